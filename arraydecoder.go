@@ -0,0 +1,295 @@
+package elemental
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// MakeStreamArrayDecoder returns a function that decodes the elements of an array of objects
+// of the given Identity, like the body of a retrieve-many response, one at a time from the given
+// reader using the given encoding. The objects are created using the given ModelManager.
+//
+// Only the current element is held in memory, so it can be used to process very large arrays.
+// JSON, MSGPACK and CBOR arrays are supported, and a null is decoded as an empty array.
+//
+// The returned function can be called until it returns an io.EOF error, indicating the array
+// is over. As for MakeStreamDecoder, the returned dispose function will be called automatically
+// when the decoding is over, but should always be called, in a defer for example.
+func MakeStreamArrayDecoder(encoding EncodingType, identity Identity, manager ModelManager, reader io.Reader) (func() (Identifiable, error), func()) {
+
+	_, encoding = codecForEncoding(encoding)
+
+	// The codecs read the elements byte by byte from a
+	// io.ByteScanner, so they can share the reader with
+	// the functions reading the array delimiters.
+	r := bufio.NewReader(reader)
+
+	var next func() (bool, error)
+	switch encoding {
+	case EncodingTypeJSON:
+		next = jsonArrayElements(r)
+	case EncodingTypeMSGPACK:
+		next = msgpackArrayElements(r)
+	case EncodingTypeCBOR:
+		next = cborArrayElements(r)
+	default:
+		return func() (Identifiable, error) {
+			return nil, fmt.Errorf("unable to decode %s: array streaming is not supported", encoding)
+		}, func() {}
+	}
+
+	decode, dispose := MakeStreamDecoder(encoding, r)
+
+	var done bool
+
+	return func() (Identifiable, error) {
+
+		if done {
+			return nil, io.EOF
+		}
+
+		more, err := next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("unable to decode %s: %s", encoding, err)
+		}
+
+		if !more {
+			done = true
+			dispose()
+			return nil, io.EOF
+		}
+
+		obj := manager.Identifiable(identity)
+		if obj == nil {
+			return nil, fmt.Errorf("unable to decode %s: unknown identity %s", encoding, identity.Name)
+		}
+
+		if err := decode(obj); err != nil {
+			if err == io.EOF {
+				return nil, fmt.Errorf("unable to decode %s: %s", encoding, io.ErrUnexpectedEOF)
+			}
+			return nil, err
+		}
+
+		return obj, nil
+	}, dispose
+}
+
+// jsonArrayElements returns a function reading the delimiters of a JSON array
+// and returning true when an element follows.
+func jsonArrayElements(r *bufio.Reader) func() (bool, error) {
+
+	var started bool
+
+	return func() (bool, error) {
+
+		b, err := readNonSpaceByte(r)
+		if err != nil {
+			return false, err
+		}
+
+		if !started {
+
+			if b == 'n' {
+				return false, readJSONNull(r)
+			}
+
+			if b != '[' {
+				return false, fmt.Errorf("expected '[', got '%c'", b)
+			}
+			started = true
+
+			if b, err = readNonSpaceByte(r); err != nil {
+				return false, err
+			}
+			if b == ']' {
+				return false, nil
+			}
+
+			return true, r.UnreadByte()
+		}
+
+		switch b {
+		case ',':
+			return true, nil
+		case ']':
+			return false, nil
+		default:
+			return false, fmt.Errorf("expected ',' or ']', got '%c'", b)
+		}
+	}
+}
+
+// readJSONNull reads the rest of a JSON null, once its first byte has been read.
+func readJSONNull(r *bufio.Reader) error {
+
+	data := make([]byte, 3)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return err
+	}
+
+	if string(data) != "ull" {
+		return fmt.Errorf("expected '[' or null, got 'n%s'", data)
+	}
+
+	return nil
+}
+
+func readNonSpaceByte(r *bufio.Reader) (byte, error) {
+
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+
+		switch b {
+		case ' ', '\t', '\n', '\r':
+		default:
+			return b, nil
+		}
+	}
+}
+
+// msgpackArrayElements returns a function reading the header of a msgpack
+// array and returning true as long as elements follow.
+func msgpackArrayElements(r *bufio.Reader) func() (bool, error) {
+
+	remaining := -1
+
+	return func() (bool, error) {
+
+		if remaining < 0 {
+
+			b, err := r.ReadByte()
+			if err != nil {
+				return false, err
+			}
+
+			switch {
+			case b&0xf0 == 0x90:
+				remaining = int(b & 0x0f)
+			case b == 0xdc:
+				n, err := readUint(r, 2)
+				if err != nil {
+					return false, err
+				}
+				remaining = int(n)
+			case b == 0xdd:
+				n, err := readArrayLength(r, 4)
+				if err != nil {
+					return false, err
+				}
+				remaining = n
+			case b == 0xc0:
+				remaining = 0
+			default:
+				return false, fmt.Errorf("expected an array, got descriptor 0x%x", b)
+			}
+		}
+
+		if remaining == 0 {
+			return false, nil
+		}
+
+		remaining--
+
+		return true, nil
+	}
+}
+
+// cborArrayElements returns a function reading the header of a CBOR array,
+// or the break of an indefinite length one, and returning true as long
+// as elements follow.
+func cborArrayElements(r *bufio.Reader) func() (bool, error) {
+
+	remaining := -1
+	var indefinite bool
+
+	return func() (bool, error) {
+
+		if remaining < 0 && !indefinite {
+
+			b, err := r.ReadByte()
+			if err != nil {
+				return false, err
+			}
+
+			switch {
+			case b >= 0x80 && b <= 0x97:
+				remaining = int(b - 0x80)
+			case b >= 0x98 && b <= 0x9b:
+				n, err := readArrayLength(r, 1<<(b-0x98))
+				if err != nil {
+					return false, err
+				}
+				remaining = n
+			case b == 0x9f:
+				indefinite = true
+			case b == 0xf6:
+				remaining = 0
+			default:
+				return false, fmt.Errorf("expected an array, got descriptor 0x%x", b)
+			}
+		}
+
+		if indefinite {
+
+			b, err := r.ReadByte()
+			if err != nil {
+				return false, err
+			}
+
+			if b == 0xff {
+				return false, nil
+			}
+
+			return true, r.UnreadByte()
+		}
+
+		if remaining == 0 {
+			return false, nil
+		}
+
+		remaining--
+
+		return true, nil
+	}
+}
+
+// maxStreamArrayLength is the maximum length of a streamed array, above
+// which the length cannot be held by an int on all platforms.
+const maxStreamArrayLength = math.MaxInt32
+
+// readArrayLength reads the length of an array encoded on the given number of
+// bytes, and returns an error if it is greater than maxStreamArrayLength.
+func readArrayLength(r *bufio.Reader, size int) (int, error) {
+
+	n, err := readUint(r, size)
+	if err != nil {
+		return 0, err
+	}
+
+	if n > maxStreamArrayLength {
+		return 0, fmt.Errorf("array length %d is greater than the maximum of %d", n, maxStreamArrayLength)
+	}
+
+	return int(n), nil
+}
+
+func readUint(r *bufio.Reader, size int) (uint64, error) {
+
+	data := make([]byte, 8)
+	if _, err := io.ReadFull(r, data[8-size:]); err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint64(data), nil
+}
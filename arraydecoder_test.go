@@ -0,0 +1,223 @@
+package elemental
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMakeStreamArrayDecoder(t *testing.T) {
+
+	lists := ListsList{}
+	for i := 0; i < 1000; i++ {
+		lists = append(lists, &List{ID: fmt.Sprintf("%d", i), Name: fmt.Sprintf("list-%d", i)})
+	}
+
+	for _, encoding := range []EncodingType{EncodingTypeJSON, EncodingTypeMSGPACK, EncodingTypeCBOR} {
+
+		for _, size := range []int{0, 1, 15, 16, 1000} {
+
+			t.Run(fmt.Sprintf("%s with %d elements", encoding, size), func(t *testing.T) {
+
+				data, err := Encode(encoding, lists[:size])
+				if err != nil {
+					t.Fatalf("unable to encode: %s", err)
+				}
+
+				decode, dispose := MakeStreamArrayDecoder(encoding, ListIdentity, Manager(), bytes.NewReader(data))
+				defer dispose()
+
+				var n int
+				for {
+					obj, err := decode()
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						t.Fatalf("unable to decode element %d: %s", n, err)
+					}
+
+					l, ok := obj.(*List)
+					if !ok {
+						t.Fatalf("unexpected decoded object: %T", obj)
+					}
+					if l.ID != lists[n].ID || l.Name != lists[n].Name {
+						t.Errorf("unexpected decoded element %d: %s %s", n, l.ID, l.Name)
+					}
+					n++
+				}
+
+				if n != size {
+					t.Errorf("expected %d elements, got %d", size, n)
+				}
+
+				if _, err := decode(); err != io.EOF {
+					t.Errorf("expected io.EOF once the array is over, got %v", err)
+				}
+			})
+		}
+	}
+
+	t.Run("json with spaces", func(t *testing.T) {
+
+		decode, dispose := MakeStreamArrayDecoder(EncodingTypeJSON, ListIdentity, Manager(), strings.NewReader(" \n[ {\"name\": \"a\"} ,\n\t{\"name\": \"b\"}\n ] "))
+		defer dispose()
+
+		var names []string
+		for {
+			obj, err := decode()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("unable to decode: %s", err)
+			}
+			names = append(names, obj.(*List).Name)
+		}
+
+		if len(names) != 2 || names[0] != "a" || names[1] != "b" {
+			t.Errorf("unexpected decoded elements: %v", names)
+		}
+	})
+
+	t.Run("cbor indefinite length array", func(t *testing.T) {
+
+		a, _ := Encode(EncodingTypeCBOR, &List{Name: "a"})
+		b, _ := Encode(EncodingTypeCBOR, &List{Name: "b"})
+
+		data := append([]byte{0x9f}, a...)
+		data = append(data, b...)
+		data = append(data, 0xff)
+
+		decode, dispose := MakeStreamArrayDecoder(EncodingTypeCBOR, ListIdentity, Manager(), bytes.NewReader(data))
+		defer dispose()
+
+		var names []string
+		for {
+			obj, err := decode()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("unable to decode: %s", err)
+			}
+			names = append(names, obj.(*List).Name)
+		}
+
+		if len(names) != 2 || names[0] != "a" || names[1] != "b" {
+			t.Errorf("unexpected decoded elements: %v", names)
+		}
+	})
+
+	for encoding, data := range map[EncodingType][]byte{
+		EncodingTypeJSON:    []byte(" null "),
+		EncodingTypeMSGPACK: {0xc0},
+		EncodingTypeCBOR:    {0xf6},
+	} {
+		t.Run(fmt.Sprintf("%s null", encoding), func(t *testing.T) {
+
+			decode, dispose := MakeStreamArrayDecoder(encoding, ListIdentity, Manager(), bytes.NewReader(data))
+			defer dispose()
+
+			if obj, err := decode(); err != io.EOF {
+				t.Errorf("expected io.EOF for a null array, got %v %v", obj, err)
+			}
+		})
+	}
+
+	errorCases := map[string]struct {
+		encoding EncodingType
+		identity Identity
+		data     []byte
+		err      string
+	}{
+		"json not an array": {
+			encoding: EncodingTypeJSON,
+			identity: ListIdentity,
+			data:     []byte(`{"name": "a"}`),
+			err:      "unable to decode application/json: expected '[', got '{'",
+		},
+		"json missing separator": {
+			encoding: EncodingTypeJSON,
+			identity: ListIdentity,
+			data:     []byte(`[{"name": "a"} {"name": "b"}]`),
+			err:      "unable to decode application/json: expected ',' or ']', got '{'",
+		},
+		"json truncated": {
+			encoding: EncodingTypeJSON,
+			identity: ListIdentity,
+			data:     []byte(`[{"name": "a"},`),
+			err:      "unable to decode application/json: unexpected EOF",
+		},
+		"json empty": {
+			encoding: EncodingTypeJSON,
+			identity: ListIdentity,
+			data:     nil,
+			err:      "unable to decode application/json: unexpected EOF",
+		},
+		"json invalid null": {
+			encoding: EncodingTypeJSON,
+			identity: ListIdentity,
+			data:     []byte(`nul]`),
+			err:      "unable to decode application/json: expected '[' or null, got 'nul]'",
+		},
+		"msgpack array too long": {
+			encoding: EncodingTypeMSGPACK,
+			identity: ListIdentity,
+			data:     []byte{0xdd, 0xff, 0xff, 0xff, 0xff},
+			err:      "unable to decode application/msgpack: array length 4294967295 is greater than the maximum of 2147483647",
+		},
+		"cbor array too long": {
+			encoding: EncodingTypeCBOR,
+			identity: ListIdentity,
+			data:     []byte{0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			err:      "unable to decode application/cbor: array length 18446744073709551615 is greater than the maximum of 2147483647",
+		},
+		"msgpack not an array": {
+			encoding: EncodingTypeMSGPACK,
+			identity: ListIdentity,
+			data:     []byte{0x81},
+			err:      "unable to decode application/msgpack: expected an array, got descriptor 0x81",
+		},
+		"msgpack truncated": {
+			encoding: EncodingTypeMSGPACK,
+			identity: ListIdentity,
+			data:     []byte{0x92, 0x80},
+			err:      "unable to decode application/msgpack: unexpected EOF",
+		},
+		"unknown identity": {
+			encoding: EncodingTypeJSON,
+			identity: Identity{Name: "nope"},
+			data:     []byte(`[{}]`),
+			err:      "unable to decode application/json: unknown identity nope",
+		},
+		"unsupported encoding": {
+			encoding: EncodingTypeProtobuf,
+			identity: ListIdentity,
+			data:     []byte{},
+			err:      "unable to decode application/x-protobuf: array streaming is not supported",
+		},
+	}
+
+	// the protobuf codec is registered, but does not support arrays.
+	registerProtobufCodec(t)
+
+	for name, tc := range errorCases {
+		t.Run(name, func(t *testing.T) {
+
+			decode, dispose := MakeStreamArrayDecoder(tc.encoding, tc.identity, Manager(), bytes.NewReader(tc.data))
+			defer dispose()
+
+			var err error
+			for err == nil {
+				_, err = decode()
+			}
+
+			if err.Error() != tc.err {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
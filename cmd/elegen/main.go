@@ -48,6 +48,8 @@ func main() {
 				OutputDir:   out,
 			}
 			return genopenapi3.GeneratorFunc(sets, cfg)
+		case "protobuf":
+			return genProtobuf(sets, out, publicMode)
 		case "", "elemental":
 			return genElemental(sets, out, publicMode)
 		default:
@@ -83,7 +85,7 @@ func main() {
 		"gen-type",
 		"g",
 		"elemental",
-		"The desired type of what needs to be generated. Possible choices are: [elemental openapi3]",
+		"The desired type of what needs to be generated. Possible choices are: [elemental openapi3 protobuf]",
 	)
 
 	if err := cmd.Execute(); err != nil {
@@ -113,3 +115,13 @@ func genElemental(sets []spec.SpecificationSet, out string, publicMode bool) err
 
 	return g.Wait()
 }
+
+func genProtobuf(sets []spec.SpecificationSet, out string, publicMode bool) error {
+
+	outFolder := path.Join(out, "protobuf")
+	if err := os.MkdirAll(outFolder, 0750); err != nil && !os.IsExist(err) {
+		return err
+	}
+
+	return writeProtobuf(sets[0], outFolder, publicMode)
+}
@@ -20,6 +20,7 @@ import (
 	"sort"
 	"strings"
 	"text/template"
+	"unicode"
 
 	"go.aporeto.io/regolithe/spec"
 	"golang.org/x/text/cases"
@@ -186,12 +187,13 @@ func attrToField(set spec.SpecificationSet, shadow bool, attr *spec.Attribute) s
 	convertedType := attrToType(set, shadow, attr)
 
 	return fmt.Sprintf(
-		"%s\n    %s %s `json:\"%s\" msgpack:\"%s\" bson:\"%s\" mapstructure:\"%s,omitempty\"`\n\n",
+		"%s\n    %s %s `json:\"%s\" msgpack:\"%s\" cbor:\"%s\" bson:\"%s\" mapstructure:\"%s,omitempty\"`\n\n",
 		strings.Join(descLines, "\n"),
 		attr.ConvertedName,
 		convertedType,
 		json,
 		msgpack,
+		msgpack,
 		bson,
 		strings.Replace(json, ",omitempty", "", 1),
 	)
@@ -542,3 +544,370 @@ func modelCommentFlags(exts map[string]any) []string {
 
 	return out
 }
+
+// A protobufField represents an attribute with its protobuf field number.
+type protobufField struct {
+	Number    int
+	Attribute *spec.Attribute
+}
+
+// A protobufEnum represents an enum with its protobuf values.
+type protobufEnum struct {
+	Name   string
+	Values []protobufEnumValue
+}
+
+// A protobufEnumValue represents a protobuf enum value.
+type protobufEnumValue struct {
+	Name   string
+	Number int
+}
+
+// protobufFields returns the exposed attributes with their protobuf field numbers,
+// sorted by name. The number can be set using the 'protobuf_field' extension. Otherwise,
+// the attributes get the next available number in name order.
+func protobufFields(s spec.Specification, version string) ([]protobufField, error) {
+
+	var fields []protobufField // nolint
+	used := map[int]string{}
+
+	for _, attr := range sortAttributes(s.Attributes(version)) {
+
+		if !attr.Exposed {
+			continue
+		}
+
+		n, err := protobufFieldExtension(attr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attribute '%s' of '%s': %s", attr.Name, s.Model().RestName, err)
+		}
+
+		if n != 0 {
+			if other, ok := used[n]; ok {
+				return nil, fmt.Errorf("invalid protobuf field number for attribute '%s' of '%s': %d is already used by '%s'", attr.Name, s.Model().RestName, n, other)
+			}
+			used[n] = attr.Name
+		}
+
+		fields = append(fields, protobufField{Number: n, Attribute: attr})
+	}
+
+	next := 1
+	for i := range fields {
+
+		if fields[i].Number != 0 {
+			continue
+		}
+
+		for used[next] != "" || (next >= 19000 && next <= 19999) {
+			next++
+		}
+
+		fields[i].Number = next
+		used[next] = fields[i].Attribute.Name
+	}
+
+	return fields, nil
+}
+
+func protobufFieldExtension(attr *spec.Attribute) (int, error) {
+
+	item, ok := attr.Extensions["protobuf_field"]
+	if !ok {
+		return 0, nil
+	}
+
+	var n int
+	switch v := item.(type) {
+	case int:
+		n = v
+	case int64:
+		n = int(v)
+	case uint64:
+		n = int(v)
+	case float64:
+		n = int(v)
+	default:
+		return 0, fmt.Errorf("expected int for extension attribute 'protobuf_field', got %T instead", item)
+	}
+
+	if n < 1 || n > 536870911 || (n >= 19000 && n <= 19999) {
+		return 0, fmt.Errorf("invalid protobuf field number: %d", n)
+	}
+
+	return n, nil
+}
+
+// protobufEnums returns the enums of the given specification. The values are numbered
+// in the order of the allowed choices, 0 being reserved for the unspecified value.
+func protobufEnums(s spec.Specification, version string) []protobufEnum {
+
+	var enums []protobufEnum // nolint
+
+	for _, enum := range buildEnums(s, version) {
+		for _, attr := range s.Attributes(version) {
+
+			if attr.Name != enum.AttributeName {
+				continue
+			}
+
+			values := []protobufEnumValue{{Name: protobufEnumValueName(attr.ConvertedName, "Unspecified")}}
+			for i, v := range attr.AllowedChoices {
+				values = append(values, protobufEnumValue{Name: protobufEnumValueName(attr.ConvertedName, v), Number: i + 1})
+			}
+
+			enums = append(enums, protobufEnum{
+				Name:   attr.ConvertedName,
+				Values: values,
+			})
+		}
+	}
+
+	return enums
+}
+
+// protobufEnumValueName returns the name of the given value of the given
+// enum in the protobuf upper snake case convention.
+func protobufEnumValueName(enum string, value string) string {
+
+	var b strings.Builder
+
+	runes := []rune(enum)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && (!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+
+	b.WriteByte('_')
+
+	for _, r := range value {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+
+	return strings.ToUpper(b.String())
+}
+
+// protobufScalarTypes maps the scalar attribute types to their protobuf type
+// and the name of the elemental.ProtobufEncoder and elemental.ProtobufDecoder methods.
+var protobufScalarTypes = map[spec.AttributeType][2]string{
+	spec.AttributeTypeString: {"string", "String"},
+	spec.AttributeTypeInt:    {"int64", "Int"},
+	spec.AttributeTypeFloat:  {"double", "Float"},
+	spec.AttributeTypeBool:   {"bool", "Bool"},
+	spec.AttributeTypeTime:   {"google.protobuf.Timestamp", "Time"},
+}
+
+// protobufUsesTimestamp returns true if one of the messages generated for the given
+// set has a google.protobuf.Timestamp field.
+func protobufUsesTimestamp(set spec.SpecificationSet, publicMode bool) bool {
+
+	for _, s := range set.Specifications() {
+
+		if publicMode && s.Model().Private {
+			continue
+		}
+
+		for _, attr := range s.Attributes(s.LatestAttributesVersion()) {
+
+			if !attr.Exposed {
+				continue
+			}
+
+			if attr.Type == spec.AttributeTypeTime || (attr.Type == spec.AttributeTypeList && attr.SubType == string(spec.AttributeTypeTime)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// attrToProtobufType returns the protobuf type of the given attribute. The types
+// that have no protobuf equivalent are encoded in JSON in a bytes field.
+func attrToProtobufType(set spec.SpecificationSet, attr *spec.Attribute) string {
+
+	switch attr.Type {
+
+	case spec.AttributeTypeEnum:
+		return attr.ConvertedName
+
+	case spec.AttributeTypeRef:
+		return set.Specification(attr.SubType).Model().EntityName
+
+	case spec.AttributeTypeRefList:
+		return "repeated " + set.Specification(attr.SubType).Model().EntityName
+
+	case spec.AttributeTypeList:
+		t, ok := protobufScalarTypes[spec.AttributeType(attr.SubType)]
+		if !ok {
+			return "bytes"
+		}
+		return "repeated " + t[0]
+	}
+
+	if t, ok := protobufScalarTypes[attr.Type]; ok {
+		return t[0]
+	}
+
+	return "bytes"
+}
+
+// attrToProtobufOptions returns the protobuf field options of the given attribute,
+// to be written after the field number.
+func attrToProtobufOptions(attr *spec.Attribute) string {
+
+	if attr.Type != spec.AttributeTypeList {
+		return ""
+	}
+
+	switch spec.AttributeType(attr.SubType) {
+	case spec.AttributeTypeInt, spec.AttributeTypeFloat, spec.AttributeTypeBool:
+		// Scalar numeric fields are packed by default in proto3, which is not supported by elemental.ProtobufDecoder.
+		return " [packed = false]"
+	}
+
+	return ""
+}
+
+// attrToProtobufEncoder returns the code writing the given attribute in the elemental.ProtobufEncoder e.
+func attrToProtobufEncoder(set spec.SpecificationSet, s spec.Specification, attr *spec.Attribute, number int) string {
+
+	field := "o." + attr.ConvertedName
+	pointer := attr.Extensions["refMode"] == "pointer"
+
+	switch attr.Type {
+
+	case spec.AttributeTypeEnum:
+		out := fmt.Sprintf("switch %s {\n", field)
+		for i, v := range attr.AllowedChoices {
+			out += fmt.Sprintf("case %s%s%s:\ne.EncodeInt(%d, %d)\n", s.Model().EntityName, attr.ConvertedName, v, number, i+1)
+		}
+		return out + fmt.Sprintf("default:\ne.EncodeInt(%d, 0)\n}", number)
+
+	case spec.AttributeTypeRef:
+		if pointer {
+			return fmt.Sprintf("if %s != nil {\ne.EncodeMessage(%d, %s)\n}", field, number, field)
+		}
+		return fmt.Sprintf("e.EncodeMessage(%d, &%s)", number, field)
+
+	case spec.AttributeTypeRefList:
+		if !set.Specification(attr.SubType).Model().Detached && pointer {
+			return fmt.Sprintf("if %s != nil {\nfor _, v := range *%s {\ne.EncodeMessage(%d, v)\n}\n}", field, field, number)
+		}
+		if set.Specification(attr.SubType).Model().Detached && !pointer {
+			return fmt.Sprintf("for i := range %s {\ne.EncodeMessage(%d, &%s[i])\n}", field, number, field)
+		}
+		return fmt.Sprintf("for _, v := range %s {\nif v != nil {\ne.EncodeMessage(%d, v)\n}\n}", field, number)
+
+	case spec.AttributeTypeList:
+		t, ok := protobufScalarTypes[spec.AttributeType(attr.SubType)]
+		if !ok {
+			break
+		}
+		if attr.SubType == string(spec.AttributeTypeInt) {
+			return fmt.Sprintf("for _, v := range %s {\ne.EncodeInt(%d, int64(v))\n}", field, number)
+		}
+		return fmt.Sprintf("for _, v := range %s {\ne.Encode%s(%d, v)\n}", field, t[1], number)
+
+	case spec.AttributeTypeInt:
+		return fmt.Sprintf("e.EncodeInt(%d, int64(%s))", number, field)
+	}
+
+	if t, ok := protobufScalarTypes[attr.Type]; ok && attr.Type != spec.AttributeTypeInt {
+		return fmt.Sprintf("e.Encode%s(%d, %s)", t[1], number, field)
+	}
+
+	return fmt.Sprintf("e.EncodeJSON(%d, %s)", number, field)
+}
+
+// attrToProtobufDecoder returns the code reading the given attribute from the elemental.ProtobufDecoder d.
+func attrToProtobufDecoder(set spec.SpecificationSet, s spec.Specification, attr *spec.Attribute) string {
+
+	field := "o." + attr.ConvertedName
+	pointer := attr.Extensions["refMode"] == "pointer"
+
+	switch attr.Type {
+
+	case spec.AttributeTypeEnum:
+		out := "switch d.DecodeInt() {\n"
+		for i, v := range attr.AllowedChoices {
+			out += fmt.Sprintf("case %d:\n%s = %s%s%s\n", i+1, field, s.Model().EntityName, attr.ConvertedName, v)
+		}
+		return out + fmt.Sprintf("default:\n%s = \"\"\n}", field)
+
+	case spec.AttributeTypeRef:
+		if pointer {
+			return fmt.Sprintf("%s = New%s()\nd.DecodeMessage(%s)", field, set.Specification(attr.SubType).Model().EntityName, field)
+		}
+		return fmt.Sprintf("d.DecodeMessage(&%s)", field)
+
+	case spec.AttributeTypeRefList:
+		remoteSpec := set.Specification(attr.SubType)
+		out := fmt.Sprintf("v := New%s()\nd.DecodeMessage(v)\n", remoteSpec.Model().EntityName)
+		if !remoteSpec.Model().Detached && pointer {
+			return out + fmt.Sprintf("if %s == nil {\n%s = &%sList{}\n}\n*%s = append(*%s, v)", field, field, remoteSpec.Model().EntityNamePlural, field, field)
+		}
+		if remoteSpec.Model().Detached && !pointer {
+			return out + fmt.Sprintf("%s = append(%s, *v)", field, field)
+		}
+		return out + fmt.Sprintf("%s = append(%s, v)", field, field)
+
+	case spec.AttributeTypeList:
+		t, ok := protobufScalarTypes[spec.AttributeType(attr.SubType)]
+		if !ok {
+			break
+		}
+		if attr.SubType == string(spec.AttributeTypeInt) {
+			return fmt.Sprintf("%s = append(%s, int(d.DecodeInt()))", field, field)
+		}
+		return fmt.Sprintf("%s = append(%s, d.Decode%s())", field, field, t[1])
+
+	case spec.AttributeTypeInt:
+		return fmt.Sprintf("%s = int(d.DecodeInt())", field)
+	}
+
+	if t, ok := protobufScalarTypes[attr.Type]; ok && attr.Type != spec.AttributeTypeInt {
+		return fmt.Sprintf("%s = d.Decode%s()", field, t[1])
+	}
+
+	return fmt.Sprintf("d.DecodeJSON(&%s)", field)
+}
+
+// attrToProtobufReset returns the code resetting the given attribute to its zero value
+// before decoding, as proto3 messages leave out the fields holding a zero value.
+func attrToProtobufReset(set spec.SpecificationSet, attr *spec.Attribute) string {
+
+	field := "o." + attr.ConvertedName
+
+	switch attr.Type {
+
+	case spec.AttributeTypeString, spec.AttributeTypeEnum:
+		return field + ` = ""`
+
+	case spec.AttributeTypeInt, spec.AttributeTypeFloat:
+		return field + " = 0"
+
+	case spec.AttributeTypeBool:
+		return field + " = false"
+
+	case spec.AttributeTypeTime:
+		return field + " = time.Time{}"
+	}
+
+	typ := attrToType(set, false, attr)
+
+	switch {
+	case strings.HasPrefix(typ, "*"), strings.HasPrefix(typ, "[]"), strings.HasPrefix(typ, "map["), typ == "any":
+		return field + " = nil"
+	case attr.Type == spec.AttributeTypeRef:
+		return field + " = " + typ + "{}"
+	default:
+		return field + " = *new(" + typ + ")"
+	}
+}
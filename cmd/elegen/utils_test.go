@@ -516,3 +516,93 @@ func Test_sortIndexes(t *testing.T) {
 		})
 	}
 }
+
+func Test_protobufEnumValueName(t *testing.T) {
+
+	testCases := map[string]struct {
+		enum     string
+		value    string
+		expected string
+	}{
+		"simple": {
+			enum:     "Action",
+			value:    "Allow",
+			expected: "ACTION_ALLOW",
+		},
+		"camel case": {
+			enum:     "ProtocolType",
+			value:    "Unspecified",
+			expected: "PROTOCOL_TYPE_UNSPECIFIED",
+		},
+		"acronym": {
+			enum:     "TLSMode",
+			value:    "Strict",
+			expected: "TLS_MODE_STRICT",
+		},
+		"invalid characters": {
+			enum:     "Mode",
+			value:    "no-verify",
+			expected: "MODE_NO_VERIFY",
+		},
+	}
+
+	for description, tc := range testCases {
+		t.Run(description, func(t *testing.T) {
+			if actual := protobufEnumValueName(tc.enum, tc.value); actual != tc.expected {
+				t.Errorf("expected: '%s'\n"+
+					"actual: '%s'\n",
+					tc.expected,
+					actual)
+			}
+		})
+	}
+}
+
+func Test_protobufFieldExtension(t *testing.T) {
+
+	testCases := map[string]struct {
+		extensions  map[string]any
+		expected    int
+		expectedErr bool
+	}{
+		"no extension": {
+			extensions: nil,
+			expected:   0,
+		},
+		"int": {
+			extensions: map[string]any{"protobuf_field": 3},
+			expected:   3,
+		},
+		"float": {
+			extensions: map[string]any{"protobuf_field": float64(4)},
+			expected:   4,
+		},
+		"not a number": {
+			extensions:  map[string]any{"protobuf_field": "3"},
+			expectedErr: true,
+		},
+		"zero": {
+			extensions:  map[string]any{"protobuf_field": 0},
+			expectedErr: true,
+		},
+		"reserved": {
+			extensions:  map[string]any{"protobuf_field": 19500},
+			expectedErr: true,
+		},
+	}
+
+	for description, tc := range testCases {
+		t.Run(description, func(t *testing.T) {
+			actual, err := protobufFieldExtension(&spec.Attribute{Extensions: tc.extensions})
+			if (err != nil) != tc.expectedErr {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if actual != tc.expected {
+				t.Errorf("expected: '%d'\n"+
+					"actual: '%d'\n",
+					tc.expected,
+					actual)
+			}
+		})
+	}
+}
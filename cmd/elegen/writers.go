@@ -50,6 +50,14 @@ var functions = template.FuncMap{
 	"sortAttributes":                  sortAttributes,
 	"sortIndexes":                     sortIndexes,
 	"modelCommentFlags":               modelCommentFlags,
+	"protobufFields":                  protobufFields,
+	"protobufEnums":                   protobufEnums,
+	"protobufUsesTimestamp":           protobufUsesTimestamp,
+	"attrToProtobufType":              attrToProtobufType,
+	"attrToProtobufOptions":           attrToProtobufOptions,
+	"attrToProtobufEncoder":           attrToProtobufEncoder,
+	"attrToProtobufDecoder":           attrToProtobufDecoder,
+	"attrToProtobufReset":             attrToProtobufReset,
 }
 
 func writeModel(set spec.SpecificationSet, name string, outFolder string, publicMode bool) error {
@@ -210,3 +218,32 @@ func writeRelationshipsRegistry(set spec.SpecificationSet, outFolder string, pub
 
 	return nil
 }
+
+func writeProtobuf(set spec.SpecificationSet, outFolder string, publicMode bool) error {
+
+	tmpl, err := makeTemplate("templates/proto.gotpl")
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	if err = tmpl.Execute(
+		&buf,
+		struct {
+			PublicMode bool
+			Set        spec.SpecificationSet
+		}{
+			PublicMode: publicMode,
+			Set:        set,
+		}); err != nil {
+		return fmt.Errorf("unable to generate protobuf definitions:%s", err)
+	}
+
+	name := set.Configuration().Name + ".proto"
+	if err := writeFile(path.Join(outFolder, name), buf.Bytes()); err != nil {
+		return fmt.Errorf("unable to write file for protobuf definitions: %s", err)
+	}
+
+	return nil
+}
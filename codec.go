@@ -0,0 +1,136 @@
+package elemental
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/ugorji/go/codec"
+)
+
+// A Codec encodes and decodes data for an EncodingType. Codecs are registered
+// using RegisterCodec, and are then used by Encode, Decode, MakeStreamEncoder,
+// MakeStreamDecoder and Convert, so by the Request, Response and Event.
+type Codec interface {
+
+	// Encode returns the encoded data of the given object.
+	Encode(obj any) ([]byte, error)
+
+	// Decode decodes the given data into the given destination.
+	Decode(data []byte, dest any) error
+
+	// NewStreamEncoder returns a function that encodes objects one after
+	// the other into the given writer, and a function that must be called
+	// once the encoding is over to release its resources.
+	NewStreamEncoder(writer io.Writer) (func(obj any) error, func())
+
+	// NewStreamDecoder returns a function that decodes objects one after
+	// the other from the given reader, and returns io.EOF once the stream
+	// is over, and a function that must be called once the decoding is over
+	// to release its resources.
+	NewStreamDecoder(reader io.Reader) (func(dest any) error, func())
+}
+
+var codecs = map[EncodingType]Codec{}
+
+// RegisterCodec registers the Codec to use for the given EncodingType, replacing the current
+// one if any. The EncodingType is then supported in Content-Type and Accept headers by
+// EncodingFromHeaders. The JSON, MSGPACK and CBOR codecs are registered by default, and the
+// Protobuf one can be registered using NewProtobufCodec.
+//
+// It must be called before encoding or decoding anything, typically in an init function.
+func RegisterCodec(encoding EncodingType, c Codec) {
+
+	if c == nil {
+		panic(fmt.Errorf("elemental: codec cannot be nil"))
+	}
+
+	codecs[encoding] = c
+}
+
+// codecForEncoding returns the Codec registered for the given encoding and the encoding
+// it handles. Unknown encodings are handled by the JSON codec.
+func codecForEncoding(encoding EncodingType) (Codec, EncodingType) {
+
+	if c, ok := codecs[encoding]; ok {
+		return c, encoding
+	}
+
+	return codecs[EncodingTypeJSON], EncodingTypeJSON
+}
+
+// hasCodec returns true if a Codec is registered for the given encoding.
+func hasCodec(encoding EncodingType) bool {
+
+	_, ok := codecs[encoding]
+	return ok
+}
+
+// ugorjiCodec is a Codec using the given ugorji handle,
+// with pools of encoders and decoders.
+type ugorjiCodec struct {
+	encoders sync.Pool
+	decoders sync.Pool
+}
+
+func newUgorjiCodec(handle codec.Handle) *ugorjiCodec {
+
+	return &ugorjiCodec{
+		encoders: sync.Pool{
+			New: func() any {
+				return codec.NewEncoder(nil, handle)
+			},
+		},
+		decoders: sync.Pool{
+			New: func() any {
+				return codec.NewDecoder(nil, handle)
+			},
+		},
+	}
+}
+
+func (c *ugorjiCodec) Encode(obj any) ([]byte, error) {
+
+	enc := c.encoders.Get().(*codec.Encoder)
+	defer c.encoders.Put(enc)
+
+	buf := bytes.NewBuffer(nil)
+	enc.Reset(buf)
+
+	if err := enc.Encode(obj); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (c *ugorjiCodec) Decode(data []byte, dest any) error {
+
+	dec := c.decoders.Get().(*codec.Decoder)
+	defer c.decoders.Put(dec)
+
+	dec.Reset(bytes.NewBuffer(data))
+
+	return dec.Decode(dest)
+}
+
+func (c *ugorjiCodec) NewStreamEncoder(writer io.Writer) (func(obj any) error, func()) {
+
+	enc := c.encoders.Get().(*codec.Encoder)
+	enc.Reset(writer)
+
+	var once sync.Once
+
+	return enc.Encode, func() { once.Do(func() { c.encoders.Put(enc) }) }
+}
+
+func (c *ugorjiCodec) NewStreamDecoder(reader io.Reader) (func(dest any) error, func()) {
+
+	dec := c.decoders.Get().(*codec.Decoder)
+	dec.Reset(reader)
+
+	var once sync.Once
+
+	return dec.Decode, func() { once.Do(func() { c.decoders.Put(dec) }) }
+}
@@ -0,0 +1,178 @@
+package elemental
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+)
+
+const testEncodingType EncodingType = "application/x-test"
+
+// testCodec is a Codec using encoding/json, and
+// counting the number of encoded objects.
+type testCodec struct {
+	encoded int
+}
+
+func (c *testCodec) Encode(obj any) ([]byte, error) {
+	c.encoded++
+	return json.Marshal(obj)
+}
+
+func (c *testCodec) Decode(data []byte, dest any) error {
+	return json.Unmarshal(data, dest)
+}
+
+func (c *testCodec) NewStreamEncoder(writer io.Writer) (func(obj any) error, func()) {
+	enc := json.NewEncoder(writer)
+	return func(obj any) error { c.encoded++; return enc.Encode(obj) }, func() {}
+}
+
+func (c *testCodec) NewStreamDecoder(reader io.Reader) (func(dest any) error, func()) {
+	return json.NewDecoder(reader).Decode, func() {}
+}
+
+func TestRegisterCodec(t *testing.T) {
+
+	c := &testCodec{}
+	RegisterCodec(testEncodingType, c)
+	defer delete(codecs, testEncodingType)
+
+	t.Run("encode and decode", func(t *testing.T) {
+
+		data, err := Encode(testEncodingType, &List{ID: "1", Name: "hello"})
+		if err != nil {
+			t.Fatalf("unable to encode: %s", err)
+		}
+		if c.encoded != 1 {
+			t.Errorf("expected the codec to be used")
+		}
+
+		l := &List{}
+		if err := Decode(testEncodingType, data, l); err != nil {
+			t.Fatalf("unable to decode: %s", err)
+		}
+		if l.ID != "1" || l.Name != "hello" {
+			t.Errorf("unexpected decoded object: %#v", l)
+		}
+
+		if err := Decode(testEncodingType, []byte("{"), l); err == nil || err.Error() != "unable to decode application/x-test: unexpected end of JSON input" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("stream", func(t *testing.T) {
+
+		buf := bytes.NewBuffer(nil)
+
+		encode, dispose := MakeStreamEncoder(testEncodingType, buf)
+		defer dispose()
+
+		for _, name := range []string{"a", "b"} {
+			if err := encode(&List{Name: name}); err != nil {
+				t.Fatalf("unable to encode: %s", err)
+			}
+		}
+
+		decode, dispose := MakeStreamDecoder(testEncodingType, buf)
+		defer dispose()
+
+		var names []string
+		for {
+			l := &List{}
+			err := decode(l)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("unable to decode: %s", err)
+			}
+			names = append(names, l.Name)
+		}
+
+		if len(names) != 2 || names[0] != "a" || names[1] != "b" {
+			t.Errorf("unexpected decoded objects: %v", names)
+		}
+	})
+
+	t.Run("convert", func(t *testing.T) {
+
+		data, err := Encode(EncodingTypeMSGPACK, &List{Name: "hello"})
+		if err != nil {
+			t.Fatalf("unable to encode: %s", err)
+		}
+
+		converted, err := Convert(EncodingTypeMSGPACK, testEncodingType, data)
+		if err != nil {
+			t.Fatalf("unable to convert: %s", err)
+		}
+
+		l := &List{}
+		if err := json.Unmarshal(converted, l); err != nil || l.Name != "hello" {
+			t.Errorf("unexpected converted data: %s (%v)", converted, err)
+		}
+	})
+
+	t.Run("headers", func(t *testing.T) {
+
+		read, write, err := EncodingFromHeaders(http.Header{
+			"Content-Type": []string{string(testEncodingType) + "; charset=utf-8"},
+			"Accept":       []string{"application/toto, " + string(testEncodingType)},
+		})
+		if err != nil {
+			t.Fatalf("unable to get the encodings: %s", err)
+		}
+		if read != testEncodingType || write != testEncodingType {
+			t.Errorf("unexpected encodings: %s, %s", read, write)
+		}
+	})
+
+	t.Run("request and response", func(t *testing.T) {
+
+		req := NewRequest()
+		req.ContentType = testEncodingType
+		req.Accept = testEncodingType
+		req.Data = []byte(`{"name": "hello"}`)
+
+		l := &List{}
+		if err := req.Decode(l); err != nil || l.Name != "hello" {
+			t.Errorf("unable to decode the request: %v", err)
+		}
+
+		resp := NewResponse(req)
+		if err := resp.Encode(l); err != nil {
+			t.Fatalf("unable to encode the response: %s", err)
+		}
+		if !bytes.Contains(resp.Data, []byte(`"name":"hello"`)) {
+			t.Errorf("unexpected response data: %s", resp.Data)
+		}
+	})
+
+	t.Run("event", func(t *testing.T) {
+
+		e := NewEventWithEncoding(EventCreate, &List{Name: "hello"}, testEncodingType)
+		if e.RawData == nil || e.JSONData != nil {
+			t.Fatalf("expected the entity to be stored as raw data")
+		}
+
+		l := &List{}
+		if err := e.Decode(l); err != nil || l.Name != "hello" {
+			t.Errorf("unable to decode the event: %v", err)
+		}
+
+		for _, encoding := range []EncodingType{EncodingTypeMSGPACK, EncodingTypeJSON, testEncodingType} {
+
+			if err := e.Convert(encoding); err != nil {
+				t.Fatalf("unable to convert the event to %s: %s", encoding, err)
+			}
+
+			// the conversion does not keep the types, like dates.
+			m := map[string]any{}
+			if err := e.Decode(&m); err != nil || m["name"] != "hello" {
+				t.Errorf("unable to decode the event converted to %s: %v", encoding, err)
+			}
+		}
+	})
+}
@@ -81,42 +81,75 @@ func (o ListsList) Version() int {
 	return 1
 }
 
+// MarshalProtobuf implements the ProtobufMarshaler interface.
+func (o ListsList) MarshalProtobuf() ([]byte, error) {
+
+	e := NewProtobufEncoder()
+	for _, obj := range o {
+		if obj != nil {
+			e.EncodeMessage(1, obj)
+		}
+	}
+
+	return e.Bytes()
+}
+
+// UnmarshalProtobuf implements the ProtobufUnmarshaler interface.
+func (o *ListsList) UnmarshalProtobuf(data []byte) error {
+
+	*o = ListsList{}
+
+	d := NewProtobufDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		case 1:
+			obj := NewList()
+			d.DecodeMessage(obj)
+			*o = append(*o, obj)
+		default:
+			d.Skip()
+		}
+	}
+
+	return d.Err()
+}
+
 // List represents the model of a list
 type List struct {
 	// The identifier.
-	ID string `json:"ID" msgpack:"ID" bson:"-" mapstructure:"ID,omitempty"`
+	ID string `json:"ID" msgpack:"ID" cbor:"ID" bson:"-" mapstructure:"ID,omitempty"`
 
 	// This attribute is creation only.
-	CreationOnly string `json:"creationOnly" msgpack:"creationOnly" bson:"creationonly" mapstructure:"creationOnly,omitempty"`
+	CreationOnly string `json:"creationOnly" msgpack:"creationOnly" cbor:"creationOnly" bson:"creationonly" mapstructure:"creationOnly,omitempty"`
 
 	// The date.
-	Date time.Time `json:"date" msgpack:"date" bson:"date" mapstructure:"date,omitempty"`
+	Date time.Time `json:"date" msgpack:"date" cbor:"date" bson:"date" mapstructure:"date,omitempty"`
 
 	// The description.
-	Description string `json:"description" msgpack:"description" bson:"description" mapstructure:"description,omitempty"`
+	Description string `json:"description" msgpack:"description" cbor:"description" bson:"description" mapstructure:"description,omitempty"`
 
 	// The name.
-	Name string `json:"name" msgpack:"name" bson:"name" mapstructure:"name,omitempty"`
+	Name string `json:"name" msgpack:"name" cbor:"name" bson:"name" mapstructure:"name,omitempty"`
 
 	// The identifier of the parent of the object.
-	ParentID string `json:"parentID" msgpack:"parentID" bson:"parentid" mapstructure:"parentID,omitempty"`
+	ParentID string `json:"parentID" msgpack:"parentID" cbor:"parentID" bson:"parentid" mapstructure:"parentID,omitempty"`
 
 	// The type of the parent of the object.
-	ParentType string `json:"parentType" msgpack:"parentType" bson:"parenttype" mapstructure:"parentType,omitempty"`
+	ParentType string `json:"parentType" msgpack:"parentType" cbor:"parentType" bson:"parenttype" mapstructure:"parentType,omitempty"`
 
 	// This attribute is readonly.
-	ReadOnly string `json:"readOnly" msgpack:"readOnly" bson:"readonly" mapstructure:"readOnly,omitempty"`
+	ReadOnly string `json:"readOnly" msgpack:"readOnly" cbor:"readOnly" bson:"readonly" mapstructure:"readOnly,omitempty"`
 
 	// This attribute is secret.
-	Secret string `json:"secret" msgpack:"secret" bson:"secret" mapstructure:"secret,omitempty"`
+	Secret string `json:"secret" msgpack:"secret" cbor:"secret" bson:"secret" mapstructure:"secret,omitempty"`
 
 	// this is a slice.
-	Slice []string `json:"slice" msgpack:"slice" bson:"slice" mapstructure:"slice,omitempty"`
+	Slice []string `json:"slice" msgpack:"slice" cbor:"slice" bson:"slice" mapstructure:"slice,omitempty"`
 
 	// This attribute is not exposed.
-	Unexposed string `json:"-" msgpack:"-" bson:"unexposed" mapstructure:"-,omitempty"`
+	Unexposed string `json:"-" msgpack:"-" cbor:"-" bson:"unexposed" mapstructure:"-,omitempty"`
 
-	ModelVersion int `json:"-" msgpack:"-" bson:"_modelversion"`
+	ModelVersion int `json:"-" msgpack:"-" cbor:"-" bson:"_modelversion"`
 }
 
 // NewList returns a new *List
@@ -205,6 +238,71 @@ func (o *List) UnmarshalBSON(raw []byte) error {
 	return nil
 }
 
+// MarshalProtobuf implements the ProtobufMarshaler interface.
+func (o *List) MarshalProtobuf() ([]byte, error) {
+
+	e := NewProtobufEncoder()
+	e.EncodeString(1, o.ID)
+	e.EncodeString(2, o.CreationOnly)
+	e.EncodeTime(3, o.Date)
+	e.EncodeString(4, o.Description)
+	e.EncodeString(5, o.Name)
+	e.EncodeString(6, o.ParentID)
+	e.EncodeString(7, o.ParentType)
+	e.EncodeString(8, o.ReadOnly)
+	e.EncodeString(9, o.Secret)
+	for _, v := range o.Slice {
+		e.EncodeString(10, v)
+	}
+
+	return e.Bytes()
+}
+
+// UnmarshalProtobuf implements the ProtobufUnmarshaler interface.
+func (o *List) UnmarshalProtobuf(data []byte) error {
+
+	o.ID = ""
+	o.CreationOnly = ""
+	o.Date = time.Time{}
+	o.Description = ""
+	o.Name = ""
+	o.ParentID = ""
+	o.ParentType = ""
+	o.ReadOnly = ""
+	o.Secret = ""
+	o.Slice = nil
+
+	d := NewProtobufDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		case 1:
+			o.ID = d.DecodeString()
+		case 2:
+			o.CreationOnly = d.DecodeString()
+		case 3:
+			o.Date = d.DecodeTime()
+		case 4:
+			o.Description = d.DecodeString()
+		case 5:
+			o.Name = d.DecodeString()
+		case 6:
+			o.ParentID = d.DecodeString()
+		case 7:
+			o.ParentType = d.DecodeString()
+		case 8:
+			o.ReadOnly = d.DecodeString()
+		case 9:
+			o.Secret = d.DecodeString()
+		case 10:
+			o.Slice = append(o.Slice, d.DecodeString())
+		default:
+			d.Skip()
+		}
+	}
+
+	return d.Err()
+}
+
 // Version returns the hardcoded version of the model.
 func (o *List) Version() int {
 
@@ -801,39 +899,39 @@ func (o SparseListsList) Version() int {
 // SparseList represents the sparse version of a list.
 type SparseList struct {
 	// The identifier.
-	ID *string `json:"ID,omitempty" msgpack:"ID,omitempty" bson:"-" mapstructure:"ID,omitempty"`
+	ID *string `json:"ID,omitempty" msgpack:"ID,omitempty" cbor:"ID,omitempty" bson:"-" mapstructure:"ID,omitempty"`
 
 	// This attribute is creation only.
-	CreationOnly *string `json:"creationOnly,omitempty" msgpack:"creationOnly,omitempty" bson:"creationonly,omitempty" mapstructure:"creationOnly,omitempty"`
+	CreationOnly *string `json:"creationOnly,omitempty" msgpack:"creationOnly,omitempty" cbor:"creationOnly,omitempty" bson:"creationonly,omitempty" mapstructure:"creationOnly,omitempty"`
 
 	// The date.
-	Date *time.Time `json:"date,omitempty" msgpack:"date,omitempty" bson:"date,omitempty" mapstructure:"date,omitempty"`
+	Date *time.Time `json:"date,omitempty" msgpack:"date,omitempty" cbor:"date,omitempty" bson:"date,omitempty" mapstructure:"date,omitempty"`
 
 	// The description.
-	Description *string `json:"description,omitempty" msgpack:"description,omitempty" bson:"description,omitempty" mapstructure:"description,omitempty"`
+	Description *string `json:"description,omitempty" msgpack:"description,omitempty" cbor:"description,omitempty" bson:"description,omitempty" mapstructure:"description,omitempty"`
 
 	// The name.
-	Name *string `json:"name,omitempty" msgpack:"name,omitempty" bson:"name,omitempty" mapstructure:"name,omitempty"`
+	Name *string `json:"name,omitempty" msgpack:"name,omitempty" cbor:"name,omitempty" bson:"name,omitempty" mapstructure:"name,omitempty"`
 
 	// The identifier of the parent of the object.
-	ParentID *string `json:"parentID,omitempty" msgpack:"parentID,omitempty" bson:"parentid,omitempty" mapstructure:"parentID,omitempty"`
+	ParentID *string `json:"parentID,omitempty" msgpack:"parentID,omitempty" cbor:"parentID,omitempty" bson:"parentid,omitempty" mapstructure:"parentID,omitempty"`
 
 	// The type of the parent of the object.
-	ParentType *string `json:"parentType,omitempty" msgpack:"parentType,omitempty" bson:"parenttype,omitempty" mapstructure:"parentType,omitempty"`
+	ParentType *string `json:"parentType,omitempty" msgpack:"parentType,omitempty" cbor:"parentType,omitempty" bson:"parenttype,omitempty" mapstructure:"parentType,omitempty"`
 
 	// This attribute is readonly.
-	ReadOnly *string `json:"readOnly,omitempty" msgpack:"readOnly,omitempty" bson:"readonly,omitempty" mapstructure:"readOnly,omitempty"`
+	ReadOnly *string `json:"readOnly,omitempty" msgpack:"readOnly,omitempty" cbor:"readOnly,omitempty" bson:"readonly,omitempty" mapstructure:"readOnly,omitempty"`
 
 	// This attribute is secret.
-	Secret *string `json:"secret,omitempty" msgpack:"secret,omitempty" bson:"secret,omitempty" mapstructure:"secret,omitempty"`
+	Secret *string `json:"secret,omitempty" msgpack:"secret,omitempty" cbor:"secret,omitempty" bson:"secret,omitempty" mapstructure:"secret,omitempty"`
 
 	// this is a slice.
-	Slice *[]string `json:"slice,omitempty" msgpack:"slice,omitempty" bson:"slice,omitempty" mapstructure:"slice,omitempty"`
+	Slice *[]string `json:"slice,omitempty" msgpack:"slice,omitempty" cbor:"slice,omitempty" bson:"slice,omitempty" mapstructure:"slice,omitempty"`
 
 	// This attribute is not exposed.
-	Unexposed *string `json:"-" msgpack:"-" bson:"unexposed,omitempty" mapstructure:"-,omitempty"`
+	Unexposed *string `json:"-" msgpack:"-" cbor:"-" bson:"unexposed,omitempty" mapstructure:"-,omitempty"`
 
-	ModelVersion int `json:"-" msgpack:"-" bson:"_modelversion"`
+	ModelVersion int `json:"-" msgpack:"-" cbor:"-" bson:"_modelversion"`
 }
 
 // NewSparseList returns a new  SparseList.
@@ -1164,27 +1262,60 @@ func (o TasksList) Version() int {
 	return 1
 }
 
+// MarshalProtobuf implements the ProtobufMarshaler interface.
+func (o TasksList) MarshalProtobuf() ([]byte, error) {
+
+	e := NewProtobufEncoder()
+	for _, obj := range o {
+		if obj != nil {
+			e.EncodeMessage(1, obj)
+		}
+	}
+
+	return e.Bytes()
+}
+
+// UnmarshalProtobuf implements the ProtobufUnmarshaler interface.
+func (o *TasksList) UnmarshalProtobuf(data []byte) error {
+
+	*o = TasksList{}
+
+	d := NewProtobufDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		case 1:
+			obj := NewTask()
+			d.DecodeMessage(obj)
+			*o = append(*o, obj)
+		default:
+			d.Skip()
+		}
+	}
+
+	return d.Err()
+}
+
 // Task represents the model of a task
 type Task struct {
 	// The identifier.
-	ID string `json:"ID" msgpack:"ID" bson:"-" mapstructure:"ID,omitempty"`
+	ID string `json:"ID" msgpack:"ID" cbor:"ID" bson:"-" mapstructure:"ID,omitempty"`
 
 	// The description.
-	Description string `json:"description" msgpack:"description" bson:"description" mapstructure:"description,omitempty"`
+	Description string `json:"description" msgpack:"description" cbor:"description" bson:"description" mapstructure:"description,omitempty"`
 
 	// The name.
-	Name string `json:"name" msgpack:"name" bson:"name" mapstructure:"name,omitempty"`
+	Name string `json:"name" msgpack:"name" cbor:"name" bson:"name" mapstructure:"name,omitempty"`
 
 	// The identifier of the parent of the object.
-	ParentID string `json:"parentID" msgpack:"parentID" bson:"parentid" mapstructure:"parentID,omitempty"`
+	ParentID string `json:"parentID" msgpack:"parentID" cbor:"parentID" bson:"parentid" mapstructure:"parentID,omitempty"`
 
 	// The type of the parent of the object.
-	ParentType string `json:"parentType" msgpack:"parentType" bson:"parenttype" mapstructure:"parentType,omitempty"`
+	ParentType string `json:"parentType" msgpack:"parentType" cbor:"parentType" bson:"parenttype" mapstructure:"parentType,omitempty"`
 
 	// The status of the task.
-	Status TaskStatusValue `json:"status" msgpack:"status" bson:"status" mapstructure:"status,omitempty"`
+	Status TaskStatusValue `json:"status" msgpack:"status" cbor:"status" bson:"status" mapstructure:"status,omitempty"`
 
-	ModelVersion int `json:"-" msgpack:"-" bson:"_modelversion"`
+	ModelVersion int `json:"-" msgpack:"-" cbor:"-" bson:"_modelversion"`
 }
 
 // NewTask returns a new *Task
@@ -1263,6 +1394,71 @@ func (o *Task) UnmarshalBSON(raw []byte) error {
 	return nil
 }
 
+// MarshalProtobuf implements the ProtobufMarshaler interface.
+func (o *Task) MarshalProtobuf() ([]byte, error) {
+
+	e := NewProtobufEncoder()
+	e.EncodeString(1, o.ID)
+	e.EncodeString(2, o.Description)
+	e.EncodeString(3, o.Name)
+	e.EncodeString(4, o.ParentID)
+	e.EncodeString(5, o.ParentType)
+	switch o.Status {
+	case TaskStatusDONE:
+		e.EncodeInt(6, 1)
+	case TaskStatusPROGRESS:
+		e.EncodeInt(6, 2)
+	case TaskStatusTODO:
+		e.EncodeInt(6, 3)
+	default:
+		e.EncodeInt(6, 0)
+	}
+
+	return e.Bytes()
+}
+
+// UnmarshalProtobuf implements the ProtobufUnmarshaler interface.
+func (o *Task) UnmarshalProtobuf(data []byte) error {
+
+	o.ID = ""
+	o.Description = ""
+	o.Name = ""
+	o.ParentID = ""
+	o.ParentType = ""
+	o.Status = ""
+
+	d := NewProtobufDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		case 1:
+			o.ID = d.DecodeString()
+		case 2:
+			o.Description = d.DecodeString()
+		case 3:
+			o.Name = d.DecodeString()
+		case 4:
+			o.ParentID = d.DecodeString()
+		case 5:
+			o.ParentType = d.DecodeString()
+		case 6:
+			switch d.DecodeInt() {
+			case 1:
+				o.Status = TaskStatusDONE
+			case 2:
+				o.Status = TaskStatusPROGRESS
+			case 3:
+				o.Status = TaskStatusTODO
+			default:
+				o.Status = ""
+			}
+		default:
+			d.Skip()
+		}
+	}
+
+	return d.Err()
+}
+
 // Version returns the hardcoded version of the model.
 func (o *Task) Version() int {
 
@@ -1699,24 +1895,24 @@ func (o SparseTasksList) Version() int {
 // SparseTask represents the sparse version of a task.
 type SparseTask struct {
 	// The identifier.
-	ID *string `json:"ID,omitempty" msgpack:"ID,omitempty" bson:"-" mapstructure:"ID,omitempty"`
+	ID *string `json:"ID,omitempty" msgpack:"ID,omitempty" cbor:"ID,omitempty" bson:"-" mapstructure:"ID,omitempty"`
 
 	// The description.
-	Description *string `json:"description,omitempty" msgpack:"description,omitempty" bson:"description,omitempty" mapstructure:"description,omitempty"`
+	Description *string `json:"description,omitempty" msgpack:"description,omitempty" cbor:"description,omitempty" bson:"description,omitempty" mapstructure:"description,omitempty"`
 
 	// The name.
-	Name *string `json:"name,omitempty" msgpack:"name,omitempty" bson:"name,omitempty" mapstructure:"name,omitempty"`
+	Name *string `json:"name,omitempty" msgpack:"name,omitempty" cbor:"name,omitempty" bson:"name,omitempty" mapstructure:"name,omitempty"`
 
 	// The identifier of the parent of the object.
-	ParentID *string `json:"parentID,omitempty" msgpack:"parentID,omitempty" bson:"parentid,omitempty" mapstructure:"parentID,omitempty"`
+	ParentID *string `json:"parentID,omitempty" msgpack:"parentID,omitempty" cbor:"parentID,omitempty" bson:"parentid,omitempty" mapstructure:"parentID,omitempty"`
 
 	// The type of the parent of the object.
-	ParentType *string `json:"parentType,omitempty" msgpack:"parentType,omitempty" bson:"parenttype,omitempty" mapstructure:"parentType,omitempty"`
+	ParentType *string `json:"parentType,omitempty" msgpack:"parentType,omitempty" cbor:"parentType,omitempty" bson:"parenttype,omitempty" mapstructure:"parentType,omitempty"`
 
 	// The status of the task.
-	Status *TaskStatusValue `json:"status,omitempty" msgpack:"status,omitempty" bson:"status,omitempty" mapstructure:"status,omitempty"`
+	Status *TaskStatusValue `json:"status,omitempty" msgpack:"status,omitempty" cbor:"status,omitempty" bson:"status,omitempty" mapstructure:"status,omitempty"`
 
-	ModelVersion int `json:"-" msgpack:"-" bson:"_modelversion"`
+	ModelVersion int `json:"-" msgpack:"-" cbor:"-" bson:"_modelversion"`
 }
 
 // NewSparseTask returns a new  SparseTask.
@@ -2093,30 +2289,63 @@ func (o UsersList) Version() int {
 	return 1
 }
 
+// MarshalProtobuf implements the ProtobufMarshaler interface.
+func (o UsersList) MarshalProtobuf() ([]byte, error) {
+
+	e := NewProtobufEncoder()
+	for _, obj := range o {
+		if obj != nil {
+			e.EncodeMessage(1, obj)
+		}
+	}
+
+	return e.Bytes()
+}
+
+// UnmarshalProtobuf implements the ProtobufUnmarshaler interface.
+func (o *UsersList) UnmarshalProtobuf(data []byte) error {
+
+	*o = UsersList{}
+
+	d := NewProtobufDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		case 1:
+			obj := NewUser()
+			d.DecodeMessage(obj)
+			*o = append(*o, obj)
+		default:
+			d.Skip()
+		}
+	}
+
+	return d.Err()
+}
+
 // User represents the model of a user
 type User struct {
 	// The identifier.
-	ID string `json:"ID" msgpack:"ID" bson:"-" mapstructure:"ID,omitempty"`
+	ID string `json:"ID" msgpack:"ID" cbor:"ID" bson:"-" mapstructure:"ID,omitempty"`
 
 	// the object is archived and not deleted.
-	Archived bool `json:"archived" msgpack:"archived" bson:"archived" mapstructure:"archived,omitempty"`
+	Archived bool `json:"archived" msgpack:"archived" cbor:"archived" bson:"archived" mapstructure:"archived,omitempty"`
 
 	// The first name.
-	FirstName string `json:"firstName" msgpack:"firstName" bson:"firstname" mapstructure:"firstName,omitempty"`
+	FirstName string `json:"firstName" msgpack:"firstName" cbor:"firstName" bson:"firstname" mapstructure:"firstName,omitempty"`
 
 	// The last name.
-	LastName string `json:"lastName" msgpack:"lastName" bson:"lastname" mapstructure:"lastName,omitempty"`
+	LastName string `json:"lastName" msgpack:"lastName" cbor:"lastName" bson:"lastname" mapstructure:"lastName,omitempty"`
 
 	// The identifier of the parent of the object.
-	ParentID string `json:"parentID" msgpack:"parentID" bson:"parentid" mapstructure:"parentID,omitempty"`
+	ParentID string `json:"parentID" msgpack:"parentID" cbor:"parentID" bson:"parentid" mapstructure:"parentID,omitempty"`
 
 	// The type of the parent of the object.
-	ParentType string `json:"parentType" msgpack:"parentType" bson:"parenttype" mapstructure:"parentType,omitempty"`
+	ParentType string `json:"parentType" msgpack:"parentType" cbor:"parentType" bson:"parenttype" mapstructure:"parentType,omitempty"`
 
 	// the login.
-	UserName string `json:"userName" msgpack:"userName" bson:"username" mapstructure:"userName,omitempty"`
+	UserName string `json:"userName" msgpack:"userName" cbor:"userName" bson:"username" mapstructure:"userName,omitempty"`
 
-	ModelVersion int `json:"-" msgpack:"-" bson:"_modelversion"`
+	ModelVersion int `json:"-" msgpack:"-" cbor:"-" bson:"_modelversion"`
 }
 
 // NewUser returns a new *User
@@ -2196,6 +2425,57 @@ func (o *User) UnmarshalBSON(raw []byte) error {
 	return nil
 }
 
+// MarshalProtobuf implements the ProtobufMarshaler interface.
+func (o *User) MarshalProtobuf() ([]byte, error) {
+
+	e := NewProtobufEncoder()
+	e.EncodeString(1, o.ID)
+	e.EncodeBool(2, o.Archived)
+	e.EncodeString(3, o.FirstName)
+	e.EncodeString(4, o.LastName)
+	e.EncodeString(5, o.ParentID)
+	e.EncodeString(6, o.ParentType)
+	e.EncodeString(7, o.UserName)
+
+	return e.Bytes()
+}
+
+// UnmarshalProtobuf implements the ProtobufUnmarshaler interface.
+func (o *User) UnmarshalProtobuf(data []byte) error {
+
+	o.ID = ""
+	o.Archived = false
+	o.FirstName = ""
+	o.LastName = ""
+	o.ParentID = ""
+	o.ParentType = ""
+	o.UserName = ""
+
+	d := NewProtobufDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		case 1:
+			o.ID = d.DecodeString()
+		case 2:
+			o.Archived = d.DecodeBool()
+		case 3:
+			o.FirstName = d.DecodeString()
+		case 4:
+			o.LastName = d.DecodeString()
+		case 5:
+			o.ParentID = d.DecodeString()
+		case 6:
+			o.ParentType = d.DecodeString()
+		case 7:
+			o.UserName = d.DecodeString()
+		default:
+			d.Skip()
+		}
+	}
+
+	return d.Err()
+}
+
 // Version returns the hardcoded version of the model.
 func (o *User) Version() int {
 
@@ -2668,27 +2948,27 @@ func (o SparseUsersList) Version() int {
 // SparseUser represents the sparse version of a user.
 type SparseUser struct {
 	// The identifier.
-	ID *string `json:"ID,omitempty" msgpack:"ID,omitempty" bson:"-" mapstructure:"ID,omitempty"`
+	ID *string `json:"ID,omitempty" msgpack:"ID,omitempty" cbor:"ID,omitempty" bson:"-" mapstructure:"ID,omitempty"`
 
 	// the object is archived and not deleted.
-	Archived *bool `json:"archived,omitempty" msgpack:"archived,omitempty" bson:"archived,omitempty" mapstructure:"archived,omitempty"`
+	Archived *bool `json:"archived,omitempty" msgpack:"archived,omitempty" cbor:"archived,omitempty" bson:"archived,omitempty" mapstructure:"archived,omitempty"`
 
 	// The first name.
-	FirstName *string `json:"firstName,omitempty" msgpack:"firstName,omitempty" bson:"firstname,omitempty" mapstructure:"firstName,omitempty"`
+	FirstName *string `json:"firstName,omitempty" msgpack:"firstName,omitempty" cbor:"firstName,omitempty" bson:"firstname,omitempty" mapstructure:"firstName,omitempty"`
 
 	// The last name.
-	LastName *string `json:"lastName,omitempty" msgpack:"lastName,omitempty" bson:"lastname,omitempty" mapstructure:"lastName,omitempty"`
+	LastName *string `json:"lastName,omitempty" msgpack:"lastName,omitempty" cbor:"lastName,omitempty" bson:"lastname,omitempty" mapstructure:"lastName,omitempty"`
 
 	// The identifier of the parent of the object.
-	ParentID *string `json:"parentID,omitempty" msgpack:"parentID,omitempty" bson:"parentid,omitempty" mapstructure:"parentID,omitempty"`
+	ParentID *string `json:"parentID,omitempty" msgpack:"parentID,omitempty" cbor:"parentID,omitempty" bson:"parentid,omitempty" mapstructure:"parentID,omitempty"`
 
 	// The type of the parent of the object.
-	ParentType *string `json:"parentType,omitempty" msgpack:"parentType,omitempty" bson:"parenttype,omitempty" mapstructure:"parentType,omitempty"`
+	ParentType *string `json:"parentType,omitempty" msgpack:"parentType,omitempty" cbor:"parentType,omitempty" bson:"parenttype,omitempty" mapstructure:"parentType,omitempty"`
 
 	// the login.
-	UserName *string `json:"userName,omitempty" msgpack:"userName,omitempty" bson:"username,omitempty" mapstructure:"userName,omitempty"`
+	UserName *string `json:"userName,omitempty" msgpack:"userName,omitempty" cbor:"userName,omitempty" bson:"username,omitempty" mapstructure:"userName,omitempty"`
 
-	ModelVersion int `json:"-" msgpack:"-" bson:"_modelversion"`
+	ModelVersion int `json:"-" msgpack:"-" cbor:"-" bson:"_modelversion"`
 }
 
 // NewSparseUser returns a new  SparseUser.
@@ -2893,7 +3173,7 @@ type mongoAttributesSparseUser struct {
 
 // Root represents the model of a root
 type Root struct {
-	ModelVersion int `json:"-" msgpack:"-" bson:"_modelversion"`
+	ModelVersion int `json:"-" msgpack:"-" cbor:"-" bson:"_modelversion"`
 }
 
 // NewRoot returns a new *Root
@@ -2950,6 +3230,28 @@ func (o *Root) UnmarshalBSON(raw []byte) error {
 	return nil
 }
 
+// MarshalProtobuf implements the ProtobufMarshaler interface.
+func (o *Root) MarshalProtobuf() ([]byte, error) {
+
+	e := NewProtobufEncoder()
+
+	return e.Bytes()
+}
+
+// UnmarshalProtobuf implements the ProtobufUnmarshaler interface.
+func (o *Root) UnmarshalProtobuf(data []byte) error {
+
+	d := NewProtobufDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		default:
+			d.Skip()
+		}
+	}
+
+	return d.Err()
+}
+
 // Version returns the hardcoded version of the model.
 func (o *Root) Version() int {
 
@@ -12,14 +12,15 @@
 package elemental
 
 import (
-	"bytes"
 	"fmt"
 	"io"
 	"mime"
 	"net/http"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/ugorji/go/codec"
 )
@@ -31,14 +32,16 @@ var (
 
 // RegisterSupportedContentType registers a new media type
 // that elemental should support for Content-Type.
-// Note that this needs external intervention to handle encoding.
+// Note that this needs external intervention to handle encoding,
+// unless a Codec is registered for it. See RegisterCodec.
 func RegisterSupportedContentType(mimetype string) {
 	externalSupportedContentType[mimetype] = struct{}{}
 }
 
 // RegisterSupportedAcceptType registers a new media type
 // that elemental should support for Accept.
-// Note that this needs external intervention to handle decoding.
+// Note that this needs external intervention to handle decoding,
+// unless a Codec is registered for it. See RegisterCodec.
 func RegisterSupportedAcceptType(mimetype string) {
 	externalSupportedAcceptType[mimetype] = struct{}{}
 }
@@ -66,34 +69,16 @@ type EncodingType string
 
 // Various values for EncodingType.
 const (
-	EncodingTypeJSON    EncodingType = "application/json"
-	EncodingTypeMSGPACK EncodingType = "application/msgpack"
+	EncodingTypeJSON     EncodingType = "application/json"
+	EncodingTypeMSGPACK  EncodingType = "application/msgpack"
+	EncodingTypeCBOR     EncodingType = "application/cbor"
+	EncodingTypeProtobuf EncodingType = "application/x-protobuf"
 )
 
 var (
-	jsonHandle       = &codec.JsonHandle{}
-	jsonEncodersPool = sync.Pool{
-		New: func() any {
-			return codec.NewEncoder(nil, jsonHandle)
-		},
-	}
-	jsonDecodersPool = sync.Pool{
-		New: func() any {
-			return codec.NewDecoder(nil, jsonHandle)
-		},
-	}
-
-	msgpackHandle       = &codec.MsgpackHandle{}
-	msgpackEncodersPool = sync.Pool{
-		New: func() any {
-			return codec.NewEncoder(nil, msgpackHandle)
-		},
-	}
-	msgpackDecodersPool = sync.Pool{
-		New: func() any {
-			return codec.NewDecoder(nil, msgpackHandle)
-		},
-	}
+	jsonHandle    = &codec.JsonHandle{}
+	msgpackHandle = &codec.MsgpackHandle{}
+	cborHandle    = &codec.CborHandle{}
 )
 
 func init() {
@@ -106,71 +91,85 @@ func init() {
 	msgpackHandle.WriteExt = true
 	msgpackHandle.MapType = reflect.TypeOf(map[string]any(nil))
 	msgpackHandle.TypeInfos = codec.NewTypeInfos([]string{"msgpack"})
+
+	// Fields without cbor tag use their msgpack tag, and times are
+	// encoded by cborTimeExt as the builtin support loses the nanoseconds.
+	// The builtin support is still used for times that are not in a struct
+	// or a typed collection, so it uses the same RFC3339 representation.
+	cborHandle.Canonical = true
+	cborHandle.MapType = reflect.TypeOf(map[string]any(nil))
+	cborHandle.TypeInfos = codec.NewTypeInfos([]string{"cbor", "msgpack"})
+	cborHandle.TimeRFC3339 = true
+	cborHandle.TimeNotBuiltin = true
+	if err := cborHandle.SetInterfaceExt(reflect.TypeOf(time.Time{}), 0, cborTimeExt{}); err != nil {
+		panic(err)
+	}
+
+	RegisterCodec(EncodingTypeJSON, newUgorjiCodec(jsonHandle))
+	RegisterCodec(EncodingTypeMSGPACK, newUgorjiCodec(msgpackHandle))
+	RegisterCodec(EncodingTypeCBOR, newUgorjiCodec(cborHandle))
 }
 
-// Decode decodes the given data using an appropriate decoder chosen
-// from the given encoding.
-func Decode(encoding EncodingType, data []byte, dest any) error {
+// cborTimeExt encodes a time.Time as a standard CBOR date/time string,
+// using the RFC3339 format with nanoseconds.
+type cborTimeExt struct{}
 
-	var pool *sync.Pool
+func (cborTimeExt) ConvertExt(v any) any {
 
-	switch encoding {
-	case EncodingTypeMSGPACK:
-		pool = &msgpackDecodersPool
+	switch t := v.(type) {
+	case time.Time:
+		return t.Format(time.RFC3339Nano)
+	case *time.Time:
+		return t.Format(time.RFC3339Nano)
 	default:
-		pool = &jsonDecodersPool
-		encoding = EncodingTypeJSON
+		panic(fmt.Sprintf("unexpected type for cbor time extension: %T", v))
 	}
+}
+
+func (cborTimeExt) UpdateExt(dst any, src any) {
 
-	res := pool.Get()
-	dec, ok := res.(*codec.Decoder)
+	s, ok := src.(string)
 	if !ok {
-		return fmt.Errorf("expected codec.Decoder from pool, got unexpected type: %T", res)
+		panic(fmt.Sprintf("unexpected value for cbor time extension: %T", src))
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		panic(err)
 	}
-	defer pool.Put(dec)
 
-	dec.Reset(bytes.NewBuffer(data))
+	*dst.(*time.Time) = t
+}
+
+// Decode decodes the given data using the Codec registered
+// for the given encoding. See RegisterCodec.
+func Decode(encoding EncodingType, data []byte, dest any) error {
+
+	c, encoding := codecForEncoding(encoding)
 
-	if err := dec.Decode(dest); err != nil {
+	if err := c.Decode(data, dest); err != nil {
 		return fmt.Errorf("unable to decode %s: %s", encoding, err.Error())
 	}
 
 	return nil
 }
 
-// Encode encodes the given object using an appropriate encoder chosen
-// from the given acceptType.
+// Encode encodes the given object using the Codec registered
+// for the given encoding. See RegisterCodec.
 func Encode(encoding EncodingType, obj any) ([]byte, error) {
 
 	if obj == nil {
 		return nil, fmt.Errorf("encode received a nil object")
 	}
 
-	var pool *sync.Pool
-
-	switch encoding {
-	case EncodingTypeMSGPACK:
-		pool = &msgpackEncodersPool
-	default:
-		pool = &jsonEncodersPool
-		encoding = EncodingTypeJSON
-	}
-
-	res := pool.Get()
-	enc, ok := res.(*codec.Encoder)
-	if !ok {
-		return nil, fmt.Errorf("expected codec.Encoder from pool, got unexpected type: %T", res)
-	}
-	defer pool.Put(enc)
-
-	buf := bytes.NewBuffer(nil)
-	enc.Reset(buf)
+	c, encoding := codecForEncoding(encoding)
 
-	if err := enc.Encode(obj); err != nil {
+	data, err := c.Encode(obj)
+	if err != nil {
 		return nil, fmt.Errorf("unable to encode %s: %s", encoding, err.Error())
 	}
 
-	return buf.Bytes(), nil
+	return data, nil
 }
 
 // MakeStreamDecoder returns a function that can be used to decode a stream from the
@@ -184,47 +183,23 @@ func Encode(encoding EncodingType, obj any) ([]byte, error) {
 // In any case, the dispose function should be always called, in a defer for example.
 func MakeStreamDecoder(encoding EncodingType, reader io.Reader) (func(dest any) error, func()) {
 
-	var pool *sync.Pool
-
-	switch encoding {
-	case EncodingTypeMSGPACK:
-		pool = &msgpackDecodersPool
-	default:
-		pool = &jsonDecodersPool
-	}
-
-	res := pool.Get()
-	dec, ok := res.(*codec.Decoder)
-	if !ok {
-		return func(dest any) error {
-			return fmt.Errorf("expected codec.Decoder from pool, got unexpected type: %T", res)
-		}, func() {}
-	}
-	dec.Reset(reader)
-
-	clean := func() {
-		if pool != nil {
-			pool.Put(dec)
-			pool = nil
-		}
-	}
+	c, encoding := codecForEncoding(encoding)
+	decode, dispose := c.NewStreamDecoder(reader)
 
 	return func(dest any) error {
 
-			if err := dec.Decode(dest); err != nil {
+		if err := decode(dest); err != nil {
 
-				if err == io.EOF {
-					clean()
-					return err
-				}
-
-				return fmt.Errorf("unable to decode %s: %s", encoding, err.Error())
+			if err == io.EOF {
+				dispose()
+				return err
 			}
 
-			return nil
-		}, func() {
-			clean()
+			return fmt.Errorf("unable to decode %s: %s", encoding, err.Error())
 		}
+
+		return nil
+	}, dispose
 }
 
 // MakeStreamEncoder returns a function that can be user en encode given data
@@ -235,41 +210,17 @@ func MakeStreamDecoder(encoding EncodingType, reader io.Reader) (func(dest any)
 // memory pools.
 func MakeStreamEncoder(encoding EncodingType, writer io.Writer) (func(obj any) error, func()) {
 
-	var pool *sync.Pool
+	c, encoding := codecForEncoding(encoding)
+	encode, dispose := c.NewStreamEncoder(writer)
 
-	switch encoding {
-	case EncodingTypeMSGPACK:
-		pool = &msgpackEncodersPool
-	default:
-		pool = &jsonEncodersPool
-	}
-
-	res := pool.Get()
-	enc, ok := res.(*codec.Encoder)
-	if !ok {
-		return func(dest any) error {
-			return fmt.Errorf("expected codec.Encoder from pool, got unexpected type: %T", res)
-		}, func() {}
-	}
-	enc.Reset(writer)
+	return func(obj any) error {
 
-	clean := func() {
-		if pool != nil {
-			pool.Put(enc)
-			pool = nil
+		if err := encode(obj); err != nil {
+			return fmt.Errorf("unable to encode %s: %s", encoding, err.Error())
 		}
-	}
 
-	return func(dest any) error {
-
-			if err := enc.Encode(dest); err != nil {
-				return fmt.Errorf("unable to encode %s: %s", encoding, err.Error())
-			}
-
-			return nil
-		}, func() {
-			clean()
-		}
+		return nil
+	}, dispose
 }
 
 // Convert converts from one EncodingType to another
@@ -289,6 +240,11 @@ func Convert(from EncodingType, to EncodingType, data []byte) ([]byte, error) {
 
 // EncodingFromHeaders returns the read (Content-Type) and write (Accept) encoding
 // from the given http.Header.
+//
+// The write encoding is negotiated following RFC 7231: the supported encoding with
+// the highest quality value in the Accept header is selected. Media ranges like
+// application/* or */* only select JSON, the other encodings must be named. A 406
+// error is returned if no supported encoding is acceptable.
 func EncodingFromHeaders(header http.Header) (read EncodingType, write EncodingType, err error) {
 
 	read = EncodingTypeJSON
@@ -306,14 +262,11 @@ func EncodingFromHeaders(header http.Header) (read EncodingType, write EncodingT
 
 		switch ct {
 
-		case "application/msgpack":
-			read = EncodingTypeMSGPACK
-
 		case "application/*", "*/*", "application/json":
 			read = EncodingTypeJSON
 
 		default:
-			var supported bool
+			supported := hasCodec(EncodingType(ct))
 			for t := range externalSupportedContentType {
 				if ct == t {
 					supported = true
@@ -329,42 +282,142 @@ func EncodingFromHeaders(header http.Header) (read EncodingType, write EncodingT
 	}
 
 	if v := header.Get("Accept"); v != "" {
-		var agreed bool
-	L:
-		for _, item := range strings.Split(v, ",") {
+		if write, err = negotiateAcceptEncoding(v); err != nil {
+			return "", "", err
+		}
+	}
+
+	return read, write, nil
+}
+
+// An acceptedMediaRange is a media range of an Accept header.
+type acceptedMediaRange struct {
+	typ     string
+	subtype string
+	params  map[string]string
+	quality float64
+	index   int
+}
+
+// specificity returns how specifically the media range matches the given
+// media type, or 0 if it does not match. Wildcards only match JSON.
+func (r acceptedMediaRange) specificity(typ string, subtype string) int {
+
+	// Encodings are always written in UTF-8.
+	if charset, ok := r.params["charset"]; ok && charset != "*" && !strings.EqualFold(charset, "utf-8") {
+		return 0
+	}
+
+	isJSON := typ+"/"+subtype == string(EncodingTypeJSON)
+
+	switch {
+	case r.typ == "*" && r.subtype == "*" && isJSON:
+		return 1
+	case r.typ == typ && r.subtype == "*" && isJSON:
+		return 2
+	case r.typ == typ && r.subtype == subtype:
+		return 3 + len(r.params)
+	default:
+		return 0
+	}
+}
+
+// parseAcceptHeader returns the media ranges of the given Accept header.
+func parseAcceptHeader(header string) ([]acceptedMediaRange, error) {
+
+	var ranges []acceptedMediaRange // nolint
+
+	for i, item := range strings.Split(header, ",") {
+
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+
+		mt, params, err := mime.ParseMediaType(item)
+		if err != nil {
+			return nil, err
+		}
 
-			at, _, err := mime.ParseMediaType(item)
-			if err != nil {
-				return "", "", NewError("Bad Request", fmt.Sprintf("Invalid Accept header: %s", err), "elemental", http.StatusBadRequest)
+		typ, subtype, ok := strings.Cut(mt, "/")
+		if !ok || (typ == "*" && subtype != "*") {
+			return nil, fmt.Errorf("invalid media range: %s", mt)
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil || quality < 0 || quality > 1 {
+				return nil, fmt.Errorf("invalid quality value: %s", q)
 			}
+			delete(params, "q")
+		}
 
-			switch at {
-
-			case "application/msgpack":
-				write = EncodingTypeMSGPACK
-				agreed = true
-				break L
-
-			case "application/*", "*/*", "application/json":
-				write = EncodingTypeJSON
-				agreed = true
-				break L
-
-			default:
-				for t := range externalSupportedAcceptType {
-					if at == t {
-						agreed = true
-						write = EncodingType(at)
-						break L
-					}
-				}
+		ranges = append(ranges, acceptedMediaRange{
+			typ:     typ,
+			subtype: subtype,
+			params:  params,
+			quality: quality,
+			index:   i,
+		})
+	}
+
+	return ranges, nil
+}
+
+// negotiateAcceptEncoding returns the supported encoding preferred by the given Accept header.
+// Encodings are ranked by the quality value of their most specific media range, then by the
+// specificity of that range, and then by its position in the header.
+func negotiateAcceptEncoding(header string) (EncodingType, error) {
+
+	ranges, err := parseAcceptHeader(header)
+	if err != nil {
+		return "", NewError("Bad Request", fmt.Sprintf("Invalid Accept header: %s", err), "elemental", http.StatusBadRequest)
+	}
+
+	candidates := make([]string, 0, len(codecs)+len(externalSupportedAcceptType))
+	for encoding := range codecs {
+		if encoding != EncodingTypeJSON {
+			candidates = append(candidates, string(encoding))
+		}
+	}
+	for t := range externalSupportedAcceptType {
+		if !hasCodec(EncodingType(t)) {
+			candidates = append(candidates, t)
+		}
+	}
+	sort.Strings(candidates)
+	candidates = append([]string{string(EncodingTypeJSON)}, candidates...)
+
+	var selected EncodingType
+	var best *acceptedMediaRange
+	var bestSpecificity int
+
+	for _, candidate := range candidates {
+
+		typ, subtype, _ := strings.Cut(candidate, "/")
+
+		var match *acceptedMediaRange
+		var specificity int
+		for i := range ranges {
+			if s := ranges[i].specificity(typ, subtype); s > specificity {
+				match, specificity = &ranges[i], s
 			}
 		}
 
-		if !agreed {
-			return "", "", NewError("Unsupported Media Type", fmt.Sprintf("Cannot find any acceptable Accept media type in provided header: %s", v), "elemental", http.StatusUnsupportedMediaType)
+		if match == nil || match.quality == 0 {
+			continue
+		}
+
+		if best == nil ||
+			match.quality > best.quality ||
+			(match.quality == best.quality && specificity > bestSpecificity) ||
+			(match.quality == best.quality && specificity == bestSpecificity && match.index < best.index) {
+			selected, best, bestSpecificity = EncodingType(candidate), match, specificity
 		}
 	}
 
-	return read, write, nil
+	if best == nil {
+		return "", NewError("Not Acceptable", fmt.Sprintf("Cannot find any acceptable Accept media type in provided header: %s", header), "elemental", http.StatusNotAcceptable)
+	}
+
+	return selected, nil
 }
@@ -17,6 +17,7 @@ import (
 	"io"
 	"net/http"
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -145,6 +146,32 @@ func TestEncodeDecode(t *testing.T) {
 
 	test(EncodingTypeJSON)
 	test(EncodingTypeMSGPACK)
+	test(EncodingTypeCBOR)
+
+	Convey("Given I encode an object with a date using encoding application/cbor", t, func() {
+
+		o := &List{ID: "1", Name: "hello", Date: time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)}
+
+		data, err := Encode(EncodingTypeCBOR, o)
+
+		Convey("Then err should be nil", func() {
+			So(err, ShouldBeNil)
+		})
+
+		Convey("When I decode it", func() {
+			o1 := &List{}
+
+			err := Decode(EncodingTypeCBOR, data, o1)
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then o1 should resemble to o, with the nanoseconds", func() {
+				So(o1, ShouldResemble, o)
+			})
+		})
+	})
 }
 
 func TestMakeStreamEncoderDecoder(t *testing.T) {
@@ -272,6 +299,31 @@ func TestConvert(t *testing.T) {
 		})
 	})
 
+	Convey("Given I have some data encoded in MSGPACK", t, func() {
+
+		l := NewList()
+		l.Name = "hello"
+		data, err := Encode(EncodingTypeMSGPACK, l)
+		if err != nil {
+			panic(err)
+		}
+
+		Convey("When I call Convert to make it CBOR", func() {
+
+			cdata, err := Convert(EncodingTypeMSGPACK, EncodingTypeCBOR, data)
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then converted data should be correct", func() {
+				l2 := NewList()
+				Decode(EncodingTypeCBOR, cdata, l2) // nolint
+				So(l2, ShouldResemble, l)
+			})
+		})
+	})
+
 	Convey("Given I have some data encoded in MSGPACK", t, func() {
 
 		l := NewList()
@@ -414,6 +466,30 @@ func TestEncodingFromHeader(t *testing.T) {
 		})
 	})
 
+	Convey("Given I have good cbor header", t, func() {
+
+		h := http.Header{}
+		h.Set("Content-Type", "application/cbor")
+		h.Set("Accept", "application/toto, application/cbor")
+
+		Convey("When I call EncodingFromHeaders", func() {
+
+			r, w, err := EncodingFromHeaders(h)
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then r should be correct", func() {
+				So(r, ShouldEqual, EncodingTypeCBOR)
+			})
+
+			Convey("Then w should be correct", func() {
+				So(w, ShouldEqual, EncodingTypeCBOR)
+			})
+		})
+	})
+
 	Convey("Given I have good json header", t, func() {
 
 		h := http.Header{}
@@ -491,7 +567,116 @@ func TestEncodingFromHeader(t *testing.T) {
 
 			Convey("Then err should not be nil", func() {
 				So(err, ShouldNotBeNil)
-				So(err.Error(), ShouldEqual, `error 415 (elemental): Unsupported Media Type: Cannot find any acceptable Accept media type in provided header: application/ppt,application/toto`)
+				So(err.Error(), ShouldEqual, `error 406 (elemental): Not Acceptable: Cannot find any acceptable Accept media type in provided header: application/ppt,application/toto`)
+			})
+		})
+	})
+
+	Convey("Given I have an accept header with quality values", t, func() {
+
+		h := http.Header{}
+		h.Set("Content-Type", "application/json")
+		h.Set("Accept", "application/json;q=0.5, application/msgpack;q=0.9, */*")
+
+		Convey("When I call EncodingFromHeaders", func() {
+
+			_, w, err := EncodingFromHeaders(h)
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then w should be the encoding with the highest quality", func() {
+				So(w, ShouldEqual, EncodingTypeMSGPACK)
+			})
+		})
+	})
+
+	Convey("Given I have an accept header excluding json", t, func() {
+
+		h := http.Header{}
+		h.Set("Accept", "application/json;q=0, application/*;q=0.2, application/cbor;q=0.1")
+
+		Convey("When I call EncodingFromHeaders", func() {
+
+			_, w, err := EncodingFromHeaders(h)
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then w should be the acceptable encoding", func() {
+				So(w, ShouldEqual, EncodingTypeCBOR)
+			})
+		})
+	})
+
+	Convey("Given I have an accept header with equal quality values", t, func() {
+
+		h := http.Header{}
+		h.Set("Accept", "text/html, application/cbor, application/msgpack, */*;q=0.8")
+
+		Convey("When I call EncodingFromHeaders", func() {
+
+			_, w, err := EncodingFromHeaders(h)
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then w should be the first one in the header", func() {
+				So(w, ShouldEqual, EncodingTypeCBOR)
+			})
+		})
+	})
+
+	Convey("Given I have an accept header with a charset", t, func() {
+
+		h := http.Header{}
+		h.Set("Accept", "application/msgpack;charset=iso-8859-1, application/json;charset=UTF-8;q=0.5")
+
+		Convey("When I call EncodingFromHeaders", func() {
+
+			_, w, err := EncodingFromHeaders(h)
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then w should be the one accepting utf-8", func() {
+				So(w, ShouldEqual, EncodingTypeJSON)
+			})
+		})
+	})
+
+	Convey("Given I have an accept header refusing everything", t, func() {
+
+		h := http.Header{}
+		h.Set("Accept", "application/msgpack;q=0, */*;q=0")
+
+		Convey("When I call EncodingFromHeaders", func() {
+
+			_, _, err := EncodingFromHeaders(h)
+
+			Convey("Then err should be correct", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `error 406 (elemental): Not Acceptable: Cannot find any acceptable Accept media type in provided header: application/msgpack;q=0, */*;q=0`)
+			})
+		})
+	})
+
+	Convey("Given I have an accept header with an invalid quality value", t, func() {
+
+		h := http.Header{}
+		h.Set("Accept", "application/json;q=2")
+
+		Convey("When I call EncodingFromHeaders", func() {
+
+			_, _, err := EncodingFromHeaders(h)
+
+			Convey("Then err should be correct", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, `error 400 (elemental): Bad Request: Invalid Accept header: invalid quality value: 2`)
 			})
 		})
 	})
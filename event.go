@@ -87,14 +87,19 @@ func NewEventWithEncoding(t EventType, o Identifiable, encoding EncodingType) *E
 }
 
 func (e *Event) configureData(encoding EncodingType, data []byte) {
-	switch encoding {
-	case EncodingTypeJSON:
-		e.JSONData = json.RawMessage(data)
-	case EncodingTypeMSGPACK:
+	if isRawEventEncoding(encoding) {
 		e.RawData = data
+	} else {
+		e.JSONData = json.RawMessage(data)
 	}
 }
 
+// isRawEventEncoding returns true if the entity of an event using the given encoding
+// is stored in its RawData. Unknown encodings are decoded as JSON, so they are not.
+func isRawEventEncoding(encoding EncodingType) bool {
+	return encoding != EncodingTypeJSON && hasCodec(encoding)
+}
+
 // GetEncoding returns the encoding used to encode the entity.
 func (e *Event) GetEncoding() EncodingType {
 	return e.Encoding
@@ -105,34 +110,39 @@ func (e *Event) Decode(dst any) error {
 	return Decode(e.GetEncoding(), e.Entity(), dst)
 }
 
-// Convert converts the internal encoded data to the given
-// encoding.
-func (e *Event) Convert(encoding EncodingType) error {
+// MatchesFilter decodes the entity of the event as a map and returns
+// true if it matches the given filter. See NewAttributeSpecifiableAdapter.
+func (e *Event) MatchesFilter(filter *Filter, opts ...MatcherOption) (bool, error) {
 
-	switch e.Encoding {
+	var entity map[string]any
+	if err := e.Decode(&entity); err != nil {
+		return false, err
+	}
 
-	case encoding:
-		return nil
+	as, err := NewAttributeSpecifiableAdapter(entity)
+	if err != nil {
+		return false, err
+	}
 
-	case EncodingTypeMSGPACK:
+	return MatchesFilter(as, filter, opts...)
+}
 
-		d, err := Convert(e.Encoding, encoding, e.RawData)
-		if err != nil {
-			return err
-		}
-		e.JSONData = json.RawMessage(d)
-		e.RawData = nil
+// Convert converts the internal encoded data to the given
+// encoding.
+func (e *Event) Convert(encoding EncodingType) error {
 
-	default:
+	if e.Encoding == encoding {
+		return nil
+	}
 
-		d, err := Convert(e.Encoding, encoding, []byte(e.JSONData))
-		if err != nil {
-			return err
-		}
-		e.JSONData = nil
-		e.RawData = d
+	d, err := Convert(e.Encoding, encoding, e.Entity())
+	if err != nil {
+		return err
 	}
 
+	e.JSONData = nil
+	e.RawData = nil
+	e.configureData(encoding, d)
 	e.Encoding = encoding
 
 	return nil
@@ -141,12 +151,11 @@ func (e *Event) Convert(encoding EncodingType) error {
 // Entity returns the byte encoded entity.
 func (e *Event) Entity() []byte {
 
-	switch e.Encoding {
-	case EncodingTypeMSGPACK:
+	if isRawEventEncoding(e.Encoding) {
 		return e.RawData
-	default:
-		return []byte(e.JSONData)
 	}
+
+	return []byte(e.JSONData)
 }
 
 func (e *Event) String() string {
@@ -200,6 +200,32 @@ func TestEvent_Convert(t *testing.T) {
 		})
 	})
 
+	Convey("Given I have an Event with EncodingTypeCBOR data", t, func() {
+
+		list := &List{
+			Name: "hello",
+		}
+
+		e := NewEventWithEncoding(EventCreate, list, EncodingTypeCBOR)
+
+		Convey("When I Convert to EncodingTypeMSGPACK", func() {
+
+			err := e.Convert(EncodingTypeMSGPACK)
+
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Then the converted event should be correct", func() {
+				l2 := &List{}
+				_ = Decode(EncodingTypeMSGPACK, e.Entity(), l2)
+				So(e.JSONData, ShouldBeNil)
+				So(e.Encoding, ShouldEqual, EncodingTypeMSGPACK)
+				So(list, ShouldResemble, l2)
+			})
+		})
+	})
+
 	Convey("Given I have an Event with EncodingTypeMSGPACK data", t, func() {
 
 		list := &List{
@@ -0,0 +1,589 @@
+package elemental
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// maxFilterDisjuncts is the maximum number of conjunctions a filter is expanded
+// into by the analysis. Larger filters are not analyzed.
+const maxFilterDisjuncts = 256
+
+// FilterImplies returns true if any object matching the given filter also matches the other one,
+// meaning the other filter subsumes the filter. For instance, a == 1 and b == 2 implies a == 1.
+//
+// The analysis understands the ==, !=, in, not in, >, >=, <, <=, exists and not exists comparators,
+// and the and, or and not operators. Other terms are only considered identical to themselves.
+// It assumes the attributes hold scalar values, as a list matches a == 1 and a == 2 if it contains
+// both values. As it only reasons on each conjunction of the filters separately, it returns false
+// when the implication cannot be established, even if it holds. Relative times are compared as if
+// they were evaluated at the same time.
+func FilterImplies(filter *Filter, other *Filter) bool {
+
+	if filter == nil || other == nil {
+		panic(fmt.Errorf("elemental: filter cannot be nil"))
+	}
+
+	now := time.Now()
+
+	disjuncts, ok := filterDisjuncts(filter)
+	if !ok {
+		return false
+	}
+
+	otherDisjuncts, ok := filterDisjuncts(other)
+	if !ok {
+		return false
+	}
+
+	for _, conj := range disjuncts {
+		for _, conj := range splitConjunction(conj, now) {
+
+			constraints := makeFilterConstraints(conj, now)
+			if constraints.unsatisfiable() {
+				continue
+			}
+
+			if !slices.ContainsFunc(otherDisjuncts, func(otherConj []analyzedTerm) bool {
+				return constraints.implies(otherConj, now)
+			}) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// FiltersEquivalent returns true if the given filters match the same objects, as
+// they imply each other. See FilterImplies for the limits of the analysis.
+func FiltersEquivalent(filter *Filter, other *Filter) bool {
+
+	return FilterImplies(filter, other) && FilterImplies(other, filter)
+}
+
+// IsFilterUnsatisfiable returns true if the given filter cannot match any object,
+// like a == 1 and a == 2. See FilterImplies for the limits of the analysis, which
+// returns false when the filter cannot be proven unsatisfiable.
+func IsFilterUnsatisfiable(filter *Filter) bool {
+
+	if filter == nil {
+		panic(fmt.Errorf("elemental: filter cannot be nil"))
+	}
+
+	now := time.Now()
+
+	disjuncts, ok := filterDisjuncts(filter)
+	if !ok {
+		return false
+	}
+
+	for _, conj := range disjuncts {
+		if !makeFilterConstraints(conj, now).unsatisfiable() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// analyzedTerm is a term of a filter expanded in disjunctive normal form.
+// A negated term is the negation of a term without complementary comparator.
+type analyzedTerm struct {
+	key        string
+	comparator FilterComparator
+	values     FilterValue
+	negated    bool
+}
+
+func (t analyzedTerm) String() string {
+
+	f := NewFilter()
+	f.addTerm(t.key, t.comparator, t.values)
+
+	if t.negated {
+		return NewFilter().Not(f).Done().String()
+	}
+
+	return f.String()
+}
+
+var complementComparators = map[FilterComparator]FilterComparator{
+	EqualComparator:      NotEqualComparator,
+	NotEqualComparator:   EqualComparator,
+	InComparator:         NotInComparator,
+	NotInComparator:      InComparator,
+	ContainComparator:    NotContainComparator,
+	NotContainComparator: ContainComparator,
+	MatchComparator:      NotMatchComparator,
+	NotMatchComparator:   MatchComparator,
+	ExistsComparator:     NotExistsComparator,
+	NotExistsComparator:  ExistsComparator,
+}
+
+func (t analyzedTerm) negate() analyzedTerm {
+
+	switch c, ok := complementComparators[t.comparator]; {
+	case t.negated:
+		t.negated = false
+	case ok:
+		t.comparator = c
+	default:
+		t.negated = true
+	}
+
+	return t
+}
+
+// filterDisjuncts returns the given filter in disjunctive normal form: the filter matches if all the terms of
+// any of the returned conjunctions match. The returned boolean is false if the filter is too large to be expanded.
+func filterDisjuncts(filter *Filter) ([][]analyzedTerm, bool) {
+
+	disjuncts := [][]analyzedTerm{{}}
+
+	for i, op := range filter.operators {
+
+		var other [][]analyzedTerm
+		var ok bool
+
+		switch op {
+
+		case AndOperator:
+			other = [][]analyzedTerm{{{key: filter.keys[i], comparator: filter.comparators[i], values: filter.values[i]}}}
+
+		case AndFilterOperator:
+			if other, ok = conjoinedDisjuncts(filter.ands[i]); !ok {
+				return nil, false
+			}
+
+		case OrFilterOperator:
+			// an or without sub filters always matches.
+			if len(filter.ors[i]) == 0 {
+				continue
+			}
+			for _, sub := range filter.ors[i] {
+				d, ok := filterDisjuncts(sub)
+				if !ok || len(other)+len(d) > maxFilterDisjuncts {
+					return nil, false
+				}
+				other = append(other, d...)
+			}
+
+		case NotFilterOperator:
+			conj, ok := conjoinedDisjuncts(filter.nots[i])
+			if !ok {
+				return nil, false
+			}
+			if other, ok = negateDisjuncts(conj); !ok {
+				return nil, false
+			}
+		}
+
+		if disjuncts, ok = productDisjuncts(disjuncts, other); !ok {
+			return nil, false
+		}
+	}
+
+	return disjuncts, true
+}
+
+// conjoinedDisjuncts returns the disjunctive normal form of the conjunction of the given filters.
+func conjoinedDisjuncts(filters SubFilter) ([][]analyzedTerm, bool) {
+
+	disjuncts := [][]analyzedTerm{{}}
+
+	for _, sub := range filters {
+
+		d, ok := filterDisjuncts(sub)
+		if !ok {
+			return nil, false
+		}
+
+		if disjuncts, ok = productDisjuncts(disjuncts, d); !ok {
+			return nil, false
+		}
+	}
+
+	return disjuncts, true
+}
+
+// negateDisjuncts returns the disjunctive normal form of the negation of the given disjuncts,
+// which is the conjunction of the disjunctions of the negations of the terms of each conjunction.
+func negateDisjuncts(disjuncts [][]analyzedTerm) ([][]analyzedTerm, bool) {
+
+	out := [][]analyzedTerm{{}}
+
+	for _, conj := range disjuncts {
+
+		negated := make([][]analyzedTerm, len(conj))
+		for i, t := range conj {
+			negated[i] = []analyzedTerm{t.negate()}
+		}
+
+		var ok bool
+		if out, ok = productDisjuncts(out, negated); !ok {
+			return nil, false
+		}
+	}
+
+	return out, true
+}
+
+// productDisjuncts returns the disjunctive normal form of the conjunction of the given disjuncts.
+func productDisjuncts(a [][]analyzedTerm, b [][]analyzedTerm) ([][]analyzedTerm, bool) {
+
+	if len(a)*len(b) > maxFilterDisjuncts {
+		return nil, false
+	}
+
+	out := make([][]analyzedTerm, 0, len(a)*len(b))
+	for _, ca := range a {
+		for _, cb := range b {
+			out = append(out, append(slices.Clip(ca), cb...))
+		}
+	}
+
+	return out, true
+}
+
+// splitConjunction splits the given conjunction into one conjunction per value of the attributes
+// that can only take a few values, so a in [1, 2] can be found to imply a == 1 or a == 2.
+func splitConjunction(conj []analyzedTerm, now time.Time) [][]analyzedTerm {
+
+	out := [][]analyzedTerm{conj}
+
+	for key, k := range makeFilterConstraints(conj, now) {
+
+		values, ok := k.values()
+		if !ok || len(values) < 2 {
+			continue
+		}
+
+		alternatives := make([][]analyzedTerm, len(values))
+		for i, v := range values {
+			alternatives[i] = []analyzedTerm{{key: key, comparator: EqualComparator, values: FilterValue{v}}}
+		}
+
+		if out, ok = productDisjuncts(out, alternatives); !ok {
+			return [][]analyzedTerm{conj}
+		}
+	}
+
+	return out
+}
+
+// filterBound is a lower or upper bound of the values of an attribute.
+type filterBound struct {
+	value     any
+	inclusive bool
+}
+
+// filterKeyConstraints are the constraints a conjunction puts on the value of an attribute.
+type filterKeyConstraints struct {
+	exists    bool
+	notExists bool
+	allowed   []any
+	excluded  []any
+	lowers    []filterBound
+	uppers    []filterBound
+	opaque    map[string]struct{}
+}
+
+// filterConstraints are the constraints a conjunction puts on each attribute.
+type filterConstraints map[string]*filterKeyConstraints
+
+func makeFilterConstraints(conj []analyzedTerm, now time.Time) filterConstraints {
+
+	constraints := filterConstraints{}
+
+	for _, t := range conj {
+
+		k, ok := constraints[t.key]
+		if !ok {
+			k = &filterKeyConstraints{opaque: map[string]struct{}{}}
+			constraints[t.key] = k
+		}
+
+		if !isAnalyzableTerm(t) {
+			k.opaque[t.String()] = struct{}{}
+			// only the negative comparators can match a missing attribute.
+			switch {
+			case t.negated:
+			case t.comparator == NotEqualComparator, t.comparator == NotInComparator,
+				t.comparator == NotContainComparator, t.comparator == NotMatchComparator:
+			default:
+				k.exists = true
+			}
+			continue
+		}
+
+		values := analyzedValues(t.values, now)
+
+		switch t.comparator {
+		case EqualComparator, InComparator:
+			if k.allowed == nil {
+				k.allowed = values
+			} else {
+				k.allowed = slices.DeleteFunc(k.allowed, func(v any) bool { return !containsAnalyzedValue(values, v) })
+			}
+		case NotEqualComparator, NotInComparator:
+			k.excluded = append(k.excluded, values...)
+		case GreaterComparator, GreaterOrEqualComparator:
+			k.lowers = append(k.lowers, filterBound{value: values[0], inclusive: t.comparator == GreaterOrEqualComparator})
+		case LesserComparator, LesserOrEqualComparator:
+			k.uppers = append(k.uppers, filterBound{value: values[0], inclusive: t.comparator == LesserOrEqualComparator})
+		case ExistsComparator:
+			k.exists = true
+		case NotExistsComparator:
+			k.notExists = true
+		}
+	}
+
+	return constraints
+}
+
+func (c filterConstraints) unsatisfiable() bool {
+
+	for _, k := range c {
+		if k.unsatisfiable() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// implies returns true if the constraints imply all the terms of the given conjunction.
+func (c filterConstraints) implies(conj []analyzedTerm, now time.Time) bool {
+
+	for _, t := range conj {
+
+		k, ok := c[t.key]
+		if !ok {
+			k = &filterKeyConstraints{}
+		}
+
+		if !k.implies(t, now) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (k *filterKeyConstraints) impliesExistence() bool {
+
+	return k.exists || k.allowed != nil || len(k.lowers) > 0 || len(k.uppers) > 0
+}
+
+// values returns the values the attribute can take, and false if they are not constrained.
+func (k *filterKeyConstraints) values() ([]any, bool) {
+
+	if k.allowed == nil {
+		return nil, false
+	}
+
+	var values []any
+	for _, v := range k.allowed {
+		if k.accepts(v) {
+			values = append(values, v)
+		}
+	}
+
+	return values, true
+}
+
+// accepts returns true if the given value satisfies the exclusions and the bounds.
+func (k *filterKeyConstraints) accepts(v any) bool {
+
+	if containsAnalyzedValue(k.excluded, v) {
+		return false
+	}
+
+	for _, b := range k.lowers {
+		if !satisfiesLowerBound(v, b) {
+			return false
+		}
+	}
+
+	for _, b := range k.uppers {
+		if !satisfiesUpperBound(v, b) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (k *filterKeyConstraints) unsatisfiable() bool {
+
+	if k.notExists && k.impliesExistence() {
+		return true
+	}
+
+	if values, ok := k.values(); ok && len(values) == 0 {
+		return true
+	}
+
+	for _, lower := range k.lowers {
+		for _, upper := range k.uppers {
+
+			c, ok := compareValues(lower.value, upper.value)
+			if !ok {
+				continue
+			}
+
+			if c > 0 || (c == 0 && (!lower.inclusive || !upper.inclusive || containsAnalyzedValue(k.excluded, lower.value))) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// implies returns true if the constraints imply the given term.
+func (k *filterKeyConstraints) implies(t analyzedTerm, now time.Time) bool {
+
+	if !isAnalyzableTerm(t) {
+		_, ok := k.opaque[t.String()]
+		return ok
+	}
+
+	values := analyzedValues(t.values, now)
+
+	switch t.comparator {
+
+	case ExistsComparator:
+		return k.impliesExistence()
+
+	case NotExistsComparator:
+		return k.notExists
+
+	case EqualComparator, InComparator:
+		allowed, ok := k.values()
+		return ok && !slices.ContainsFunc(allowed, func(v any) bool { return !containsAnalyzedValue(values, v) })
+
+	case NotEqualComparator, NotInComparator:
+		if k.notExists {
+			return true
+		}
+		allowed, constrained := k.values()
+		for _, v := range values {
+			if constrained && !containsAnalyzedValue(allowed, v) {
+				continue
+			}
+			if !k.accepts(v) {
+				continue
+			}
+			return false
+		}
+		return true
+
+	case GreaterComparator, GreaterOrEqualComparator, LesserComparator, LesserOrEqualComparator:
+		bound := filterBound{value: values[0], inclusive: t.comparator == GreaterOrEqualComparator || t.comparator == LesserOrEqualComparator}
+		lower := t.comparator == GreaterComparator || t.comparator == GreaterOrEqualComparator
+
+		if allowed, ok := k.values(); ok {
+			return !slices.ContainsFunc(allowed, func(v any) bool {
+				if lower {
+					return !satisfiesLowerBound(v, bound)
+				}
+				return !satisfiesUpperBound(v, bound)
+			})
+		}
+
+		if lower {
+			return slices.ContainsFunc(k.lowers, func(b filterBound) bool { return boundImpliesLowerBound(b, bound) })
+		}
+		return slices.ContainsFunc(k.uppers, func(b filterBound) bool { return boundImpliesUpperBound(b, bound) })
+	}
+
+	return false
+}
+
+// isAnalyzableTerm returns true if the analysis understands the given term.
+func isAnalyzableTerm(t analyzedTerm) bool {
+
+	if t.negated {
+		return false
+	}
+
+	switch t.comparator {
+	case EqualComparator, NotEqualComparator, InComparator, NotInComparator,
+		GreaterComparator, GreaterOrEqualComparator, LesserComparator, LesserOrEqualComparator:
+	case ExistsComparator, NotExistsComparator:
+		return true
+	default:
+		return false
+	}
+
+	if len(t.values) == 0 {
+		return false
+	}
+
+	for _, v := range t.values {
+		if _, ok := v.(FilterPlaceholder); ok {
+			return false
+		}
+		// list values are compared as lists, which the analysis does not handle.
+		if isArrayLike(reflect.ValueOf(v)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// analyzedValues returns the given values with the relative times evaluated at the given time.
+func analyzedValues(values FilterValue, now time.Time) []any {
+
+	out := make([]any, len(values))
+	for i, v := range values {
+		if t, ok := relativeTimeValue(v, now); ok {
+			out[i] = t
+			continue
+		}
+		out[i] = v
+	}
+
+	return out
+}
+
+func containsAnalyzedValue(values []any, value any) bool {
+
+	return slices.ContainsFunc(values, func(v any) bool {
+		if c, ok := compareValues(v, value); ok {
+			return c == 0
+		}
+		return reflect.DeepEqual(v, value)
+	})
+}
+
+func satisfiesLowerBound(v any, b filterBound) bool {
+
+	c, ok := compareValues(v, b.value)
+	return ok && (c > 0 || (c == 0 && b.inclusive))
+}
+
+func satisfiesUpperBound(v any, b filterBound) bool {
+
+	c, ok := compareValues(v, b.value)
+	return ok && (c < 0 || (c == 0 && b.inclusive))
+}
+
+// boundImpliesLowerBound returns true if any value above the lower bound b is also above the lower bound other.
+func boundImpliesLowerBound(b filterBound, other filterBound) bool {
+
+	c, ok := compareValues(b.value, other.value)
+	return ok && (c > 0 || (c == 0 && (other.inclusive || !b.inclusive)))
+}
+
+// boundImpliesUpperBound returns true if any value below the upper bound b is also below the upper bound other.
+func boundImpliesUpperBound(b filterBound, other filterBound) bool {
+
+	c, ok := compareValues(b.value, other.value)
+	return ok && (c < 0 || (c == 0 && (other.inclusive || !b.inclusive)))
+}
@@ -0,0 +1,162 @@
+package elemental_test
+
+import (
+	"testing"
+
+	"go.aporeto.io/elemental"
+)
+
+func TestFilterImplies(t *testing.T) {
+
+	tests := map[string]struct {
+		filter          string
+		other           string
+		expectedImplies bool
+	}{
+		"more terms":                     {filter: `a == 1 and b == 2`, other: `a == 1`, expectedImplies: true},
+		"less terms":                     {filter: `a == 1`, other: `a == 1 and b == 2`, expectedImplies: false},
+		"same terms":                     {filter: `a == 1 and b == 2`, other: `b == 2 and a == 1`, expectedImplies: true},
+		"different value":                {filter: `a == 1`, other: `a == 2`, expectedImplies: false},
+		"equal and in":                   {filter: `a == 1`, other: `a in [1, 2]`, expectedImplies: true},
+		"in and in":                      {filter: `a in [1, 2]`, other: `a in [1, 2, 3]`, expectedImplies: true},
+		"larger in":                      {filter: `a in [1, 2, 3]`, other: `a in [1, 2]`, expectedImplies: false},
+		"in narrowed by not equal":       {filter: `a in [1, 2, 3] and a != 3`, other: `a in [1, 2]`, expectedImplies: true},
+		"equal and not equal":            {filter: `a == 1`, other: `a != 2`, expectedImplies: true},
+		"equal and not in":               {filter: `a == 1`, other: `a not in [2, 3]`, expectedImplies: true},
+		"not in and not equal":           {filter: `a not in [2, 3]`, other: `a != 3`, expectedImplies: true},
+		"not equal and not in":           {filter: `a != 3`, other: `a not in [2, 3]`, expectedImplies: false},
+		"equal and bound":                {filter: `a == 5`, other: `a > 3`, expectedImplies: true},
+		"equal outside bound":            {filter: `a == 2`, other: `a > 3`, expectedImplies: false},
+		"tighter lower bound":            {filter: `a > 5`, other: `a >= 5`, expectedImplies: true},
+		"looser lower bound":             {filter: `a >= 5`, other: `a > 5`, expectedImplies: false},
+		"tighter upper bound":            {filter: `a < 3 and a > 1`, other: `a <= 10`, expectedImplies: true},
+		"bound and not equal":            {filter: `a > 5`, other: `a != 3`, expectedImplies: true},
+		"numbers of different types":     {filter: `a == 1`, other: `a >= 0.5`, expectedImplies: true},
+		"strings":                        {filter: `a == "abc"`, other: `a < "b"`, expectedImplies: true},
+		"relative times":                 {filter: `date > now() - 1d`, other: `date > now() - 1w`, expectedImplies: true},
+		"relative times reversed":        {filter: `date > now() - 1w`, other: `date > now() - 1d`, expectedImplies: false},
+		"exists":                         {filter: `a == 1`, other: `a exists`, expectedImplies: true},
+		"exists from string comparator":  {filter: `a startswith "x"`, other: `a exists`, expectedImplies: true},
+		"not exists and not equal":       {filter: `a not exists`, other: `a != 1`, expectedImplies: true},
+		"not exists":                     {filter: `a != 1`, other: `a not exists`, expectedImplies: false},
+		"identical opaque terms":         {filter: `a matches "^x" and b == 1`, other: `a matches "^x"`, expectedImplies: true},
+		"different opaque terms":         {filter: `a matches "^x"`, other: `a matches "^y"`, expectedImplies: false},
+		"or implies or":                  {filter: `a == 1 or a == 2`, other: `a in [1, 2, 3]`, expectedImplies: true},
+		"or does not imply":              {filter: `a == 1 or b == 2`, other: `a == 1`, expectedImplies: false},
+		"implies one disjunct":           {filter: `a == 1 and b == 2`, other: `a == 3 or b == 2`, expectedImplies: true},
+		"not":                            {filter: `not (a == 1 or a == 2)`, other: `a != 1`, expectedImplies: true},
+		"not equal and not":              {filter: `a != 1`, other: `not (a == 1)`, expectedImplies: true},
+		"negated opaque term":            {filter: `not (a > 1) and b == 1`, other: `not (a > 1)`, expectedImplies: true},
+		"double negation":                {filter: `not (not (a > 1))`, other: `a >= 1`, expectedImplies: true},
+		"unsatisfiable implies anything": {filter: `a == 1 and a == 2`, other: `b == 3`, expectedImplies: true},
+		"anything implies empty filter":  {filter: `a == 1`, other: ``, expectedImplies: true},
+		"placeholders":                   {filter: `a == $a and b == 1`, other: `a == $a`, expectedImplies: true},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			filter, err := elemental.NewFilterParser(tc.filter, elemental.OptPlaceholders()).Parse()
+			if err != nil {
+				t.Fatalf("unable to parse filter: %s", err)
+			}
+
+			other := elemental.NewFilter()
+			if tc.other != "" {
+				if other, err = elemental.NewFilterParser(tc.other, elemental.OptPlaceholders()).Parse(); err != nil {
+					t.Fatalf("unable to parse other filter: %s", err)
+				}
+			}
+
+			if implies := elemental.FilterImplies(filter, other); implies != tc.expectedImplies {
+				t.Errorf("unexpected implication of %s by %s: expected %t, got %t", other, filter, tc.expectedImplies, implies)
+			}
+		})
+	}
+}
+
+func TestFiltersEquivalent(t *testing.T) {
+
+	tests := map[string]struct {
+		filter             string
+		other              string
+		expectedEquivalent bool
+	}{
+		"reordered":          {filter: `a == 1 and b == 2`, other: `b == 2 and a == 1`, expectedEquivalent: true},
+		"in and or":          {filter: `a in [1, 2]`, other: `a == 2 or a == 1`, expectedEquivalent: true},
+		"de morgan":          {filter: `not (a == 1 and b == 2)`, other: `a != 1 or b != 2`, expectedEquivalent: true},
+		"bounds":             {filter: `a >= 1 and a <= 1`, other: `a == 1`, expectedEquivalent: false},
+		"narrower":           {filter: `a in [1, 2]`, other: `a == 1`, expectedEquivalent: false},
+		"redundant term":     {filter: `a == 1 and a > 0`, other: `a == 1`, expectedEquivalent: true},
+		"both unsatisfiable": {filter: `a == 1 and a == 2`, other: `b > 2 and b < 1`, expectedEquivalent: true},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			filter, err := elemental.NewFilterFromString(tc.filter)
+			if err != nil {
+				t.Fatalf("unable to parse filter: %s", err)
+			}
+
+			other, err := elemental.NewFilterFromString(tc.other)
+			if err != nil {
+				t.Fatalf("unable to parse other filter: %s", err)
+			}
+
+			if equivalent := elemental.FiltersEquivalent(filter, other); equivalent != tc.expectedEquivalent {
+				t.Errorf("unexpected equivalence of %s and %s: expected %t, got %t", filter, other, tc.expectedEquivalent, equivalent)
+			}
+		})
+	}
+}
+
+func TestIsFilterUnsatisfiable(t *testing.T) {
+
+	tests := map[string]struct {
+		filter                string
+		expectedUnsatisfiable bool
+	}{
+		"different values":            {filter: `a == 1 and a == 2`, expectedUnsatisfiable: true},
+		"same values":                 {filter: `a == 1 and a == 1.0`, expectedUnsatisfiable: false},
+		"disjoint in":                 {filter: `a in [1, 2] and a in [3, 4]`, expectedUnsatisfiable: true},
+		"overlapping in":              {filter: `a in [1, 2] and a in [2, 3]`, expectedUnsatisfiable: false},
+		"equal and not equal":         {filter: `a == 1 and a != 1`, expectedUnsatisfiable: true},
+		"in and not in":               {filter: `a in [1, 2] and a not in [1, 2]`, expectedUnsatisfiable: true},
+		"empty range":                 {filter: `a > 5 and a < 3`, expectedUnsatisfiable: true},
+		"exclusive single value":      {filter: `a >= 5 and a < 5`, expectedUnsatisfiable: true},
+		"inclusive single value":      {filter: `a >= 5 and a <= 5`, expectedUnsatisfiable: false},
+		"excluded single value":       {filter: `a >= 5 and a <= 5 and a != 5`, expectedUnsatisfiable: true},
+		"equal outside range":         {filter: `a == 10 and a < 5`, expectedUnsatisfiable: true},
+		"exists and not exists":       {filter: `a exists and a not exists`, expectedUnsatisfiable: true},
+		"equal and not exists":        {filter: `a == 1 and a not exists`, expectedUnsatisfiable: true},
+		"not equal and not exists":    {filter: `a != 1 and a not exists`, expectedUnsatisfiable: false},
+		"different keys":              {filter: `a == 1 and b == 2`, expectedUnsatisfiable: false},
+		"all disjuncts":               {filter: `(a == 1 or a == 2) and a == 3`, expectedUnsatisfiable: true},
+		"one satisfiable disjunct":    {filter: `(a == 1 or a == 2) and a == 2`, expectedUnsatisfiable: false},
+		"not":                         {filter: `a == 1 and not (a == 1)`, expectedUnsatisfiable: true},
+		"negated opaque":              {filter: `a > 1 and not (a > 1)`, expectedUnsatisfiable: false},
+		"incomparable bounds":         {filter: `a > 5 and a < "x"`, expectedUnsatisfiable: false},
+		"incomparable equal":          {filter: `a == "x" and a > 5`, expectedUnsatisfiable: true},
+		"opaque terms":                {filter: `a matches "^x" and a matches "^y"`, expectedUnsatisfiable: false},
+		"string comparator not exist": {filter: `a startswith "x" and a not exists`, expectedUnsatisfiable: true},
+		"empty":                       {filter: ``, expectedUnsatisfiable: false},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			filter := elemental.NewFilter()
+			if tc.filter != "" {
+				var err error
+				if filter, err = elemental.NewFilterFromString(tc.filter); err != nil {
+					t.Fatalf("unable to parse filter: %s", err)
+				}
+			}
+
+			if unsatisfiable := elemental.IsFilterUnsatisfiable(filter); unsatisfiable != tc.expectedUnsatisfiable {
+				t.Errorf("unexpected unsatisfiability of %s: expected %t, got %t", filter, tc.expectedUnsatisfiable, unsatisfiable)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package elemental
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FilterToBSON translates the given filter into a MongoDB query.
+//
+// If a manager is given, the keys of the filter are resolved against the attribute specifications of the model
+// of the given identity and translated to their BSONFieldName. An error is returned if the identity is unknown or
+// if one of the keys is not an attribute of the model. If the manager is nil, keys are simply lower cased, which
+// is what elegen does by default when generating the bson field names.
+//
+// Keys can be dotted paths targeting nested data (see ValueForPath), in which case only their root attribute is
+// translated, and the rest of the path is kept as is.
+//
+// Values given to the "_id" field are converted to primitive.ObjectID when they are valid hexadecimal object ids,
+// and time.Duration and FilterRelativeTime values are translated to a date relative to now, as they are in MatchesFilter.
+//
+// The comparators are translated as follow:
+//
+//	==           {k: {$eq: v}}
+//	!=           {k: {$ne: v}}
+//	>, >=, <, <= {k: {$gt|$gte|$lt|$lte: v}}
+//	in           {k: {$in: [v1, v2]}}
+//	not in       {k: {$nin: [v1, v2]}}
+//	contains     {k: {$in: [v1, v2]}}
+//	not contains {k: {$nin: [v1, v2]}}
+//	matches      {$or: [{k: {$regex: v1}}, {k: {$regex: v2}}]}
+//	not matches  {$nor: [{k: {$regex: v1}}, {k: {$regex: v2}}]}
+//	exists       {k: {$exists: true}}
+//	not exists   {k: {$exists: false}}
+//	=~           {k: {$regex: "^v$", $options: "i"}}
+//	startswith   {k: {$regex: "^v"}}
+//	endswith     {k: {$regex: "v$"}}
+//
+// The values of =~, startswith and endswith must be strings, and they are escaped to be matched literally.
+//
+// All the statements of the filter are combined using $and, and the sub filters using $and or $or.
+// Negated sub filters are translated to {$nor: [{$and: [...]}]}. As it is done in MatchesFilter, empty $and and $or
+// sub filters match everything, so they are left out, and empty negated sub filters never match, so they are
+// translated to {$nor: [{}]}.
+func FilterToBSON(filter *Filter, manager ModelManager, identity Identity) (bson.D, error) {
+
+	if filter == nil {
+		panic(fmt.Errorf("elemental: filter cannot be nil"))
+	}
+
+	if err := checkFilterBound(filter); err != nil {
+		return nil, fmt.Errorf("elemental: unable to translate filter: %w", err)
+	}
+
+	var prototype AttributeSpecifiable
+	if manager != nil {
+		var ok bool
+		if prototype, ok = manager.Identifiable(identity).(AttributeSpecifiable); !ok {
+			return nil, fmt.Errorf("elemental: unable to translate filter: unknown identity or not an attribute specifiable: %s", identity)
+		}
+	}
+
+	return filterToBSON(filter, prototype)
+}
+
+func filterToBSON(filter *Filter, prototype AttributeSpecifiable) (bson.D, error) {
+
+	items := make([]bson.D, 0, len(filter.operators))
+
+	for i, op := range filter.operators {
+
+		switch op {
+
+		case AndOperator:
+			k, err := bsonFieldName(prototype, filter.keys[i])
+			if err != nil {
+				return nil, err
+			}
+
+			values := make([]any, len(filter.values[i]))
+			for j, v := range filter.values[i] {
+				values[j] = bsonValue(k, v)
+			}
+
+			item, err := comparatorToBSON(k, filter.comparators[i], values)
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, item)
+
+		case AndFilterOperator, OrFilterOperator, NotFilterOperator:
+			subs, name := filter.ands[i], "$and"
+			switch op {
+			case OrFilterOperator:
+				subs, name = filter.ors[i], "$or"
+			case NotFilterOperator:
+				subs = filter.nots[i]
+			}
+
+			subItems := make([]bson.D, len(subs))
+			for j, sub := range subs {
+				subItem, err := filterToBSON(sub, prototype)
+				if err != nil {
+					return nil, err
+				}
+				subItems[j] = subItem
+			}
+
+			// MongoDB rejects empty $and, $or and $nor arrays.
+			switch {
+			case op == NotFilterOperator && len(subItems) == 0:
+				// the negation of a filter that always matches never matches.
+				items = append(items, bson.D{{Key: "$nor", Value: []bson.D{{}}}})
+			case op == NotFilterOperator:
+				items = append(items, bson.D{{Key: "$nor", Value: []bson.D{{{Key: name, Value: subItems}}}}})
+			case len(subItems) > 0:
+				items = append(items, bson.D{{Key: name, Value: subItems}})
+			}
+		}
+	}
+
+	switch len(items) {
+	case 0:
+		return bson.D{}, nil
+	case 1:
+		return items[0], nil
+	default:
+		return bson.D{{Key: "$and", Value: items}}, nil
+	}
+}
+
+func comparatorToBSON(k string, comparator FilterComparator, values []any) (bson.D, error) {
+
+	op := func(name string, value any) bson.D {
+		return bson.D{{Key: k, Value: bson.D{{Key: name, Value: value}}}}
+	}
+
+	regexes := func() []bson.D {
+		out := make([]bson.D, len(values))
+		for i, v := range values {
+			out[i] = op("$regex", v)
+		}
+		return out
+	}
+
+	switch comparator {
+	case EqualComparator:
+		return op("$eq", values[0]), nil
+	case NotEqualComparator:
+		return op("$ne", values[0]), nil
+	case GreaterComparator:
+		return op("$gt", values[0]), nil
+	case GreaterOrEqualComparator:
+		return op("$gte", values[0]), nil
+	case LesserComparator:
+		return op("$lt", values[0]), nil
+	case LesserOrEqualComparator:
+		return op("$lte", values[0]), nil
+	case InComparator, ContainComparator:
+		return op("$in", values), nil
+	case NotInComparator, NotContainComparator:
+		return op("$nin", values), nil
+	case MatchComparator:
+		return bson.D{{Key: "$or", Value: regexes()}}, nil
+	case NotMatchComparator:
+		return bson.D{{Key: "$nor", Value: regexes()}}, nil
+	case ExistsComparator:
+		return op("$exists", true), nil
+	case NotExistsComparator:
+		return op("$exists", false), nil
+	case EqualIgnoreCaseComparator, StartWithComparator, EndWithComparator:
+		var s string
+		switch v := values[0].(type) {
+		case string:
+			s = v
+		case primitive.ObjectID:
+			s = v.Hex()
+		default:
+			return nil, fmt.Errorf("elemental: unable to translate filter: %s expects a string, got %T", translateComparator(comparator), v)
+		}
+		switch comparator {
+		case EqualIgnoreCaseComparator:
+			return op("$regex", primitive.Regex{Pattern: "^" + regexp.QuoteMeta(s) + "$", Options: "i"}), nil
+		case StartWithComparator:
+			return op("$regex", "^"+regexp.QuoteMeta(s)), nil
+		default:
+			return op("$regex", regexp.QuoteMeta(s)+"$"), nil
+		}
+	default:
+		return nil, fmt.Errorf("elemental: unable to translate filter: unknown comparator %d", comparator)
+	}
+}
+
+// bsonFieldName returns the bson field name for the given filter key.
+func bsonFieldName(prototype AttributeSpecifiable, key string) (string, error) {
+
+	// only the root attribute of a dotted path is translated. The rest
+	// of the path targets nested data, like map keys, and is kept as is.
+	root, rest, nested := strings.Cut(key, ".")
+	if nested {
+		rest = "." + rest
+	}
+
+	if prototype == nil {
+		return strings.ToLower(root) + rest, nil
+	}
+
+	spec, ok := specificationForKey(prototype, root)
+	if !ok {
+		return "", fmt.Errorf("elemental: unable to translate filter: unknown attribute %q", key)
+	}
+
+	if spec.BSONFieldName == "" {
+		return strings.ToLower(root) + rest, nil
+	}
+
+	return spec.BSONFieldName + rest, nil
+}
+
+// bsonValue massages the given filter value to be used in a MongoDB query for the given field.
+func bsonValue(k string, v any) any {
+
+	switch vv := v.(type) {
+
+	case time.Duration, FilterRelativeTime:
+		t, _ := relativeTimeValue(vv, time.Now())
+		return t
+
+	case string:
+		if k == "_id" && primitive.IsValidObjectID(vv) {
+			oid, _ := primitive.ObjectIDFromHex(vv)
+			return oid
+		}
+	}
+
+	return v
+}
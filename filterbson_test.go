@@ -0,0 +1,234 @@
+package elemental_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.aporeto.io/elemental"
+	testmodel "go.aporeto.io/elemental/test/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFilterToBSON(t *testing.T) {
+
+	oid := primitive.NewObjectID()
+	date := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		filter        *elemental.Filter
+		manager       elemental.ModelManager
+		identity      elemental.Identity
+		expected      bson.D
+		expectedError bool
+	}{
+		"empty filter": {
+			filter:   elemental.NewFilterComposer().Done(),
+			expected: bson.D{},
+		},
+		"equal without manager": {
+			filter:   elemental.NewFilterComposer().WithKey("firstName").Equals("Alice").Done(),
+			expected: bson.D{{Key: "firstname", Value: bson.D{{Key: "$eq", Value: "Alice"}}}},
+		},
+		"equal with manager": {
+			filter:   elemental.NewFilterComposer().WithKey("parentID").Equals("x").Done(),
+			manager:  testmodel.Manager(),
+			identity: testmodel.UserIdentity,
+			expected: bson.D{{Key: "parentid", Value: bson.D{{Key: "$eq", Value: "x"}}}},
+		},
+		"path without manager": {
+			filter:   elemental.NewFilterComposer().WithKey("Metadata.Owner").Equals("alice").Done(),
+			expected: bson.D{{Key: "metadata.Owner", Value: bson.D{{Key: "$eq", Value: "alice"}}}},
+		},
+		"path with manager": {
+			filter:   elemental.NewFilterComposer().WithKey("parentID.Owner").Equals("alice").Done(),
+			manager:  testmodel.Manager(),
+			identity: testmodel.UserIdentity,
+			expected: bson.D{{Key: "parentid.Owner", Value: bson.D{{Key: "$eq", Value: "alice"}}}},
+		},
+		"identifier with manager": {
+			filter:   elemental.NewFilterComposer().WithKey("ID").Equals(oid.Hex()).Done(),
+			manager:  testmodel.Manager(),
+			identity: testmodel.UserIdentity,
+			expected: bson.D{{Key: "_id", Value: bson.D{{Key: "$eq", Value: oid}}}},
+		},
+		"identifier which is not an object id": {
+			filter:   elemental.NewFilterComposer().WithKey("ID").In("not-an-oid", oid.Hex()).Done(),
+			manager:  testmodel.Manager(),
+			identity: testmodel.UserIdentity,
+			expected: bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: []any{"not-an-oid", oid}}}}},
+		},
+		"not equal": {
+			filter:   elemental.NewFilterComposer().WithKey("a").NotEquals(1).Done(),
+			expected: bson.D{{Key: "a", Value: bson.D{{Key: "$ne", Value: 1}}}},
+		},
+		"ordering": {
+			filter: elemental.NewFilterComposer().
+				WithKey("a").GreaterThan(1).
+				WithKey("b").GreaterOrEqualThan(2).
+				WithKey("c").LesserThan(date).
+				WithKey("d").LesserOrEqualThan(4).
+				Done(),
+			expected: bson.D{{Key: "$and", Value: []bson.D{
+				{{Key: "a", Value: bson.D{{Key: "$gt", Value: 1}}}},
+				{{Key: "b", Value: bson.D{{Key: "$gte", Value: 2}}}},
+				{{Key: "c", Value: bson.D{{Key: "$lt", Value: date}}}},
+				{{Key: "d", Value: bson.D{{Key: "$lte", Value: 4}}}},
+			}}},
+		},
+		"in and contains": {
+			filter: elemental.NewFilterComposer().
+				WithKey("a").In(1, 2).
+				WithKey("b").NotIn(3).
+				WithKey("c").Contains("x").
+				WithKey("d").NotContains("y", "z").
+				Done(),
+			expected: bson.D{{Key: "$and", Value: []bson.D{
+				{{Key: "a", Value: bson.D{{Key: "$in", Value: []any{1, 2}}}}},
+				{{Key: "b", Value: bson.D{{Key: "$nin", Value: []any{3}}}}},
+				{{Key: "c", Value: bson.D{{Key: "$in", Value: []any{"x"}}}}},
+				{{Key: "d", Value: bson.D{{Key: "$nin", Value: []any{"y", "z"}}}}},
+			}}},
+		},
+		"matches": {
+			filter: elemental.NewFilterComposer().WithKey("a").Matches("^x", "y$").Done(),
+			expected: bson.D{{Key: "$or", Value: []bson.D{
+				{{Key: "a", Value: bson.D{{Key: "$regex", Value: "^x"}}}},
+				{{Key: "a", Value: bson.D{{Key: "$regex", Value: "y$"}}}},
+			}}},
+		},
+		"string comparators": {
+			filter: elemental.NewFilterComposer().
+				WithKey("a").EqualsIgnoreCase("a.b").
+				WithKey("b").StartsWith("/ns").
+				WithKey("c").EndsWith("(x)").
+				Done(),
+			expected: bson.D{{Key: "$and", Value: []bson.D{
+				{{Key: "a", Value: bson.D{{Key: "$regex", Value: primitive.Regex{Pattern: `^a\.b$`, Options: "i"}}}}},
+				{{Key: "b", Value: bson.D{{Key: "$regex", Value: "^/ns"}}}},
+				{{Key: "c", Value: bson.D{{Key: "$regex", Value: `\(x\)$`}}}},
+			}}},
+		},
+		"string comparator with a non string value": {
+			filter:        elemental.NewFilterComposer().WithKey("a").EndsWith(1).Done(),
+			expectedError: true,
+		},
+		"exists": {
+			filter: elemental.NewFilterComposer().WithKey("a").Exists().WithKey("b").NotExists().Done(),
+			expected: bson.D{{Key: "$and", Value: []bson.D{
+				{{Key: "a", Value: bson.D{{Key: "$exists", Value: true}}}},
+				{{Key: "b", Value: bson.D{{Key: "$exists", Value: false}}}},
+			}}},
+		},
+		"sub filters": {
+			filter: elemental.NewFilterComposer().
+				WithKey("a").Equals(1).
+				Or(
+					elemental.NewFilterComposer().WithKey("b").Equals(2).Done(),
+					elemental.NewFilterComposer().And(
+						elemental.NewFilterComposer().WithKey("c").Equals(3).Done(),
+						elemental.NewFilterComposer().WithKey("d").Equals(4).Done(),
+					).Done(),
+				).
+				Done(),
+			expected: bson.D{{Key: "$and", Value: []bson.D{
+				{{Key: "a", Value: bson.D{{Key: "$eq", Value: 1}}}},
+				{{Key: "$or", Value: []bson.D{
+					{{Key: "b", Value: bson.D{{Key: "$eq", Value: 2}}}},
+					{{Key: "$and", Value: []bson.D{
+						{{Key: "c", Value: bson.D{{Key: "$eq", Value: 3}}}},
+						{{Key: "d", Value: bson.D{{Key: "$eq", Value: 4}}}},
+					}}},
+				}}},
+			}}},
+		},
+		"negated sub filter": {
+			filter: elemental.NewFilterComposer().
+				Not(
+					elemental.NewFilterComposer().WithKey("a").Equals(1).Done(),
+					elemental.NewFilterComposer().WithKey("b").NotMatches("^x").Done(),
+				).
+				Done(),
+			expected: bson.D{{Key: "$nor", Value: []bson.D{
+				{{Key: "$and", Value: []bson.D{
+					{{Key: "a", Value: bson.D{{Key: "$eq", Value: 1}}}},
+					{{Key: "$nor", Value: []bson.D{
+						{{Key: "b", Value: bson.D{{Key: "$regex", Value: "^x"}}}},
+					}}},
+				}}},
+			}}},
+		},
+		"empty negated sub filter": {
+			filter:   elemental.NewFilterComposer().Not().Done(),
+			expected: bson.D{{Key: "$nor", Value: []bson.D{{}}}},
+		},
+		"empty sub filters": {
+			filter: elemental.NewFilterComposer().
+				WithKey("a").Equals(1).
+				And().
+				Or().
+				Done(),
+			expected: bson.D{{Key: "a", Value: bson.D{{Key: "$eq", Value: 1}}}},
+		},
+		"only empty sub filters": {
+			filter:   elemental.NewFilterComposer().And().Or().Done(),
+			expected: bson.D{},
+		},
+		"unknown attribute with manager": {
+			filter:        elemental.NewFilterComposer().WithKey("unknown").Equals(1).Done(),
+			manager:       testmodel.Manager(),
+			identity:      testmodel.UserIdentity,
+			expectedError: true,
+		},
+		"unknown attribute in a sub filter with manager": {
+			filter: elemental.NewFilterComposer().Or(
+				elemental.NewFilterComposer().WithKey("firstName").Equals(1).Done(),
+				elemental.NewFilterComposer().WithKey("unknown").Equals(1).Done(),
+			).Done(),
+			manager:       testmodel.Manager(),
+			identity:      testmodel.UserIdentity,
+			expectedError: true,
+		},
+		"unknown identity with manager": {
+			filter:        elemental.NewFilterComposer().WithKey("firstName").Equals(1).Done(),
+			manager:       testmodel.Manager(),
+			identity:      elemental.MakeIdentity("unknown", "unknowns"),
+			expectedError: true,
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			out, err := elemental.FilterToBSON(tc.filter, tc.manager, tc.identity)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("error expectation failed: expected an error: %t, actual error: %v", tc.expectedError, err)
+			}
+
+			if !reflect.DeepEqual(out, tc.expected) {
+				t.Errorf("unexpected translation\nexpected: %#v\nactual:   %#v", tc.expected, out)
+			}
+		})
+	}
+}
+
+func TestFilterToBSON_Duration(t *testing.T) {
+
+	filter := elemental.NewFilterComposer().WithKey("date").GreaterThan(-time.Hour).Done()
+
+	before := time.Now().Add(-time.Hour)
+	out, err := elemental.FilterToBSON(filter, nil, elemental.EmptyIdentity)
+	if err != nil {
+		t.Fatalf("did not expect to get an error, but received: %s", err)
+	}
+
+	value, ok := out[0].Value.(bson.D)[0].Value.(time.Time)
+	if !ok {
+		t.Fatalf("expected the duration to be translated to a time.Time, got %T", out[0].Value.(bson.D)[0].Value)
+	}
+
+	if value.Before(before) || value.After(time.Now().Add(-time.Hour)) {
+		t.Errorf("expected the duration to be relative to now, got %s", value)
+	}
+}
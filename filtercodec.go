@@ -0,0 +1,329 @@
+package elemental
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/ugorji/go/codec"
+)
+
+// Types of the values of an encoded Filter.
+const (
+	filterValueTypeNull        = "null"
+	filterValueTypeString      = "string"
+	filterValueTypeInteger     = "integer"
+	filterValueTypeFloat       = "float"
+	filterValueTypeBoolean     = "boolean"
+	filterValueTypeTime        = "time"
+	filterValueTypeDuration    = "duration"
+	filterValueTypeList        = "list"
+	filterValueTypePlaceholder = "placeholder"
+	filterValueTypeRelative    = "relativetime"
+)
+
+// filterTermNode is the decoded form of one operator of a Filter. It either holds
+// a key, a comparator and its values, or one kind of sub filters.
+type filterTermNode struct {
+	Key        string            `msgpack:"key,omitempty" json:"key,omitempty"`
+	Comparator string            `msgpack:"comparator,omitempty" json:"comparator,omitempty"`
+	Values     []filterValueNode `msgpack:"values,omitempty" json:"values,omitempty"`
+	And        []*Filter         `msgpack:"and,omitempty" json:"and,omitempty"`
+	Or         []*Filter         `msgpack:"or,omitempty" json:"or,omitempty"`
+	Not        []*Filter         `msgpack:"not,omitempty" json:"not,omitempty"`
+}
+
+// filterValueNode is the encoded form of a value of a Filter. The type is always
+// given so the value can be decoded to the same Go type whatever the encoding is.
+type filterValueNode struct {
+	Type  string            `msgpack:"type" json:"type"`
+	Value any               `msgpack:"value" json:"value"`
+	Items []filterValueNode `msgpack:"items,omitempty" json:"items,omitempty"`
+}
+
+var filterComparatorsByName = func() map[string]FilterComparator {
+
+	out := map[string]FilterComparator{}
+	for c := EqualComparator; c < emptyComparator; c++ {
+		out[translateComparator(c)] = c
+	}
+
+	return out
+}()
+
+// CodecEncodeSelf implements codec.Selfer. It allows to encode the Filter with Encode.
+//
+// A Filter is encoded as the list of its terms. A term is an object that either holds a "key",
+// a "comparator" (as written in the string representation of the filter, like "==" or "not in")
+// and its "values", or one of "and", "or" and "not", holding a list of sub filters. For instance:
+//
+//	[
+//	  {"key": "name", "comparator": "==", "values": [{"type": "string", "value": "a"}]},
+//	  {"or": [
+//	    [{"key": "date", "comparator": ">", "values": [{"type": "duration", "value": "-1h0m0s"}]}],
+//	    [{"key": "tags", "comparator": "exists", "values": [{"type": "boolean", "value": true}]}]
+//	  ]}
+//	]
+//
+// Values are tagged with their type, which is one of null, string, integer, float, boolean,
+// time (encoded using RFC3339), duration (encoded like time.Duration.String), relativetime (encoded
+// like FilterRelativeTime.String), placeholder (encoded as its name, see FilterPlaceholder) or list,
+// which holds the encoded values of its "items".
+func (f *Filter) CodecEncodeSelf(e *codec.Encoder) {
+
+	terms, err := encodeFilter(f)
+	if err != nil {
+		panic(err)
+	}
+
+	e.MustEncode(terms)
+}
+
+// CodecDecodeSelf implements codec.Selfer. It allows to decode a Filter encoded by CodecEncodeSelf
+// with Decode.
+func (f *Filter) CodecDecodeSelf(d *codec.Decoder) {
+
+	var terms []filterTermNode
+	d.MustDecode(&terms)
+
+	decoded, err := decodeFilter(terms)
+	if err != nil {
+		panic(err)
+	}
+
+	*f = *decoded
+}
+
+func encodeFilter(f *Filter) ([]map[string]any, error) {
+
+	// terms are encoded as maps so sub filters are always written, even when there are none.
+	terms := make([]map[string]any, len(f.operators))
+
+	for i, op := range f.operators {
+
+		switch op {
+
+		case AndOperator:
+			values := make([]filterValueNode, len(f.values[i]))
+			for j, v := range f.values[i] {
+				n, err := encodeFilterValue(v)
+				if err != nil {
+					return nil, fmt.Errorf("elemental: unable to encode filter: invalid value for key %q: %w", f.keys[i], err)
+				}
+				values[j] = n
+			}
+
+			terms[i] = map[string]any{
+				"key":        f.keys[i],
+				"comparator": translateComparator(f.comparators[i]),
+				"values":     values,
+			}
+
+		case AndFilterOperator:
+			terms[i] = map[string]any{"and": append([]*Filter{}, f.ands[i]...)}
+
+		case OrFilterOperator:
+			terms[i] = map[string]any{"or": append([]*Filter{}, f.ors[i]...)}
+
+		case NotFilterOperator:
+			terms[i] = map[string]any{"not": append([]*Filter{}, f.nots[i]...)}
+		}
+	}
+
+	return terms, nil
+}
+
+func encodeFilterValue(value any) (filterValueNode, error) {
+
+	switch v := value.(type) {
+	case nil:
+		return filterValueNode{Type: filterValueTypeNull}, nil
+	case time.Time:
+		return filterValueNode{Type: filterValueTypeTime, Value: v.Format(time.RFC3339Nano)}, nil
+	case time.Duration:
+		return filterValueNode{Type: filterValueTypeDuration, Value: v.String()}, nil
+	case FilterPlaceholder:
+		return filterValueNode{Type: filterValueTypePlaceholder, Value: v.Name}, nil
+	case FilterRelativeTime:
+		return filterValueNode{Type: filterValueTypeRelative, Value: v.String()}, nil
+	}
+
+	v := reflect.ValueOf(value)
+
+	switch {
+	case v.Kind() == reflect.String:
+		return filterValueNode{Type: filterValueTypeString, Value: v.String()}, nil
+	case v.Kind() == reflect.Bool:
+		return filterValueNode{Type: filterValueTypeBoolean, Value: v.Bool()}, nil
+	case isInt(v):
+		return filterValueNode{Type: filterValueTypeInteger, Value: v.Int()}, nil
+	case isUint(v):
+		if v.Uint() > math.MaxInt64 {
+			return filterValueNode{}, fmt.Errorf("integer %d overflows int64", v.Uint())
+		}
+		return filterValueNode{Type: filterValueTypeInteger, Value: int64(v.Uint())}, nil
+	case isNumber(v):
+		return filterValueNode{Type: filterValueTypeFloat, Value: v.Float()}, nil
+	case isArrayLike(v):
+		items := make([]filterValueNode, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			n, err := encodeFilterValue(v.Index(i).Interface())
+			if err != nil {
+				return filterValueNode{}, err
+			}
+			items[i] = n
+		}
+		return filterValueNode{Type: filterValueTypeList, Items: items}, nil
+	default:
+		return filterValueNode{}, fmt.Errorf("unsupported type %T", value)
+	}
+}
+
+func decodeFilter(terms []filterTermNode) (*Filter, error) {
+
+	f := NewFilter()
+
+	for _, t := range terms {
+
+		var kinds int
+		for _, set := range []bool{t.Key != "", t.And != nil, t.Or != nil, t.Not != nil} {
+			if set {
+				kinds++
+			}
+		}
+		if kinds != 1 {
+			return nil, fmt.Errorf("elemental: unable to decode filter: a term must have exactly one of key, and, or and not")
+		}
+
+		switch {
+
+		case t.And != nil:
+			if err := checkSubFilters(t.And); err != nil {
+				return nil, err
+			}
+			f.And(t.And...)
+
+		case t.Or != nil:
+			if err := checkSubFilters(t.Or); err != nil {
+				return nil, err
+			}
+			f.Or(t.Or...)
+
+		case t.Not != nil:
+			if err := checkSubFilters(t.Not); err != nil {
+				return nil, err
+			}
+			f.Not(t.Not...)
+
+		default:
+			comparator, ok := filterComparatorsByName[t.Comparator]
+			if !ok {
+				return nil, fmt.Errorf("elemental: unable to decode filter: unknown comparator %q for key %q", t.Comparator, t.Key)
+			}
+
+			values := make(FilterValue, len(t.Values))
+			for i, n := range t.Values {
+				v, err := decodeFilterValue(n)
+				if err != nil {
+					return nil, fmt.Errorf("elemental: unable to decode filter: invalid value for key %q: %w", t.Key, err)
+				}
+				values[i] = v
+			}
+
+			// comparators that are not set using a list of values only have one.
+			if !isListComparator(comparator) && len(values) != 1 {
+				return nil, fmt.Errorf("elemental: unable to decode filter: comparator %q for key %q expects one value, got %d", t.Comparator, t.Key, len(values))
+			}
+
+			f.addTerm(t.Key, comparator, values)
+		}
+	}
+
+	return f, nil
+}
+
+func checkSubFilters(subs []*Filter) error {
+
+	for _, sub := range subs {
+		if sub == nil {
+			return fmt.Errorf("elemental: unable to decode filter: sub filters cannot be null")
+		}
+	}
+
+	return nil
+}
+
+func decodeFilterValue(n filterValueNode) (any, error) {
+
+	if n.Type == filterValueTypeNull {
+		return nil, nil
+	}
+
+	if n.Type == filterValueTypeList {
+		items := make([]any, len(n.Items))
+		for i, item := range n.Items {
+			v, err := decodeFilterValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = v
+		}
+		return items, nil
+	}
+
+	v := reflect.ValueOf(n.Value)
+
+	switch n.Type {
+
+	case filterValueTypeString:
+		if v.Kind() == reflect.String {
+			return v.String(), nil
+		}
+
+	case filterValueTypeBoolean:
+		if v.Kind() == reflect.Bool {
+			return v.Bool(), nil
+		}
+
+	case filterValueTypeInteger:
+		switch {
+		case isInt(v):
+			return v.Int(), nil
+		case isUint(v) && v.Uint() <= math.MaxInt64:
+			return int64(v.Uint()), nil
+		case isNumber(v) && toFloat(v) == math.Trunc(toFloat(v)):
+			return int64(toFloat(v)), nil
+		}
+
+	case filterValueTypeFloat:
+		if isNumber(v) {
+			return toFloat(v), nil
+		}
+
+	case filterValueTypeTime:
+		if v.Kind() == reflect.String {
+			return time.Parse(time.RFC3339Nano, v.String())
+		}
+
+	case filterValueTypeDuration:
+		if v.Kind() == reflect.String {
+			return time.ParseDuration(v.String())
+		}
+
+	case filterValueTypeRelative:
+		if v.Kind() == reflect.String {
+			return parseFilterRelativeTime(v.String())
+		}
+
+	case filterValueTypePlaceholder:
+		if v.Kind() == reflect.String && placeholderPattern.MatchString("$"+v.String()) {
+			return FilterPlaceholder{Name: v.String()}, nil
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown value type %q", n.Type)
+	}
+
+	return nil, fmt.Errorf("invalid %s: %v", n.Type, n.Value)
+}
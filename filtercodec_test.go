@@ -0,0 +1,176 @@
+package elemental_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.aporeto.io/elemental"
+)
+
+func TestFilter_EncodeDecode(t *testing.T) {
+
+	date := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	filters := map[string]*elemental.Filter{
+		"empty filter": elemental.NewFilterComposer().Done(),
+		"all comparators": elemental.NewFilterComposer().
+			WithKey("a").Equals("x").
+			WithKey("b").NotEquals("y").
+			WithKey("c").GreaterThan(int64(1)).
+			WithKey("d").GreaterOrEqualThan(1.5).
+			WithKey("e").LesserThan(date).
+			WithKey("f").LesserOrEqualThan(-time.Hour).
+			WithKey("g").In("x", "y").
+			WithKey("h").NotIn(true, false).
+			WithKey("i").Contains("x").
+			WithKey("j").NotContains("x", "y").
+			WithKey("k").Matches("^x", `\d"`).
+			WithKey("l").NotMatches("^x").
+			WithKey("m").Exists().
+			WithKey("n").NotExists().
+			WithKey("o").EqualsIgnoreCase("X").
+			WithKey("p").StartsWith("x").
+			WithKey("q").EndsWith("x").
+			WithKey("r.s").Equals([]any{"x", int64(1)}).
+			Done(),
+		"sub filters": elemental.NewFilterComposer().
+			WithKey("a").Equals(int64(1)).
+			Or(
+				elemental.NewFilterComposer().WithKey("b").Equals(int64(2)).Done(),
+				elemental.NewFilterComposer().And(
+					elemental.NewFilterComposer().WithKey("c").Equals(int64(3)).Done(),
+					elemental.NewFilterComposer().Not(
+						elemental.NewFilterComposer().WithKey("d").Equals(int64(4)).Done(),
+					).Done(),
+				).Done(),
+			).
+			Not().
+			Done(),
+	}
+
+	for _, encoding := range []elemental.EncodingType{elemental.EncodingTypeJSON, elemental.EncodingTypeMSGPACK} {
+		for description, filter := range filters {
+			t.Run(string(encoding)+" "+description, func(t *testing.T) {
+
+				data, err := elemental.Encode(encoding, filter)
+				if err != nil {
+					t.Fatalf("unable to encode filter: %s", err)
+				}
+
+				decoded := elemental.NewFilter()
+				if err := elemental.Decode(encoding, data, decoded); err != nil {
+					t.Fatalf("unable to decode filter: %s", err)
+				}
+
+				if decoded.String() != filter.String() {
+					t.Errorf("unexpected filter\nexpected: %s\nactual:   %s", filter, decoded)
+				}
+
+				if !reflect.DeepEqual(decoded.Values(), filter.Values()) {
+					t.Errorf("unexpected values\nexpected: %#v\nactual:   %#v", filter.Values(), decoded.Values())
+				}
+
+				if !reflect.DeepEqual(decoded.Operators(), filter.Operators()) {
+					t.Errorf("unexpected operators\nexpected: %#v\nactual:   %#v", filter.Operators(), decoded.Operators())
+				}
+			})
+		}
+	}
+}
+
+func TestFilter_EncodeDecode_Null(t *testing.T) {
+
+	filter := elemental.NewFilterComposer().WithKey("a").NotEquals(nil).WithKey("b").In(nil, []any{nil}).Done()
+
+	for _, encoding := range []elemental.EncodingType{elemental.EncodingTypeJSON, elemental.EncodingTypeMSGPACK} {
+		t.Run(string(encoding), func(t *testing.T) {
+
+			data, err := elemental.Encode(encoding, filter)
+			if err != nil {
+				t.Fatalf("unable to encode filter: %s", err)
+			}
+
+			decoded := elemental.NewFilter()
+			if err := elemental.Decode(encoding, data, decoded); err != nil {
+				t.Fatalf("unable to decode filter: %s", err)
+			}
+
+			if !reflect.DeepEqual(decoded.Values(), filter.Values()) {
+				t.Errorf("unexpected values\nexpected: %#v\nactual:   %#v", filter.Values(), decoded.Values())
+			}
+		})
+	}
+}
+
+func TestFilter_EncodeJSON(t *testing.T) {
+
+	filter := elemental.NewFilterComposer().
+		WithKey("name").Equals("a").
+		Or(
+			elemental.NewFilterComposer().WithKey("date").GreaterThan(-time.Hour).Done(),
+			elemental.NewFilterComposer().WithKey("tags").Exists().Done(),
+		).
+		Done()
+
+	data, err := elemental.Encode(elemental.EncodingTypeJSON, filter)
+	if err != nil {
+		t.Fatalf("unable to encode filter: %s", err)
+	}
+
+	expected := `[` +
+		`{"comparator":"==","key":"name","values":[{"type":"string","value":"a"}]},` +
+		`{"or":[` +
+		`[{"comparator":"\u003e","key":"date","values":[{"type":"duration","value":"-1h0m0s"}]}],` +
+		`[{"comparator":"exists","key":"tags","values":[{"type":"boolean","value":true}]}]` +
+		`]}` +
+		`]`
+
+	if string(data) != expected {
+		t.Errorf("unexpected encoding\nexpected: %s\nactual:   %s", expected, string(data))
+	}
+}
+
+func TestFilter_DecodeErrors(t *testing.T) {
+
+	tests := map[string]string{
+		"not a list":              `{"key": "a"}`,
+		"empty term":              `[{}]`,
+		"term with two kinds":     `[{"key": "a", "comparator": "exists", "values": [{"type": "boolean", "value": true}], "and": []}]`,
+		"unknown comparator":      `[{"key": "a", "comparator": "~=", "values": [{"type": "string", "value": "a"}]}]`,
+		"missing value":           `[{"key": "a", "comparator": "=="}]`,
+		"too many values":         `[{"key": "a", "comparator": "==", "values": [{"type": "null"}, {"type": "null"}]}]`,
+		"unknown value type":      `[{"key": "a", "comparator": "==", "values": [{"type": "uuid", "value": "a"}]}]`,
+		"value not of its type":   `[{"key": "a", "comparator": "==", "values": [{"type": "boolean", "value": "true"}]}]`,
+		"non integral integer":    `[{"key": "a", "comparator": "==", "values": [{"type": "integer", "value": 1.5}]}]`,
+		"invalid time":            `[{"key": "a", "comparator": "==", "values": [{"type": "time", "value": "yesterday"}]}]`,
+		"invalid duration":        `[{"key": "a", "comparator": "==", "values": [{"type": "duration", "value": "1 hour"}]}]`,
+		"invalid value in a list": `[{"key": "a", "comparator": "==", "values": [{"type": "list", "items": [{"type": "float", "value": "1"}]}]}]`,
+		"null sub filter":         `[{"or": [null]}]`,
+		"invalid sub filter":      `[{"not": [[{}]]}]`,
+	}
+
+	for description, data := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			err := elemental.Decode(elemental.EncodingTypeJSON, []byte(data), elemental.NewFilter())
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+
+			if !strings.HasPrefix(err.Error(), "unable to decode application/json:") {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestFilter_EncodeErrors(t *testing.T) {
+
+	filter := elemental.NewFilterComposer().WithKey("a").Equals(struct{}{}).Done()
+
+	if _, err := elemental.Encode(elemental.EncodingTypeJSON, filter); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
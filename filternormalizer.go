@@ -0,0 +1,205 @@
+package elemental
+
+import (
+	"slices"
+	"strings"
+)
+
+// NormalizeFilter returns a new filter that is semantically equivalent to the given filter, in a canonical form.
+// The given filter is not modified.
+//
+// The normalization:
+//   - flattens nested and sub filters into their parent
+//   - flattens nested or sub filters into their parent or sub filter
+//   - removes duplicate terms, sub filters and values
+//   - sorts terms, sub filters and values of in, not in, contains, not contains and matches deterministically
+//   - folds in and not in with a single value into == and !=
+//   - removes or sub filters that are empty or contain an empty sub filter, as they always match
+//   - removes double negations
+//
+// Two filters that only differ by the points above will have the same normalized form.
+func NormalizeFilter(filter *Filter) *Filter {
+
+	if filter == nil {
+		return nil
+	}
+
+	return buildNormalizedFilter(normalizeConjunction(filter))
+}
+
+// CanonicalFilterString returns the string representation of the normalized version of the given filter.
+// It can be used as a cache key, as semantically identical filters will return the same string.
+func CanonicalFilterString(filter *Filter) string {
+
+	if filter == nil {
+		return ""
+	}
+
+	return NormalizeFilter(filter).String()
+}
+
+// normalizedTerm is either a simple term, an or sub filter if disjuncts is not nil,
+// or a negated sub filter if negated is true.
+type normalizedTerm struct {
+	key        string
+	comparator FilterComparator
+	values     FilterValue
+	disjuncts  [][]normalizedTerm
+	negated    bool
+	negation   []normalizedTerm
+	repr       string
+}
+
+// normalizeConjunction returns the flattened, sorted and deduplicated list of terms
+// that must all match for the given filter to match.
+func normalizeConjunction(filter *Filter) []normalizedTerm {
+
+	var terms []normalizedTerm
+
+	for i, op := range filter.operators {
+
+		switch op {
+
+		case AndOperator:
+			terms = append(terms, normalizeTerm(filter.keys[i], filter.comparators[i], filter.values[i]))
+
+		case AndFilterOperator:
+			for _, sub := range filter.ands[i] {
+				terms = append(terms, normalizeConjunction(sub)...)
+			}
+
+		case OrFilterOperator:
+			var disjuncts [][]normalizedTerm
+			var alwaysTrue bool
+
+			for _, sub := range filter.ors[i] {
+
+				conj := normalizeConjunction(sub)
+
+				switch {
+				case len(conj) == 0:
+					alwaysTrue = true
+				case len(conj) == 1 && conj[0].disjuncts != nil:
+					disjuncts = append(disjuncts, conj[0].disjuncts...)
+				default:
+					disjuncts = append(disjuncts, conj)
+				}
+			}
+
+			if alwaysTrue || len(disjuncts) == 0 {
+				continue
+			}
+
+			disjuncts = sortAndCompactBy(disjuncts, func(c []normalizedTerm) string {
+				return buildNormalizedFilter(c).String()
+			})
+
+			if len(disjuncts) == 1 {
+				terms = append(terms, disjuncts[0]...)
+				continue
+			}
+
+			term := normalizedTerm{disjuncts: disjuncts}
+			term.repr = buildNormalizedFilter([]normalizedTerm{term}).String()
+			terms = append(terms, term)
+
+		case NotFilterOperator:
+			var conj []normalizedTerm
+			for _, sub := range filter.nots[i] {
+				conj = append(conj, normalizeConjunction(sub)...)
+			}
+			conj = sortAndCompactBy(conj, func(t normalizedTerm) string { return t.repr })
+
+			if len(conj) == 1 && conj[0].negated {
+				terms = append(terms, conj[0].negation...)
+				continue
+			}
+
+			term := normalizedTerm{negated: true, negation: conj}
+			term.repr = buildNormalizedFilter([]normalizedTerm{term}).String()
+			terms = append(terms, term)
+		}
+	}
+
+	return sortAndCompactBy(terms, func(t normalizedTerm) string { return t.repr })
+}
+
+func normalizeTerm(key string, comparator FilterComparator, values FilterValue) normalizedTerm {
+
+	values = append(FilterValue{}, values...)
+
+	switch comparator {
+
+	case InComparator, NotInComparator, ContainComparator, NotContainComparator, MatchComparator, NotMatchComparator:
+		values = sortAndCompactBy(values, func(v any) string { return translateValue(InComparator, v) })
+
+		if len(values) == 1 {
+			switch comparator {
+			case InComparator:
+				comparator = EqualComparator
+			case NotInComparator:
+				comparator = NotEqualComparator
+			}
+		}
+	}
+
+	term := normalizedTerm{
+		key:        key,
+		comparator: comparator,
+		values:     values,
+	}
+	term.repr = buildNormalizedFilter([]normalizedTerm{term}).String()
+
+	return term
+}
+
+func buildNormalizedFilter(terms []normalizedTerm) *Filter {
+
+	f := NewFilter()
+
+	for _, t := range terms {
+
+		if t.negated {
+			f.Not(buildNormalizedFilter(t.negation))
+			continue
+		}
+
+		if t.disjuncts == nil {
+			f.addTerm(t.key, t.comparator, t.values)
+			continue
+		}
+
+		subs := make(SubFilter, len(t.disjuncts))
+		for i, d := range t.disjuncts {
+			subs[i] = buildNormalizedFilter(d)
+		}
+		f.Or(subs...)
+	}
+
+	return f
+}
+
+// sortAndCompactBy sorts the given items using the given key function and
+// removes the items that have the same key.
+func sortAndCompactBy[T any](items []T, key func(T) string) []T {
+
+	type keyed struct {
+		key  string
+		item T
+	}
+
+	ks := make([]keyed, len(items))
+	for i, item := range items {
+		ks[i] = keyed{key: key(item), item: item}
+	}
+
+	slices.SortStableFunc(ks, func(a, b keyed) int { return strings.Compare(a.key, b.key) })
+	ks = slices.CompactFunc(ks, func(a, b keyed) bool { return a.key == b.key })
+
+	out := make([]T, len(ks))
+	for i, k := range ks {
+		out[i] = k.item
+	}
+
+	return out
+}
@@ -0,0 +1,163 @@
+package elemental_test
+
+import (
+	"testing"
+
+	"go.aporeto.io/elemental"
+)
+
+func TestNormalizeFilter(t *testing.T) {
+
+	tests := map[string]struct {
+		filter   string
+		expected string
+	}{
+		"should sort terms": {
+			filter:   `b == 2 and a == 1`,
+			expected: `a == 1 and b == 2`,
+		},
+		"should flatten and sub filters": {
+			filter:   `a == 1 and (b == 2 and (c == 3 and d == 4))`,
+			expected: `a == 1 and b == 2 and c == 3 and d == 4`,
+		},
+		"should remove duplicate terms": {
+			filter:   `a == 1 and b == 2 and a == 1`,
+			expected: `a == 1 and b == 2`,
+		},
+		"should sort and flatten or sub filters": {
+			filter:   `c == 3 or (b == 2 or a == 1)`,
+			expected: `((a == 1) or (b == 2) or (c == 3))`,
+		},
+		"should remove duplicate or sub filters": {
+			filter:   `a == 1 or b == 2 or a == 1`,
+			expected: `((a == 1) or (b == 2))`,
+		},
+		"should fold an or sub filter with a single distinct sub filter": {
+			filter:   `c == 3 and (a == 1 or a == 1)`,
+			expected: `a == 1 and c == 3`,
+		},
+		"should sort the terms of the or sub filters": {
+			filter:   `(b == 2 and a == 1) or c == 3`,
+			expected: `((a == 1 and b == 2) or (c == 3))`,
+		},
+		"should fold not in with a single value": {
+			filter:   `a not in ["x"]`,
+			expected: `a != "x"`,
+		},
+		"should fold in with a single value": {
+			filter:   `a in ["x", "x"]`,
+			expected: `a == "x"`,
+		},
+		"should sort and deduplicate the values of in": {
+			filter:   `a in ["z", "x", "y", "x"]`,
+			expected: `a in ["x", "y", "z"]`,
+		},
+		"should sort and deduplicate the values of not contains": {
+			filter:   `a not contains ["z", "x", "x"]`,
+			expected: `a not contains ["x", "z"]`,
+		},
+		"should normalize negated sub filters": {
+			filter:   `not (b == 2 and (a == 1 and b == 2)) and c == 3`,
+			expected: `c == 3 and not ((a == 1 and b == 2))`,
+		},
+		"should remove double negations": {
+			filter:   `not (not (b == 2 and a == 1))`,
+			expected: `a == 1 and b == 2`,
+		},
+		"should sort the values of not matches": {
+			filter:   `a not matches ["^b", "^a"]`,
+			expected: `a not matches ["^a", "^b"]`,
+		},
+		"should sort the values of matches": {
+			filter:   `a matches ["^b", "^a"]`,
+			expected: `a matches ["^a", "^b"]`,
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			filter, err := elemental.NewFilterFromString(tc.filter)
+			if err != nil {
+				t.Fatalf("test setup invalid - unable to parse filter: %s", err)
+			}
+
+			original := filter.String()
+
+			if out := elemental.NormalizeFilter(filter).String(); out != tc.expected {
+				t.Errorf("unexpected normalized filter\nexpected: %s\nactual:   %s", tc.expected, out)
+			}
+
+			if filter.String() != original {
+				t.Errorf("the original filter should not have been modified")
+			}
+
+			// the normalized form must be stable.
+			normalized, err := elemental.NewFilterFromString(tc.expected)
+			if err != nil {
+				t.Fatalf("unable to parse normalized filter: %s", err)
+			}
+
+			if out := elemental.CanonicalFilterString(normalized); out != tc.expected {
+				t.Errorf("normalized filter is not stable\nexpected: %s\nactual:   %s", tc.expected, out)
+			}
+		})
+	}
+}
+
+func TestCanonicalFilterString(t *testing.T) {
+
+	equivalents := [][]string{
+		{
+			`a == 1 and b == 2`,
+			`b == 2 and a == 1`,
+			`(b == 2) and (a == 1 and b == 2)`,
+		},
+		{
+			`namespace == "/a" and (status in ["x", "y"] or name == "n")`,
+			`(name == "n" or status in ["y", "x"]) and namespace == "/a"`,
+			`(name == "n" or (status in ["y", "x", "y"] or name == "n")) and namespace == "/a"`,
+		},
+	}
+
+	for _, filters := range equivalents {
+
+		var expected string
+		for i, s := range filters {
+
+			filter, err := elemental.NewFilterFromString(s)
+			if err != nil {
+				t.Fatalf("test setup invalid - unable to parse filter: %s", err)
+			}
+
+			out := elemental.CanonicalFilterString(filter)
+			if i == 0 {
+				expected = out
+				continue
+			}
+
+			if out != expected {
+				t.Errorf("expected %q and %q to have the same canonical string\nexpected: %s\nactual:   %s", filters[0], s, expected, out)
+			}
+		}
+	}
+
+	if elemental.CanonicalFilterString(nil) != "" {
+		t.Errorf("expected the canonical string of a nil filter to be empty")
+	}
+}
+
+func TestNormalizeFilter_Matching(t *testing.T) {
+
+	filter := elemental.NewFilterComposer().
+		WithKey("a").Equals(1).
+		Or(
+			elemental.NewFilterComposer().WithKey("b").NotIn(2).Done(),
+			elemental.NewFilterComposer().Done(),
+		).
+		Done()
+
+	if out := elemental.NormalizeFilter(filter).String(); out != `a == 1` {
+		t.Errorf("expected an or sub filter containing an empty filter to be removed, got: %s", out)
+	}
+}
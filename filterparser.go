@@ -14,6 +14,7 @@ package elemental
 import (
 	"fmt"
 	"regexp"
+	"regexp/syntax"
 	"strconv"
 	"strings"
 	"time"
@@ -48,34 +49,41 @@ const (
 	parserTokenRIGHTSQUAREBRACKET
 	parserTokenCOMMA
 	parserTokenNOTCONTAINS
-	// parserTokenNOTMATCHES not implemented yet in filters.
+	parserTokenNOTMATCHES
 	parserTokenIN
 	parserTokenNOTIN
 	parserTokenNOT
 	parserTokenEXISTS
 	parserTokenNOTEXISTS
+	parserTokenEQUALIGNORECASE
+	parserTokenSTARTSWITH
+	parserTokenENDSWITH
 )
 
 const (
-	wordAND         = "AND"
-	wordCONTAINS    = "CONTAINS"
-	wordEQUAL       = "=="
-	wordFALSE       = "FALSE"
-	wordGT          = ">"
-	wordGTE         = ">="
-	wordIN          = "IN"
-	wordLT          = "<"
-	wordLTE         = "<="
-	wordMATCHES     = "MATCHES"
-	wordNOTCONTAINS = "NOT CONTAINS"
-	wordNOTEQUAL    = "!="
-	wordNOTIN       = "NOT IN"
-	wordOR          = "OR"
-	wordTRUE        = "TRUE"
-	wordNOT         = "NOT"
-	wordEXISTS      = "EXISTS"
-	wordNOTEXISTS   = "NOT EXISTS"
-	wordEOF         = "EOF"
+	wordAND             = "AND"
+	wordCONTAINS        = "CONTAINS"
+	wordEQUAL           = "=="
+	wordFALSE           = "FALSE"
+	wordGT              = ">"
+	wordGTE             = ">="
+	wordIN              = "IN"
+	wordLT              = "<"
+	wordLTE             = "<="
+	wordMATCHES         = "MATCHES"
+	wordNOTCONTAINS     = "NOT CONTAINS"
+	wordNOTEQUAL        = "!="
+	wordNOTIN           = "NOT IN"
+	wordNOTMATCHES      = "NOT MATCHES"
+	wordOR              = "OR"
+	wordTRUE            = "TRUE"
+	wordNOT             = "NOT"
+	wordEXISTS          = "EXISTS"
+	wordNOTEXISTS       = "NOT EXISTS"
+	wordEQUALIGNORECASE = "=~"
+	wordSTARTSWITH      = "STARTSWITH"
+	wordENDSWITH        = "ENDSWITH"
+	wordEOF             = "EOF"
 )
 
 const (
@@ -111,20 +119,24 @@ var operatorStart = map[rune]any{
 
 var (
 	operatorsToToken = map[string]parserToken{
-		wordEQUAL:       parserTokenEQUAL,
-		wordNOTEQUAL:    parserTokenNOTEQUAL,
-		wordLT:          parserTokenLT,
-		wordLTE:         parserTokenLTE,
-		wordGT:          parserTokenGT,
-		wordGTE:         parserTokenGTE,
-		wordCONTAINS:    parserTokenCONTAINS,
-		wordNOTCONTAINS: parserTokenNOTCONTAINS,
-		wordMATCHES:     parserTokenMATCHES,
-		wordIN:          parserTokenIN,
-		wordNOTIN:       parserTokenNOTIN,
-		wordNOT:         parserTokenNOT,
-		wordEXISTS:      parserTokenEXISTS,
-		wordNOTEXISTS:   parserTokenNOTEXISTS,
+		wordEQUAL:           parserTokenEQUAL,
+		wordNOTEQUAL:        parserTokenNOTEQUAL,
+		wordLT:              parserTokenLT,
+		wordLTE:             parserTokenLTE,
+		wordGT:              parserTokenGT,
+		wordGTE:             parserTokenGTE,
+		wordCONTAINS:        parserTokenCONTAINS,
+		wordNOTCONTAINS:     parserTokenNOTCONTAINS,
+		wordMATCHES:         parserTokenMATCHES,
+		wordIN:              parserTokenIN,
+		wordNOTIN:           parserTokenNOTIN,
+		wordNOTMATCHES:      parserTokenNOTMATCHES,
+		wordNOT:             parserTokenNOT,
+		wordEXISTS:          parserTokenEXISTS,
+		wordNOTEXISTS:       parserTokenNOTEXISTS,
+		wordEQUALIGNORECASE: parserTokenEQUALIGNORECASE,
+		wordSTARTSWITH:      parserTokenSTARTSWITH,
+		wordENDSWITH:        parserTokenENDSWITH,
 	}
 
 	wordToToken = map[string]parserToken{
@@ -148,17 +160,27 @@ var (
 
 var datePattern = regexp.MustCompile(`^date\((.*)\)$`)
 var nowPattern = regexp.MustCompile(`^now\((.*)\)$`)
+var startOfPatterns = map[FilterTimeAnchor]*regexp.Regexp{
+	FilterTimeAnchorStartOfDay:   regexp.MustCompile(`^startOfDay\((.*)\)$`),
+	FilterTimeAnchorStartOfWeek:  regexp.MustCompile(`^startOfWeek\((.*)\)$`),
+	FilterTimeAnchorStartOfMonth: regexp.MustCompile(`^startOfMonth\((.*)\)$`),
+}
 var dateLayout = "2006-01-02"
 var dateTimeLayout = "2006-01-02 15:04"
 var errorInvalidExpression = fmt.Errorf("invalid expression")
 
 // FilterParser represents a Parser
 type FilterParser struct {
+	input   string
 	scanner *scanner
 	config  filterParserConfig
+	depth   int // current number of nested sub filters and arrays
+	terms   int // number of terms parsed so far
+	values  int // number of values of the array parsed so far, including the values of its nested arrays
 	buffer  struct {
 		token   parserToken // last read token
 		literal string      // last read literal
+		offset  int         // offset of the last read token
 		size    int         // buffer size (max=1)
 	}
 }
@@ -172,22 +194,29 @@ func NewFilterParser(input string, opts ...FilterParserOption) *FilterParser {
 	}
 
 	return &FilterParser{
+		input:   input,
 		config:  config,
 		scanner: newScanner(input),
 	}
 }
 
 // Parse parses the input string and returns a new Filter.
+//
+// If the input is invalid, the returned error is an elemental.Error with a
+// 400 status code. Its Data contains the position of the error in the input,
+// the offending fragment, the expected tokens and, when the fragment looks like
+// a misspelled keyword, a suggestion. See parseError for details.
 func (p *FilterParser) Parse() (*Filter, error) {
 
 	token, literal := p.peekIgnoreWhitespace()
 
-	// The input needs to start with a word, a quote or a left parenthesis.
+	// The input needs to start with a word, a quote, a left parenthesis or a negation.
 	if token != parserTokenWORD &&
 		token != parserTokenQUOTE &&
 		token != parserTokenSINGLEQUOTE &&
-		token != parserTokenLEFTPARENTHESIS {
-		return nil, fmt.Errorf("invalid start of expression. found %s", literal)
+		token != parserTokenLEFTPARENTHESIS &&
+		token != parserTokenNOT {
+		return nil, p.parseError(p.buffer.offset, literal, expectedStart, "invalid start of expression. found %s", literal)
 	}
 
 	// Stack all discovered the filters
@@ -221,6 +250,7 @@ func (p *FilterParser) Parse() (*Filter, error) {
 
 		if token == parserTokenQUOTE || token == parserTokenSINGLEQUOTE {
 			// Handle expression starting with QUOTE like "a" operator b
+			keyOffset := p.buffer.offset
 			key, err := p.parseUntilQuoteSimilar(token)
 			if err != nil {
 				return nil, err
@@ -231,7 +261,7 @@ func (p *FilterParser) Parse() (*Filter, error) {
 				return nil, err
 			}
 
-			filter, err := p.makeFilter(key, operator, value)
+			filter, err := p.makeFilter(key, keyOffset, operator, value)
 			if err != nil {
 				return nil, err
 			}
@@ -243,12 +273,13 @@ func (p *FilterParser) Parse() (*Filter, error) {
 		if token == parserTokenWORD {
 			// Handle expression without QUOTE like a operator b
 			// In that case, the literal is the word scanned.
+			keyOffset := p.buffer.offset
 			operator, value, err := p.parseOperatorAndValue()
 			if err != nil {
 				return nil, err
 			}
 
-			filter, err := p.makeFilter(literal, operator, value)
+			filter, err := p.makeFilter(literal, keyOffset, operator, value)
 			if err != nil {
 				return nil, err
 			}
@@ -281,17 +312,51 @@ func (p *FilterParser) Parse() (*Filter, error) {
 			// In case of "(", a subfilter needs to be computed
 			// and stacked to the previously found filters.
 
-			subFilter, err := p.Parse()
+			subFilter, err := p.parseSubFilter()
 			if err != nil {
 				return nil, err
 			}
 			stack = append(stack, subFilter)
+			continue
+		}
+
+		if token == parserTokenNOT {
+			// In case of "NOT", the next token must be a "(" and the
+			// computed subfilter is negated.
+
+			if token, literal := p.scanIgnoreWhitespace(); token != parserTokenLEFTPARENTHESIS {
+				fragment := literal
+				if token == parserTokenEOF {
+					literal = wordEOF
+				}
+				return nil, p.parseError(p.buffer.offset, fragment, []string{"("}, "invalid usage of operator NOT. found %s instead of (", literal)
+			}
+
+			subFilter, err := p.parseSubFilter()
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, NewFilterComposer().Not(subFilter).Done())
 		}
 	}
 
 	return finalFilter.Done(), nil
 }
 
+// parseSubFilter parses the sub filter following the "(" that has just been scanned.
+func (p *FilterParser) parseSubFilter() (*Filter, error) {
+
+	p.depth++
+	if p.config.maxDepth > 0 && p.depth > p.config.maxDepth {
+		return nil, p.parseError(p.buffer.offset, p.buffer.literal, nil, "filter is too deeply nested: maximum depth is %d", p.config.maxDepth)
+	}
+
+	f, err := p.Parse()
+	p.depth--
+
+	return f, err
+}
+
 // scan returns the next token scanned or the last bufferred one
 func (p *FilterParser) scan() (parserToken, string) {
 	// If a token has been buffered, use it
@@ -301,10 +366,11 @@ func (p *FilterParser) scan() (parserToken, string) {
 	}
 
 	// Otherwise scan the next token
+	offset := p.scanner.offset()
 	token, literal := p.scanner.scan()
 
 	// Save it to the buffer in case we unscan later.
-	p.buffer.token, p.buffer.literal = token, literal
+	p.buffer.token, p.buffer.literal, p.buffer.offset = token, literal, offset
 
 	return token, literal
 }
@@ -342,21 +408,62 @@ func (p *FilterParser) parseOperatorAndValue() (parserToken, any, error) {
 	}
 
 	if _, ok := p.config.unsupportedComparators[operator]; ok {
-		return parserTokenILLEGAL, nil, fmt.Errorf("unsupported comparator: %s", tokenToOperator(operator))
+		return parserTokenILLEGAL, nil, p.parseError(p.buffer.offset, p.buffer.literal, nil, "unsupported comparator: %s", tokenToOperator(operator))
 	}
 
 	if operator == parserTokenEXISTS || operator == parserTokenNOTEXISTS {
 		return operator, nil, nil
 	}
 
+	p.peekIgnoreWhitespace()
+	valueOffset := p.buffer.offset
+
+	p.values = 0
 	value, err := p.parseValue()
 	if err != nil {
 		return parserTokenILLEGAL, nil, err
 	}
 
+	if operator == parserTokenMATCHES || operator == parserTokenNOTMATCHES {
+		if err := p.checkRegexes(valueOffset, value); err != nil {
+			return parserTokenILLEGAL, nil, err
+		}
+	}
+
 	return operator, value, nil
 }
 
+// checkRegexes checks the regular expressions of the given value against the limits of the parser,
+// including the ones of its nested arrays.
+func (p *FilterParser) checkRegexes(offset int, value any) error {
+
+	if values, ok := value.([]any); ok {
+		for _, v := range values {
+			if err := p.checkRegexes(offset, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	expr, ok := value.(string)
+	if !ok {
+		return nil
+	}
+
+	if p.config.maxRegexLength > 0 && len(expr) > p.config.maxRegexLength {
+		return p.parseError(offset, expr, nil, "regular expression is too long: maximum length is %d", p.config.maxRegexLength)
+	}
+
+	if p.config.rejectUnsafeRegexes {
+		if err := checkRegexSafety(expr); err != nil {
+			return p.parseError(offset, expr, nil, "unsafe regular expression %s: %s", expr, err)
+		}
+	}
+
+	return nil
+}
+
 func tokenToOperator(t parserToken) string {
 
 	for operator, token := range operatorsToToken {
@@ -368,10 +475,15 @@ func tokenToOperator(t parserToken) string {
 	return ""
 }
 
-func (p *FilterParser) makeFilter(key string, operator parserToken, value any) (*Filter, error) {
+func (p *FilterParser) makeFilter(key string, keyOffset int, operator parserToken, value any) (*Filter, error) {
 
 	if strings.HasPrefix(key, "$") {
-		return nil, fmt.Errorf("could not start a parameter with $. Found %s", key)
+		return nil, p.parseError(keyOffset, key, nil, "could not start a parameter with $. Found %s", key)
+	}
+
+	p.terms++
+	if p.config.maxTerms > 0 && p.terms > p.config.maxTerms {
+		return nil, p.parseError(keyOffset, key, nil, "filter has too many terms: maximum is %d", p.config.maxTerms)
 	}
 
 	filter := NewFilterComposer()
@@ -420,12 +532,24 @@ func (p *FilterParser) makeFilter(key string, operator parserToken, value any) (
 		} else {
 			filter.WithKey(key).Matches(value)
 		}
+	case parserTokenNOTMATCHES:
+		if values, ok := value.([]any); ok {
+			filter.WithKey(key).NotMatches(values...)
+		} else {
+			filter.WithKey(key).NotMatches(value)
+		}
 	case parserTokenEXISTS:
 		filter.WithKey(key).Exists()
 	case parserTokenNOTEXISTS:
 		filter.WithKey(key).NotExists()
+	case parserTokenEQUALIGNORECASE:
+		filter.WithKey(key).EqualsIgnoreCase(value)
+	case parserTokenSTARTSWITH:
+		filter.WithKey(key).StartsWith(value)
+	case parserTokenENDSWITH:
+		filter.WithKey(key).EndsWith(value)
 	default:
-		return nil, fmt.Errorf("unsupported operator")
+		return nil, p.parseError(p.buffer.offset, p.buffer.literal, expectedOperators, "unsupported operator")
 	}
 
 	token, literal := p.peekIgnoreWhitespace()
@@ -434,10 +558,11 @@ func (p *FilterParser) makeFilter(key string, operator parserToken, value any) (
 		token != parserTokenRIGHTPARENTHESIS &&
 		token != parserTokenEOF {
 
+		fragment := literal
 		if token == parserTokenEOF {
 			literal = wordEOF
 		}
-		return nil, fmt.Errorf("invalid keyword after %s. found %s", filter.Done().String(), literal)
+		return nil, p.parseError(p.buffer.offset, fragment, expectedConjunction, "invalid keyword after %s. found %s", filter.Done().String(), literal)
 	}
 
 	return filter.Done(), nil
@@ -454,11 +579,17 @@ func (p *FilterParser) parseOperator() (parserToken, error) {
 	}
 
 	if !tokenIsOperator(token) {
+		fragment := literal
 		if token == parserTokenEOF {
 			literal = wordEOF
 		}
 
-		return parserTokenILLEGAL, fmt.Errorf("invalid operator. found %s instead of (==, !=, <, <=, >, >=, contains, in, matches, exists)", literal)
+		expected := expectedOperators
+		if operatorNot {
+			expected = expectedNegated
+		}
+
+		return parserTokenILLEGAL, p.parseError(p.buffer.offset, fragment, expected, "invalid operator. found %s instead of (==, !=, <, <=, >, >=, =~, contains, in, matches, exists, startswith, endswith)", literal)
 	}
 
 	if operatorNot {
@@ -469,8 +600,10 @@ func (p *FilterParser) parseOperator() (parserToken, error) {
 			return parserTokenNOTIN, nil
 		case parserTokenEXISTS:
 			return parserTokenNOTEXISTS, nil
+		case parserTokenMATCHES:
+			return parserTokenNOTMATCHES, nil
 		default:
-			return parserTokenILLEGAL, fmt.Errorf("invalid usage of operator NOT before %s", literal)
+			return parserTokenILLEGAL, p.parseError(p.buffer.offset, literal, expectedNegated, "invalid usage of operator NOT before %s", literal)
 		}
 	}
 
@@ -497,6 +630,13 @@ func (p *FilterParser) parseValue() (any, error) {
 		return false, nil
 	}
 
+	if p.config.placeholders && token == parserTokenWORD && strings.HasPrefix(literal, "$") {
+		if !placeholderPattern.MatchString(literal) {
+			return nil, p.parseError(p.buffer.offset, literal, nil, "invalid placeholder %s: a placeholder must be a $ followed by a name made of letters, digits and _", literal)
+		}
+		return FilterPlaceholder{Name: literal[1:]}, nil
+	}
+
 	if i, err := strconv.ParseInt(literal, 10, 0); err == nil {
 		return i, nil
 	}
@@ -513,9 +653,9 @@ func (p *FilterParser) parseValue() (any, error) {
 		return nil, err
 	}
 
-	duration, err := p.parseDurationValue()
+	relative, err := p.parseRelativeTimeValue()
 	if err == nil {
-		return duration, nil
+		return relative, nil
 	}
 	if err != errorInvalidExpression {
 		return nil, err
@@ -529,22 +669,24 @@ func (p *FilterParser) parseValue() (any, error) {
 	return v, nil
 }
 
-// parseExpression parse an expression with the regex if the prefix matches and returns the matching value.
-func (p *FilterParser) parseExpression(prefix string, regex *regexp.Regexp) (string, error) {
+// parseExpression parse an expression with the regex if the prefix matches and returns the matching value
+// and the offset of the expression.
+func (p *FilterParser) parseExpression(prefix string, regex *regexp.Regexp) (string, int, error) {
 
 	p.unscan()
 	_, literal := p.scanIgnoreWhitespace()
 
 	if literal != prefix {
 		p.unscan()
-		return "", errorInvalidExpression
+		return "", 0, errorInvalidExpression
 	}
 
+	offset := p.buffer.offset
 	expression := literal
 	token, literal := p.scanIgnoreWhitespace()
 	if token != parserTokenLEFTPARENTHESIS {
 		p.unscan()
-		return "", errorInvalidExpression
+		return "", 0, errorInvalidExpression
 	}
 
 	expression += literal
@@ -554,7 +696,7 @@ func (p *FilterParser) parseExpression(prefix string, regex *regexp.Regexp) (str
 
 		if token == parserTokenLEFTPARENTHESIS ||
 			token == parserTokenEOF {
-			return "", errorInvalidExpression
+			return "", 0, errorInvalidExpression
 		}
 
 		if token == parserTokenRIGHTPARENTHESIS {
@@ -564,15 +706,15 @@ func (p *FilterParser) parseExpression(prefix string, regex *regexp.Regexp) (str
 
 	matches := regex.FindStringSubmatch(expression)
 	if len(matches) != 2 {
-		return "", errorInvalidExpression
+		return "", 0, errorInvalidExpression
 	}
 
-	return strings.Trim(matches[1], " \""), nil
+	return strings.Trim(matches[1], " \""), offset, nil
 }
 
 func (p *FilterParser) parseDurationValue() (time.Duration, error) {
 
-	expression, err := p.parseExpression("now", nowPattern)
+	expression, offset, err := p.parseExpression("now", nowPattern)
 	if err != nil {
 		return -1, err
 	}
@@ -583,45 +725,173 @@ func (p *FilterParser) parseDurationValue() (time.Duration, error) {
 
 	d, err := time.ParseDuration(expression)
 	if err != nil {
-		return -1, fmt.Errorf("unable to parse duration %s: %s", expression, err.Error())
+		return -1, p.parseError(offset, expression, nil, "unable to parse duration %s: %s", expression, err.Error())
 	}
 	return d, nil
 }
 
+// parseRelativeTimeValue parses a time relative to the evaluation of the filter, like `now("-1h")`
+// or `startOfDay("Europe/Paris") - 1mo`. Without offsets, now() returns a time.Duration, otherwise
+// a FilterRelativeTime is returned.
+func (p *FilterParser) parseRelativeTimeValue() (any, error) {
+
+	var relative FilterRelativeTime
+
+	duration, err := p.parseDurationValue()
+	switch err {
+
+	case nil:
+		relative = FilterRelativeTime{Anchor: FilterTimeAnchorNow, Duration: duration}
+
+	case errorInvalidExpression:
+		for _, anchor := range []FilterTimeAnchor{FilterTimeAnchorStartOfDay, FilterTimeAnchorStartOfWeek, FilterTimeAnchorStartOfMonth} {
+
+			var location string
+			var offset int
+			location, offset, err = p.parseExpression(string(anchor), startOfPatterns[anchor])
+			if err == errorInvalidExpression {
+				continue
+			}
+
+			if _, lerr := loadFilterLocation(location); lerr != nil {
+				return nil, p.parseError(offset, location, nil, "unable to load time zone %s: %s", location, lerr.Error())
+			}
+
+			relative = FilterRelativeTime{Anchor: anchor, Location: location}
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, err
+	}
+
+	found, err := p.parseTimeOffsets(&relative)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found && relative.Anchor == FilterTimeAnchorNow {
+		return relative.Duration, nil
+	}
+
+	return relative, nil
+}
+
+// parseTimeOffsets parses the offsets following the anchor of a relative time, like `- 7d + 12h`,
+// and adds them to the given FilterRelativeTime. It returns false if there are no offsets.
+func (p *FilterParser) parseTimeOffsets(relative *FilterRelativeTime) (bool, error) {
+
+	var found bool
+
+	for {
+
+		token, literal := p.peekIgnoreWhitespace()
+
+		var sign int
+		var offset string
+
+		switch {
+		case token == parserTokenILLEGAL && literal == "+":
+			sign = 1
+		case token == parserTokenWORD && strings.HasPrefix(literal, "-"):
+			sign, offset = -1, literal[1:]
+		default:
+			return found, nil
+		}
+
+		p.scanIgnoreWhitespace()
+		offsetPosition := p.buffer.offset + 1
+
+		if offset == "" {
+			token, literal = p.scanIgnoreWhitespace()
+			if token != parserTokenWORD {
+				fragment := literal
+				if token == parserTokenEOF {
+					literal = wordEOF
+				}
+				return false, p.parseError(p.buffer.offset, fragment, nil, "invalid time offset. found %s", literal)
+			}
+			offset, offsetPosition = literal, p.buffer.offset
+		}
+
+		months, days, duration, err := parseFilterTimeOffset(offset)
+		if err != nil {
+			return false, p.parseError(offsetPosition, offset, nil, "unable to parse time offset %s: %s", offset, err.Error())
+		}
+
+		relative.Months += sign * months
+		relative.Days += sign * days
+		relative.Duration += time.Duration(sign) * duration
+		found = true
+	}
+}
+
+// parseDateValue parses a date like `date("2020-01-02")`. An optional time zone
+// can be given for the dates that do not specify one, like `date("2020-01-02", "Europe/Paris")`.
 func (p *FilterParser) parseDateValue() (time.Time, error) {
 
-	expression, err := p.parseExpression("date", datePattern)
+	expression, offset, err := p.parseExpression("date", datePattern)
 	if err != nil {
 		return time.Time{}, err
 	}
 
+	loc := time.UTC
+	if value, location, found := strings.Cut(expression, ","); found {
+		expression = strings.Trim(value, ` "`)
+		location = strings.Trim(location, ` "`)
+		if loc, err = loadFilterLocation(location); err != nil {
+			return time.Time{}, p.parseError(offset, location, nil, "unable to load time zone %s: %s", location, err.Error())
+		}
+	}
+
 	// RFC3339 Layout
-	t, err := time.Parse(time.RFC3339, expression)
+	t, err := time.ParseInLocation(time.RFC3339, expression, loc)
 	if err == nil {
 		return t, nil
 	}
 	// YYYY-MM-DD HH:MM Layout
-	t, err = time.Parse(dateTimeLayout, expression)
+	t, err = time.ParseInLocation(dateTimeLayout, expression, loc)
 	if err == nil {
 		return t, nil
 	}
 	// YYYY-MM-DD Layout
-	t, err = time.Parse(dateLayout, expression)
+	t, err = time.ParseInLocation(dateLayout, expression, loc)
 	if err == nil {
 		return t, nil
 	}
 
-	return t, fmt.Errorf("unable to parse date format %s", expression)
+	return t, p.parseError(offset, expression, nil, "unable to parse date format %s", expression)
 }
 
+// parseUntilQuoteSimilar reads everything until the given closing quote. If OptStringEscapes is
+// set, a backslash escapes a quote or a backslash and any other character is taken literally.
+// Otherwise, the words inside the quotes are scanned as if they were not quoted.
 func (p *FilterParser) parseUntilQuoteSimilar(tokenQuote parserToken) (string, error) {
 
+	quote := runeQUOTE
+	if tokenQuote == parserTokenSINGLEQUOTE {
+		quote = runeSINGLEQUOTE
+	}
+
+	if p.config.stringEscapes {
+
+		word, ok := p.scanner.scanQuoted(quote)
+		if !ok {
+			return "", p.parseError(p.scanner.offset(), word, []string{string(quote)}, "missing quote after %s", word)
+		}
+
+		return word, nil
+	}
+
 	var word string
 	// Scan everything until the next quote or the end of the input
 	for {
 		token, literal := p.scan()
 		if token == parserTokenEOF {
-			return "", fmt.Errorf("missing quote after %s", word)
+			return "", p.parseError(p.buffer.offset, word, []string{string(quote)}, "missing quote after %s", word)
 		}
 
 		if token == tokenQuote {
@@ -645,18 +915,20 @@ func (p *FilterParser) parseStringValue() (string, error) {
 
 	// Unquoted string can have only one word
 	if token != parserTokenWORD {
+		fragment := literal
 		if token == parserTokenEOF {
 			literal = wordEOF
 		}
-		return "", fmt.Errorf("invalid value. found %s", literal)
+		return "", p.parseError(p.buffer.offset, fragment, expectedValue, "invalid value. found %s", literal)
 	}
 
+	offset := p.buffer.offset
 	token, next := p.peekIgnoreWhitespace()
 	switch token {
 	case parserTokenQUOTE, parserTokenSINGLEQUOTE:
-		return "", fmt.Errorf("missing quote before the value: %s", literal)
+		return "", p.parseError(offset, literal, nil, "missing quote before the value: %s", literal)
 	case parserTokenWORD:
-		return "", fmt.Errorf("missing parentheses to protect value: %s %s", literal, next)
+		return "", p.parseError(offset, literal+" "+next, nil, "missing parentheses to protect value: %s %s", literal, next)
 	}
 
 	return literal, nil
@@ -668,7 +940,14 @@ func (p *FilterParser) parseArrayValue() ([]any, error) {
 
 	token, literal := p.scanIgnoreWhitespace()
 	if token != parserTokenLEFTSQUAREBRACKET {
-		return nil, fmt.Errorf("invalid start of list. found %s", literal)
+		return nil, p.parseError(p.buffer.offset, literal, []string{"["}, "invalid start of list. found %s", literal)
+	}
+
+	p.depth++
+	defer func() { p.depth-- }()
+
+	if p.config.maxDepth > 0 && p.depth > p.config.maxDepth {
+		return nil, p.parseError(p.buffer.offset, literal, nil, "filter is too deeply nested: maximum depth is %d", p.config.maxDepth)
 	}
 
 	values := []any{}
@@ -680,7 +959,7 @@ func (p *FilterParser) parseArrayValue() ([]any, error) {
 		if token == parserTokenEOF ||
 			token == parserTokenLEFTPARENTHESIS ||
 			token == parserTokenRIGHTPARENTHESIS {
-			return nil, fmt.Errorf("invalid end of array. found %s", literal)
+			return nil, p.parseError(p.buffer.offset, literal, expectedArrayEnd, "invalid end of array. found %s", literal)
 		}
 
 		if token == parserTokenRIGHTSQUAREBRACKET {
@@ -691,6 +970,11 @@ func (p *FilterParser) parseArrayValue() ([]any, error) {
 			continue
 		}
 
+		p.values++
+		if p.config.maxArrayLength > 0 && p.values > p.config.maxArrayLength {
+			return nil, p.parseError(p.buffer.offset, literal, nil, "array is too long: maximum length is %d", p.config.maxArrayLength)
+		}
+
 		p.unscan()
 		value, err := p.parseValue()
 		if err != nil {
@@ -745,5 +1029,45 @@ func tokenIsOperator(token parserToken) bool {
 		token == parserTokenEXISTS ||
 		token == parserTokenNOTEXISTS ||
 		token == parserTokenNOTCONTAINS ||
-		token == parserTokenNOTIN
+		token == parserTokenNOTIN ||
+		token == parserTokenNOTMATCHES ||
+		token == parserTokenEQUALIGNORECASE ||
+		token == parserTokenSTARTSWITH ||
+		token == parserTokenENDSWITH
+}
+
+// checkRegexSafety returns an error if the given regular expression cannot be parsed,
+// or if it contains an unbounded quantifier applied to an expression that already
+// contains one, which makes backtracking engines run in exponential time.
+func checkRegexSafety(expr string) error {
+
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return err
+	}
+
+	if hasNestedQuantifiers(re, false) {
+		return fmt.Errorf("nested quantifiers are not allowed")
+	}
+
+	return nil
+}
+
+func hasNestedQuantifiers(re *syntax.Regexp, quantified bool) bool {
+
+	unbounded := re.Op == syntax.OpStar ||
+		re.Op == syntax.OpPlus ||
+		(re.Op == syntax.OpRepeat && re.Max == -1)
+
+	if unbounded && quantified {
+		return true
+	}
+
+	for _, sub := range re.Sub {
+		if hasNestedQuantifiers(sub, quantified || unbounded) {
+			return true
+		}
+	}
+
+	return false
 }
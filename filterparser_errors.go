@@ -0,0 +1,110 @@
+package elemental
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	expectedStart       = []string{"key", `"`, "'", "(", "not"}
+	expectedOperators   = []string{"==", "!=", "<", "<=", ">", ">=", "=~", "contains", "in", "matches", "exists", "startswith", "endswith", "not"}
+	expectedNegated     = []string{"contains", "in", "matches", "exists"}
+	expectedConjunction = []string{"and", "or", ")"}
+	expectedValue       = []string{"value"}
+	expectedArrayEnd    = []string{",", "]"}
+)
+
+// parseError returns an elemental.Error describing an error that occurred at the given byte
+// offset of the input. The Data of the error is a map containing:
+//   - offset: the byte offset of the offending fragment in the input
+//   - line and column: the position of the offending fragment in the input, starting at 1
+//   - fragment: the offending fragment
+//   - expected: the list of tokens that were expected instead of the fragment, if any
+//   - suggestion: the expected keyword the fragment is likely a misspelling of, if any
+func (p *FilterParser) parseError(offset int, fragment string, expected []string, format string, args ...any) error {
+
+	offset = min(max(offset, 0), len(p.input))
+
+	if fragment == string(runeEOF) {
+		fragment = ""
+	}
+
+	line, column := 1, 1
+	for _, r := range p.input[:offset] {
+		if r == '\n' {
+			line++
+			column = 1
+			continue
+		}
+		column++
+	}
+
+	description := fmt.Sprintf(format, args...)
+	data := map[string]any{
+		"offset":   offset,
+		"line":     line,
+		"column":   column,
+		"fragment": fragment,
+		"expected": expected,
+	}
+
+	if suggestion := suggestKeyword(fragment, expected); suggestion != "" {
+		data["suggestion"] = suggestion
+		description = fmt.Sprintf("%s. did you mean %s?", description, suggestion)
+	}
+
+	return NewErrorWithData("Invalid Filter", description, "elemental", http.StatusBadRequest, data)
+}
+
+// suggestKeyword returns the candidate that is the closest to the given word if
+// it is close enough to be considered as a misspelling of it.
+func suggestKeyword(word string, candidates []string) string {
+
+	word = strings.ToLower(word)
+	length := utf8.RuneCountInString(word)
+	threshold := max(1, length/3)
+
+	var suggestion string
+	best := threshold + 1
+
+	for _, candidate := range candidates {
+		if d := editDistance(word, candidate); d > 0 && d < best && d < length {
+			suggestion, best = candidate, d
+		}
+	}
+
+	return suggestion
+}
+
+// editDistance returns the edit distance between a and b, where an edit is the insertion,
+// deletion or substitution of a rune, or the transposition of two adjacent runes.
+func editDistance(a, b string) int {
+
+	ra, rb := []rune(a), []rune(b)
+
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(ra)][len(rb)]
+}
@@ -0,0 +1,160 @@
+package elemental
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParser_ErrorPosition(t *testing.T) {
+
+	tests := map[string]struct {
+		filter     string
+		offset     int
+		line       int
+		column     int
+		fragment   string
+		expected   []string
+		suggestion string
+	}{
+		"invalid operator": {
+			filter:   `name == "a" and age = 3`,
+			offset:   20,
+			line:     1,
+			column:   21,
+			fragment: "=",
+			expected: expectedOperators,
+		},
+		"misspelled contains": {
+			filter:     `tags contain "a"`,
+			offset:     5,
+			line:       1,
+			column:     6,
+			fragment:   "contain",
+			expected:   expectedOperators,
+			suggestion: "contains",
+		},
+		"misspelled exists": {
+			filter:     `name == "a" and tags EXIST`,
+			offset:     21,
+			line:       1,
+			column:     22,
+			fragment:   "EXIST",
+			expected:   expectedOperators,
+			suggestion: "exists",
+		},
+		"misspelled negated operator": {
+			filter:     `tags not matchs "a"`,
+			offset:     9,
+			line:       1,
+			column:     10,
+			fragment:   "matchs",
+			expected:   expectedNegated,
+			suggestion: "matches",
+		},
+		"misspelled conjunction on another line": {
+			filter:     "name == \"a\"\n  adn age == 3",
+			offset:     14,
+			line:       2,
+			column:     3,
+			fragment:   "adn",
+			expected:   expectedConjunction,
+			suggestion: "and",
+		},
+		"missing quote": {
+			filter:   `name == "a`,
+			offset:   10,
+			line:     1,
+			column:   11,
+			fragment: "a",
+			expected: []string{`"`},
+		},
+		"invalid end of array": {
+			filter:   `name in ["a", "b"`,
+			offset:   17,
+			line:     1,
+			column:   18,
+			fragment: "",
+			expected: expectedArrayEnd,
+		},
+		"parameter key": {
+			filter:   `name == "a" and $key == 3`,
+			offset:   16,
+			line:     1,
+			column:   17,
+			fragment: "$key",
+		},
+		"invalid duration": {
+			filter:   `date > now("-1x")`,
+			offset:   7,
+			line:     1,
+			column:   8,
+			fragment: "-1x",
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			_, err := NewFilterParser(tc.filter).Parse()
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+
+			e, ok := err.(Error)
+			if !ok {
+				t.Fatalf("expected an elemental.Error, got %T: %s", err, err)
+			}
+
+			if e.Code != http.StatusBadRequest {
+				t.Errorf("expected code %d, got %d", http.StatusBadRequest, e.Code)
+			}
+
+			data := e.Data.(map[string]any)
+
+			if data["offset"] != tc.offset || data["line"] != tc.line || data["column"] != tc.column {
+				t.Errorf("unexpected position: expected %d (%d:%d), got %v (%v:%v)", tc.offset, tc.line, tc.column, data["offset"], data["line"], data["column"])
+			}
+
+			if data["fragment"] != tc.fragment {
+				t.Errorf("unexpected fragment: expected %q, got %q", tc.fragment, data["fragment"])
+			}
+
+			if !reflect.DeepEqual(data["expected"], tc.expected) {
+				t.Errorf("unexpected expected tokens: expected %v, got %v", tc.expected, data["expected"])
+			}
+
+			if suggestion, _ := data["suggestion"].(string); suggestion != tc.suggestion {
+				t.Errorf("unexpected suggestion: expected %q, got %q", tc.suggestion, suggestion)
+			}
+		})
+	}
+}
+
+func Test_suggestKeyword(t *testing.T) {
+
+	tests := []struct {
+		word       string
+		candidates []string
+		expected   string
+	}{
+		{"contain", expectedOperators, "contains"},
+		{"CONTAIN", expectedOperators, "contains"},
+		{"contians", expectedOperators, "contains"},
+		{"exist", expectedOperators, "exists"},
+		{"in", expectedOperators, ""},
+		{"=", expectedOperators, ""},
+		{"hello", expectedOperators, ""},
+		{"an", expectedConjunction, "and"},
+		{"adn", expectedConjunction, "and"},
+		{"and", expectedConjunction, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.word, func(t *testing.T) {
+			if s := suggestKeyword(tc.word, tc.candidates); s != tc.expected {
+				t.Errorf("expected suggestion %q for %q, got %q", tc.expected, tc.word, s)
+			}
+		})
+	}
+}
@@ -5,6 +5,16 @@ import "strings"
 type filterParserConfig struct {
 	// map to support an O(1) lookup during parsing
 	unsupportedComparators map[parserToken]struct{}
+
+	// limits are disabled when set to 0.
+	maxDepth            int
+	maxTerms            int
+	maxArrayLength      int
+	maxRegexLength      int
+	rejectUnsafeRegexes bool
+
+	stringEscapes bool
+	placeholders  bool
 }
 
 // FilterParserOption represents the type for the options that can be passed to `NewFilterParser` which can be used to
@@ -23,3 +33,69 @@ func OptUnsupportedComparators(blacklist []FilterComparator) FilterParserOption
 		}
 	}
 }
+
+// OptMaxDepth limits the number of nested parentheses, negations and arrays in the filter.
+// If supplied, the parser will return an error if the filter is nested deeper than the given depth.
+func OptMaxDepth(depth int) FilterParserOption {
+	return func(config *filterParserConfig) {
+		config.maxDepth = depth
+	}
+}
+
+// OptMaxTerms limits the number of comparisons, like `a == b`, in the whole filter.
+// If supplied, the parser will return an error if the filter contains more terms than the given number.
+func OptMaxTerms(terms int) FilterParserOption {
+	return func(config *filterParserConfig) {
+		config.maxTerms = terms
+	}
+}
+
+// OptMaxArrayLength limits the number of values of an array, like in `a in [b, c]`. The values of the nested arrays
+// are counted as well, so `a in [b, [c, d]]` contains 4 values.
+// If supplied, the parser will return an error if an array of the filter contains more values than the given length.
+func OptMaxArrayLength(length int) FilterParserOption {
+	return func(config *filterParserConfig) {
+		config.maxArrayLength = length
+	}
+}
+
+// OptMaxRegexLength limits the length in bytes of the regular expressions given to matches and not matches.
+// If supplied, the parser will return an error if a regular expression of the filter is longer than the given length.
+func OptMaxRegexLength(length int) FilterParserOption {
+	return func(config *filterParserConfig) {
+		config.maxRegexLength = length
+	}
+}
+
+// OptRejectUnsafeRegexes makes the parser return an error if a regular expression given to matches or not matches
+// cannot be parsed or contains nested unbounded quantifiers, like `(a+)+` or `(a*b?)*`. Go regular expressions
+// always run in linear time, but such expressions can take exponential time in backtracking engines, like
+// the one of MongoDB, to which filters are often translated.
+func OptRejectUnsafeRegexes() FilterParserOption {
+	return func(config *filterParserConfig) {
+		config.rejectUnsafeRegexes = true
+	}
+}
+
+// OptStringEscapes makes the parser read quoted strings the way Filter.String writes them: inside the
+// quotes, a backslash followed by a quote or another backslash is replaced by that character, and any
+// other character, including a backslash followed by anything else, is taken literally.
+//
+// Without this option, a backslash inside quotes drops itself and escapes any character that follows it
+// in the middle of a word, so "a\d" is read as `ad` instead of `a\d`, and it is kept as is at the start of
+// a word, so "a \"b\"" cannot be parsed. As this changes the meaning of existing filters, it must be
+// enabled explicitly.
+func OptStringEscapes() FilterParserOption {
+	return func(config *filterParserConfig) {
+		config.stringEscapes = true
+	}
+}
+
+// OptPlaceholders makes the parser read an unquoted $ followed by a name, like `namespace == $ns`, as a
+// FilterPlaceholder that must be bound using Filter.Bind before the filter is used. Without this option,
+// such a value is read as a string, and the parser never returns a filter containing placeholders.
+func OptPlaceholders() FilterParserOption {
+	return func(config *filterParserConfig) {
+		config.placeholders = true
+	}
+}
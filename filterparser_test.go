@@ -215,6 +215,156 @@ func TestParser_UnsupportedComparator(t *testing.T) {
 	}
 }
 
+func TestParser_Limits(t *testing.T) {
+
+	deep := strings.Repeat("(", 10) + `a == 1` + strings.Repeat(")", 10)
+
+	tests := map[string]struct {
+		filter         string
+		opts           []FilterParserOption
+		expectedError  string
+		expectedOffset int
+	}{
+		"depth within the limit": {
+			filter: `a == 1 and (b == 2 or not (c == 3))`,
+			opts:   []FilterParserOption{OptMaxDepth(2)},
+		},
+		"depth over the limit": {
+			filter:         `a == 1 and (b == 2 or not (c == 3 and (d == 4)))`,
+			opts:           []FilterParserOption{OptMaxDepth(2)},
+			expectedError:  "filter is too deeply nested: maximum depth is 2",
+			expectedOffset: 38,
+		},
+		"many nested parentheses": {
+			filter:         deep,
+			opts:           []FilterParserOption{OptMaxDepth(5)},
+			expectedError:  "filter is too deeply nested: maximum depth is 5",
+			expectedOffset: 5,
+		},
+		"no depth limit": {
+			filter: deep,
+		},
+		"terms within the limit": {
+			filter: `a == 1 and (b == 2 or c exists)`,
+			opts:   []FilterParserOption{OptMaxTerms(3)},
+		},
+		"terms over the limit": {
+			filter:         `a == 1 and (b == 2 or c exists) and d == 4`,
+			opts:           []FilterParserOption{OptMaxTerms(3)},
+			expectedError:  "filter has too many terms: maximum is 3",
+			expectedOffset: 36,
+		},
+		"array within the limit": {
+			filter: `a in [1, 2, 3]`,
+			opts:   []FilterParserOption{OptMaxArrayLength(3)},
+		},
+		"array over the limit": {
+			filter:         `a in [1, 2, 3, 4]`,
+			opts:           []FilterParserOption{OptMaxArrayLength(3)},
+			expectedError:  "array is too long: maximum length is 3",
+			expectedOffset: 15,
+		},
+		"nested arrays over the depth limit": {
+			filter:         `a == 1 and (b in [1, [2, [3]]])`,
+			opts:           []FilterParserOption{OptMaxDepth(2)},
+			expectedError:  "filter is too deeply nested: maximum depth is 2",
+			expectedOffset: 21,
+		},
+		"nested arrays within the depth limit": {
+			filter: `a == 1 and (b in [1, [2, 3]])`,
+			opts:   []FilterParserOption{OptMaxDepth(3)},
+		},
+		"nested arrays within the limit": {
+			filter: `a in [1, [2]] and b in [3, 4]`,
+			opts:   []FilterParserOption{OptMaxArrayLength(3)},
+		},
+		"nested arrays over the limit": {
+			filter:         `a in [[1, 2], [3, 4]]`,
+			opts:           []FilterParserOption{OptMaxArrayLength(3)},
+			expectedError:  "array is too long: maximum length is 3",
+			expectedOffset: 14,
+		},
+		"regex within the limit": {
+			filter: `a matches ["^abc", "d$"]`,
+			opts:   []FilterParserOption{OptMaxRegexLength(4)},
+		},
+		"regex over the limit": {
+			filter:         `a == "abcdef" and a not matches ["^abc", "^abcdef"]`,
+			opts:           []FilterParserOption{OptMaxRegexLength(4)},
+			expectedError:  "regular expression is too long: maximum length is 4",
+			expectedOffset: 32,
+		},
+		"regex in a nested array over the limit": {
+			filter:         `a matches ["^a", ["^abcdef"]]`,
+			opts:           []FilterParserOption{OptMaxRegexLength(4)},
+			expectedError:  "regular expression is too long: maximum length is 4",
+			expectedOffset: 10,
+		},
+		"nested quantifiers in a nested array": {
+			filter:         `a matches [["^(a+)+$"]]`,
+			opts:           []FilterParserOption{OptRejectUnsafeRegexes()},
+			expectedError:  "unsafe regular expression ^(a+)+$: nested quantifiers are not allowed",
+			expectedOffset: 10,
+		},
+		"safe regex": {
+			filter: `a matches "^(abc)*d{2,}[a-z]+(x{1,3})+$"`,
+			opts:   []FilterParserOption{OptRejectUnsafeRegexes()},
+		},
+		"nested quantifiers": {
+			filter:         `a matches "^(a+)+$"`,
+			opts:           []FilterParserOption{OptRejectUnsafeRegexes()},
+			expectedError:  "unsafe regular expression ^(a+)+$: nested quantifiers are not allowed",
+			expectedOffset: 10,
+		},
+		"nested quantifiers in an alternation": {
+			filter:         `a matches ["^x", "(a|b*){2,}"]`,
+			opts:           []FilterParserOption{OptRejectUnsafeRegexes()},
+			expectedError:  "nested quantifiers are not allowed",
+			expectedOffset: 10,
+		},
+		"invalid regex": {
+			filter:         `a matches "(a"`,
+			opts:           []FilterParserOption{OptRejectUnsafeRegexes()},
+			expectedError:  "unsafe regular expression (a: error parsing regexp",
+			expectedOffset: 10,
+		},
+		"unsafe regex allowed without the option": {
+			filter: `a matches "^(a+)+$"`,
+		},
+	}
+
+	for scenario, tc := range tests {
+		t.Run(scenario, func(t *testing.T) {
+
+			_, err := NewFilterParser(tc.filter, tc.opts...).Parse()
+
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Fatalf("did not expect to get an error, but received: %s", err)
+				}
+				return
+			}
+
+			perr, ok := err.(Error)
+			if !ok {
+				t.Fatalf("expected an elemental.Error, got %T: %v", err, err)
+			}
+
+			if perr.Code != 400 {
+				t.Errorf("expected a 400 error, got %d", perr.Code)
+			}
+
+			if !strings.Contains(perr.Description, tc.expectedError) {
+				t.Errorf("unexpected error\nexpected: %s\nactual:   %s", tc.expectedError, perr.Description)
+			}
+
+			if offset := perr.Data.(map[string]any)["offset"]; offset != tc.expectedOffset {
+				t.Errorf("unexpected offset: expected %d, got %v", tc.expectedOffset, offset)
+			}
+		})
+	}
+}
+
 func Test_tokenToOperator(t *testing.T) {
 
 	tests := map[string]struct {
@@ -491,7 +641,7 @@ func TestParser_Keys_Errors(t *testing.T) {
 
 			Convey("Then there should be an error", func() {
 				So(err, ShouldNotEqual, nil)
-				So(err.Error(), ShouldEqual, `missing quote after key == chris`)
+				So(err.(Error).Description, ShouldEqual, `missing quote after key == chris`)
 			})
 		})
 	})
@@ -653,6 +803,214 @@ func TestParser_Operators(t *testing.T) {
 	})
 }
 
+func TestParser_Not(t *testing.T) {
+
+	tests := map[string]struct {
+		filter   string
+		expected *Filter
+	}{
+		"not matches": {
+			filter:   `key not matches "^value"`,
+			expected: NewFilterComposer().WithKey("key").NotMatches("^value").Done(),
+		},
+		"not matches with multiple values": {
+			filter:   `key NOT MATCHES ["^a", "^b"]`,
+			expected: NewFilterComposer().WithKey("key").NotMatches("^a", "^b").Done(),
+		},
+		"negated sub filter": {
+			filter:   `not (a == 1 or b == 2)`,
+			expected: NewFilterComposer().Not(NewFilterComposer().Or(NewFilterComposer().WithKey("a").Equals(1).Done(), NewFilterComposer().WithKey("b").Equals(2).Done()).Done()).Done(),
+		},
+		"negated sub filter in a conjunction": {
+			filter: `c == 3 and NOT (a == 1) and d == 4`,
+			expected: NewFilterComposer().And(
+				NewFilterComposer().WithKey("c").Equals(3).Done(),
+				NewFilterComposer().Not(NewFilterComposer().WithKey("a").Equals(1).Done()).Done(),
+				NewFilterComposer().WithKey("d").Equals(4).Done(),
+			).Done(),
+		},
+		"nested negated sub filters": {
+			filter: `not (a == 1 and not (b == 2))`,
+			expected: NewFilterComposer().Not(
+				NewFilterComposer().And(
+					NewFilterComposer().WithKey("a").Equals(1).Done(),
+					NewFilterComposer().Not(NewFilterComposer().WithKey("b").Equals(2).Done()).Done(),
+				).Done(),
+			).Done(),
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			filter, err := NewFilterParser(tc.filter).Parse()
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %s", err)
+			}
+
+			if filter.String() != tc.expected.String() {
+				t.Errorf("unexpected filter\nexpected: %s\nactual:   %s", tc.expected, filter)
+			}
+
+			reparsed, err := NewFilterParser(filter.String()).Parse()
+			if err != nil {
+				t.Fatalf("unable to parse the string representation: %s", err)
+			}
+
+			if reparsed.String() != filter.String() {
+				t.Errorf("string representation is not stable\nexpected: %s\nactual:   %s", filter, reparsed)
+			}
+		})
+	}
+}
+
+func TestParser_StringComparators(t *testing.T) {
+
+	tests := map[string]struct {
+		filter   string
+		expected *Filter
+	}{
+		"equals ignore case": {
+			filter:   `name =~ "Alice"`,
+			expected: NewFilterComposer().WithKey("name").EqualsIgnoreCase("Alice").Done(),
+		},
+		"equals ignore case without spaces": {
+			filter:   `name=~alice`,
+			expected: NewFilterComposer().WithKey("name").EqualsIgnoreCase("alice").Done(),
+		},
+		"starts with": {
+			filter:   `namespace STARTSWITH "/a/b"`,
+			expected: NewFilterComposer().WithKey("namespace").StartsWith("/a/b").Done(),
+		},
+		"ends with": {
+			filter: `name endswith '.com' and a == 1`,
+			expected: NewFilterComposer().And(
+				NewFilterComposer().WithKey("name").EndsWith(".com").Done(),
+				NewFilterComposer().WithKey("a").Equals(1).Done(),
+			).Done(),
+		},
+		"escaped quotes and backslashes": {
+			filter:   `name startswith "a \"b\" \\ \d"`,
+			expected: NewFilterComposer().WithKey("name").StartsWith(`a "b" \ \d`).Done(),
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			filter, err := NewFilterParser(tc.filter, OptStringEscapes()).Parse()
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %s", err)
+			}
+
+			if filter.String() != tc.expected.String() {
+				t.Errorf("unexpected filter\nexpected: %s\nactual:   %s", tc.expected, filter)
+			}
+
+			reparsed, err := NewFilterParser(filter.String(), OptStringEscapes()).Parse()
+			if err != nil {
+				t.Fatalf("unable to parse the string representation: %s", err)
+			}
+
+			if reparsed.String() != filter.String() {
+				t.Errorf("string representation does not round trip\nexpected: %s\nactual:   %s", filter, reparsed)
+			}
+		})
+	}
+}
+
+func TestParser_StringEscapes(t *testing.T) {
+
+	tests := map[string]struct {
+		filter       string
+		legacy       string
+		legacyError  bool
+		escaped      string
+		escapedError bool
+	}{
+		"escaped backslash": {
+			filter:  `a == "x\\d"`,
+			legacy:  `x\d`,
+			escaped: `x\d`,
+		},
+		"backslash before a letter": {
+			filter:  `a == "x\d"`,
+			legacy:  `xd`,
+			escaped: `x\d`,
+		},
+		"backslash before a space": {
+			filter:  `a == "x\ y"`,
+			legacy:  `x y`,
+			escaped: `x\ y`,
+		},
+		"escaped quote in a word": {
+			filter:  `a == "x\"y"`,
+			legacy:  `x"y`,
+			escaped: `x"y`,
+		},
+		"escaped quote at the start of a word": {
+			filter:      `a == "say \"hi\""`,
+			legacyError: true,
+			escaped:     `say "hi"`,
+		},
+		"escaped single quote": {
+			filter:  `a == 'it\'s'`,
+			legacy:  `it's`,
+			escaped: `it's`,
+		},
+		"unterminated": {
+			filter:       `a == "x\"`,
+			legacyError:  true,
+			escapedError: true,
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			filter, err := NewFilterParser(tc.filter).Parse()
+			if tc.legacyError != (err != nil) {
+				t.Fatalf("unexpected error without string escapes: %v", err)
+			}
+			if err == nil && filter.Values()[0][0] != tc.legacy {
+				t.Errorf("unexpected value without string escapes: %q", filter.Values()[0][0])
+			}
+
+			filter, err = NewFilterParser(tc.filter, OptStringEscapes()).Parse()
+			if tc.escapedError != (err != nil) {
+				t.Fatalf("unexpected error with string escapes: %v", err)
+			}
+			if err == nil && filter.Values()[0][0] != tc.escaped {
+				t.Errorf("unexpected value with string escapes: %q", filter.Values()[0][0])
+			}
+		})
+	}
+}
+
+func TestParser_Not_Errors(t *testing.T) {
+
+	tests := map[string]string{
+		"not without parenthesis": `not a == 1`,
+		"not at the end":          `a == 1 and not`,
+		"not before equal":        `a not == 1`,
+		"unsupported not matches": `a not matches "x"`,
+	}
+
+	for description, filter := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			var opts []FilterParserOption
+			if description == "unsupported not matches" {
+				opts = append(opts, OptUnsupportedComparators([]FilterComparator{NotMatchComparator}))
+			}
+
+			if _, err := NewFilterParser(filter, opts...).Parse(); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
 func TestParser_Operators_Errors(t *testing.T) {
 
 	Convey(`Given the wrong operator '"'`, t, func() {
@@ -665,7 +1023,7 @@ func TestParser_Operators_Errors(t *testing.T) {
 
 			Convey("Then there should be an error", func() {
 				So(err, ShouldNotEqual, nil)
-				So(err.Error(), ShouldEqual, `invalid operator. found " instead of (==, !=, <, <=, >, >=, contains, in, matches, exists)`)
+				So(err.(Error).Description, ShouldEqual, `invalid operator. found " instead of (==, !=, <, <=, >, >=, =~, contains, in, matches, exists, startswith, endswith)`)
 			})
 		})
 	})
@@ -680,7 +1038,7 @@ func TestParser_Operators_Errors(t *testing.T) {
 
 			Convey("Then there should be an error", func() {
 				So(err, ShouldNotEqual, nil)
-				So(err.Error(), ShouldEqual, `invalid operator. found and instead of (==, !=, <, <=, >, >=, contains, in, matches, exists)`)
+				So(err.(Error).Description, ShouldEqual, `invalid operator. found and instead of (==, !=, <, <=, >, >=, =~, contains, in, matches, exists, startswith, endswith)`)
 			})
 		})
 	})
@@ -837,7 +1195,7 @@ func TestParser_Values_Errors(t *testing.T) {
 
 			Convey("Then there should be an error", func() {
 				So(err, ShouldNotEqual, nil)
-				So(err.Error(), ShouldEqual, "missing parentheses to protect value: hello world")
+				So(err.(Error).Description, ShouldEqual, "missing parentheses to protect value: hello world")
 			})
 		})
 	})
@@ -852,7 +1210,7 @@ func TestParser_Values_Errors(t *testing.T) {
 
 			Convey("Then there should be an error", func() {
 				So(err, ShouldNotEqual, nil)
-				So(err.Error(), ShouldEqual, `missing quote after hello`)
+				So(err.(Error).Description, ShouldEqual, `missing quote after hello`)
 			})
 		})
 	})
@@ -867,7 +1225,7 @@ func TestParser_Values_Errors(t *testing.T) {
 
 			Convey("Then there should be an error", func() {
 				So(err, ShouldNotEqual, nil)
-				So(err.Error(), ShouldEqual, `missing quote after hello`)
+				So(err.(Error).Description, ShouldEqual, `missing quote after hello`)
 			})
 		})
 	})
@@ -882,7 +1240,7 @@ func TestParser_Values_Errors(t *testing.T) {
 
 			Convey("Then there should be an error", func() {
 				So(err, ShouldNotEqual, nil)
-				So(err.Error(), ShouldEqual, `missing quote before the value: hello`)
+				So(err.(Error).Description, ShouldEqual, `missing quote before the value: hello`)
 			})
 		})
 	})
@@ -896,7 +1254,7 @@ func TestParser_Values_Errors(t *testing.T) {
 
 			Convey("Then there should be an error", func() {
 				So(err, ShouldNotEqual, nil)
-				So(err.Error(), ShouldEqual, `missing quote before the value: hello`)
+				So(err.(Error).Description, ShouldEqual, `missing quote before the value: hello`)
 			})
 		})
 	})
@@ -911,7 +1269,7 @@ func TestParser_Values_Errors(t *testing.T) {
 
 			Convey("Then there should be an error", func() {
 				So(err, ShouldNotEqual, nil)
-				So(err.Error(), ShouldEqual, `missing quote after hello"`)
+				So(err.(Error).Description, ShouldEqual, `missing quote after hello"`)
 			})
 		})
 	})
@@ -926,7 +1284,7 @@ func TestParser_Values_Errors(t *testing.T) {
 
 			Convey("Then there should be an error", func() {
 				So(err, ShouldNotEqual, nil)
-				So(err.Error(), ShouldEqual, `missing quote after hello'`)
+				So(err.(Error).Description, ShouldEqual, `missing quote after hello'`)
 			})
 		})
 	})
@@ -941,7 +1299,7 @@ func TestParser_Values_Errors(t *testing.T) {
 
 			Convey("Then there should be an error", func() {
 				So(err, ShouldNotEqual, nil)
-				So(err.Error(), ShouldEqual, `invalid value. found and`)
+				So(err.(Error).Description, ShouldEqual, `invalid value. found and`)
 			})
 		})
 	})
@@ -956,7 +1314,7 @@ func TestParser_Values_Errors(t *testing.T) {
 
 			Convey("Then there should be an error", func() {
 				So(err, ShouldNotEqual, nil)
-				So(err.Error(), ShouldEqual, `invalid keyword after key exists. found value`)
+				So(err.(Error).Description, ShouldEqual, `invalid keyword after key exists. found value`)
 			})
 		})
 	})
@@ -1081,7 +1439,7 @@ func TestParser_Values_DateType_Errors(t *testing.T) {
 
 			Convey("Then there should be an error", func() {
 				So(err, ShouldNotEqual, nil)
-				So(err.Error(), ShouldEqual, `unable to parse date format invalid-date`)
+				So(err.(Error).Description, ShouldEqual, `unable to parse date format invalid-date`)
 			})
 		})
 	})
@@ -1095,7 +1453,7 @@ func TestParser_Values_DateType_Errors(t *testing.T) {
 
 			Convey("Then there should be an error", func() {
 				So(err, ShouldNotEqual, nil)
-				So(err.Error(), ShouldEqual, `unable to parse date format 2012-24-2`)
+				So(err.(Error).Description, ShouldEqual, `unable to parse date format 2012-24-2`)
 			})
 		})
 	})
@@ -1109,7 +1467,7 @@ func TestParser_Values_DateType_Errors(t *testing.T) {
 
 			Convey("Then there should be an error", func() {
 				So(err, ShouldNotEqual, nil)
-				So(err.Error(), ShouldEqual, `unable to parse date format 2012-24-2`)
+				So(err.(Error).Description, ShouldEqual, `unable to parse date format 2012-24-2`)
 			})
 		})
 	})
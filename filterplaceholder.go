@@ -0,0 +1,290 @@
+package elemental
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"time"
+)
+
+const (
+	filterUnboundPlaceholderFormat = `Placeholder '$%s' for attribute '%s' is not bound`
+	filterMissingPlaceholderFormat = `Missing value for placeholder '$%s' of attribute '%s'`
+	filterInvalidPlaceholderFormat = `Invalid value for placeholder '$%s' of attribute '%s': %s`
+)
+
+var placeholderPattern = regexp.MustCompile(`^\$[A-Za-z_][A-Za-z0-9_]*$`)
+
+// A FilterPlaceholder is a named value of a Filter that must be bound
+// using Bind before the filter is used. In the filter grammar, a placeholder
+// is written as an unquoted $ followed by its name, like `namespace == $ns`,
+// and it is only read as such by a FilterParser created with OptPlaceholders.
+type FilterPlaceholder struct {
+	Name string
+}
+
+// String returns the string representation of the placeholder.
+func (p FilterPlaceholder) String() string {
+	return "$" + p.Name
+}
+
+// Placeholders returns the sorted names of the placeholders used in the filter,
+// including the ones used in its sub filters.
+func (f *Filter) Placeholders() []string {
+
+	seen := map[string]struct{}{}
+	_ = WalkFilter(f, func(term FilterTerm) error {
+		for _, v := range term.Values {
+			for _, item := range filterValueItems(v, true) {
+				if p, ok := item.(FilterPlaceholder); ok {
+					seen[p.Name] = struct{}{}
+				}
+			}
+		}
+		return nil
+	})
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Bind returns a new Filter where the placeholders are replaced by the values of the given map,
+// keyed by placeholder name (without the $). The filter itself is never modified, so a parsed
+// filter can be bound many times. Values that are not used by the filter are ignored.
+//
+// A value must be a string, a boolean, a number, a time.Time, a time.Duration, a FilterRelativeTime, nil, or a slice
+// of those. When a placeholder is the only value of in, not in, contains, not contains, matches
+// or not matches, a slice is expanded as the list of values, so `tags contains $tags` bound to
+// []string{"a", "b"} is the same as `tags contains ["a", "b"]`. Otherwise:
+//   - =~, startswith, endswith, matches and not matches require strings
+//   - >, >=, < and <= require a string, a number, or a time.Time, time.Duration or FilterRelativeTime
+//
+// If a placeholder has no value or if a value is invalid, Bind returns an Errors containing a
+// 422 error for each of them.
+func (f *Filter) Bind(values map[string]any) (*Filter, error) {
+
+	errs := NewErrors()
+
+	bound := TransformFilter(f, func(term FilterTerm) (FilterTerm, bool) {
+
+		out := make(FilterValue, 0, len(term.Values))
+
+		for _, v := range term.Values {
+
+			p, ok := v.(FilterPlaceholder)
+			if !ok {
+				// placeholders can also be items of a list.
+				if items, isList := v.([]any); isList {
+					list := make([]any, len(items))
+					for i, item := range items {
+						list[i] = item
+						if ip, ok := item.(FilterPlaceholder); ok {
+							value, err := bindPlaceholder(term, ip, values, false)
+							if err != nil {
+								errs = errs.Append(err)
+								continue
+							}
+							list[i] = value
+						}
+					}
+					v = list
+				}
+				out = append(out, v)
+				continue
+			}
+
+			value, err := bindPlaceholder(term, p, values, len(term.Values) == 1)
+			if err != nil {
+				errs = errs.Append(err)
+				continue
+			}
+
+			if len(term.Values) == 1 && isListComparator(term.Comparator) {
+				if rv := reflect.ValueOf(value); isArrayLike(rv) {
+					for i := 0; i < rv.Len(); i++ {
+						out = append(out, rv.Index(i).Interface())
+					}
+					continue
+				}
+			}
+
+			out = append(out, value)
+		}
+
+		term.Values = out
+
+		return term, true
+	})
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return bound, nil
+}
+
+// bindPlaceholder returns the value of the given placeholder, type checked
+// against the comparator of the given term.
+func bindPlaceholder(term FilterTerm, p FilterPlaceholder, values map[string]any, expand bool) (any, error) {
+
+	value, ok := values[p.Name]
+	if !ok {
+		return nil, makeFilterError(http.StatusUnprocessableEntity, term.Key, filterMissingPlaceholderFormat, p.Name, term.Key)
+	}
+
+	items := []any{value}
+	if rv := reflect.ValueOf(value); isArrayLike(rv) {
+		if expand && isListComparator(term.Comparator) {
+			items = filterValueItems(value, true)
+		} else if !isEqualityComparator(term.Comparator) {
+			return nil, makeFilterError(http.StatusUnprocessableEntity, term.Key, filterInvalidPlaceholderFormat, p.Name, term.Key, "a list cannot be used with "+translateComparator(term.Comparator))
+		}
+	}
+
+	for _, item := range items {
+		if err := checkPlaceholderValue(term.Comparator, item); err != nil {
+			return nil, makeFilterError(http.StatusUnprocessableEntity, term.Key, filterInvalidPlaceholderFormat, p.Name, term.Key, err)
+		}
+	}
+
+	return value, nil
+}
+
+func checkPlaceholderValue(comparator FilterComparator, value any) error {
+
+	switch value.(type) {
+	case nil:
+		switch comparator {
+		case EqualComparator, NotEqualComparator, InComparator, NotInComparator, ContainComparator, NotContainComparator:
+			return nil
+		default:
+			return fmt.Errorf("null cannot be used with %s", translateComparator(comparator))
+		}
+	case time.Time, time.Duration, FilterRelativeTime:
+		if isStringComparator(comparator) {
+			return fmt.Errorf("expected a string, got %T", value)
+		}
+		return nil
+	case FilterPlaceholder:
+		return fmt.Errorf("a placeholder cannot be bound to another placeholder")
+	}
+
+	v := reflect.ValueOf(value)
+
+	switch {
+	case v.Kind() == reflect.String:
+		return nil
+	case isStringComparator(comparator):
+		return fmt.Errorf("expected a string, got %T", value)
+	case v.Kind() == reflect.Bool:
+		switch comparator {
+		case GreaterComparator, GreaterOrEqualComparator, LesserComparator, LesserOrEqualComparator:
+			return fmt.Errorf("a boolean cannot be used with %s", translateComparator(comparator))
+		}
+		return nil
+	case isNumber(v):
+		return nil
+	case isArrayLike(v):
+		for i := 0; i < v.Len(); i++ {
+			if err := checkPlaceholderValue(comparator, v.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported type %T", value)
+	}
+}
+
+// checkFilterBound returns an error if the given filter still contains placeholders.
+// Unlike Placeholders, it does not allocate, as it runs every time a filter is used.
+func checkFilterBound(filter *Filter) error {
+
+	if p, ok := findPlaceholder(filter); ok {
+		return fmt.Errorf("placeholder $%s is not bound", p.Name)
+	}
+
+	return nil
+}
+
+// findPlaceholder returns the first placeholder used in the given filter or in its sub filters.
+func findPlaceholder(filter *Filter) (FilterPlaceholder, bool) {
+
+	for i, op := range filter.operators {
+
+		var subs []*Filter
+
+		switch op {
+		case AndOperator:
+			for _, v := range filter.values[i] {
+				if p, ok := findPlaceholderInValue(v); ok {
+					return p, true
+				}
+			}
+			continue
+		case AndFilterOperator:
+			subs = filter.ands[i]
+		case OrFilterOperator:
+			subs = filter.ors[i]
+		case NotFilterOperator:
+			subs = filter.nots[i]
+		}
+
+		for _, sub := range subs {
+			if p, ok := findPlaceholder(sub); ok {
+				return p, true
+			}
+		}
+	}
+
+	return FilterPlaceholder{}, false
+}
+
+// findPlaceholderInValue returns the first placeholder of the given value,
+// which is either a placeholder or a list of values holding one.
+func findPlaceholderInValue(value any) (FilterPlaceholder, bool) {
+
+	switch v := value.(type) {
+	case FilterPlaceholder:
+		return v, true
+	case []any:
+		for _, item := range v {
+			if p, ok := findPlaceholderInValue(item); ok {
+				return p, true
+			}
+		}
+	}
+
+	return FilterPlaceholder{}, false
+}
+
+func isListComparator(comparator FilterComparator) bool {
+
+	switch comparator {
+	case InComparator, NotInComparator, ContainComparator, NotContainComparator, MatchComparator, NotMatchComparator:
+		return true
+	default:
+		return false
+	}
+}
+
+func isEqualityComparator(comparator FilterComparator) bool {
+	return comparator == EqualComparator || comparator == NotEqualComparator
+}
+
+func isStringComparator(comparator FilterComparator) bool {
+
+	switch comparator {
+	case EqualIgnoreCaseComparator, StartWithComparator, EndWithComparator, MatchComparator, NotMatchComparator:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,290 @@
+package elemental_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.aporeto.io/elemental"
+	testmodel "go.aporeto.io/elemental/test/model"
+)
+
+func TestFilter_Placeholders(t *testing.T) {
+
+	filter, err := elemental.NewFilterParser(`namespace == $ns and (date > $since or name in [$name, "b"]) and not (tags contains $tags)`, elemental.OptPlaceholders()).Parse()
+	if err != nil {
+		t.Fatalf("unable to parse filter: %s", err)
+	}
+
+	if names := filter.Placeholders(); !reflect.DeepEqual(names, []string{"name", "ns", "since", "tags"}) {
+		t.Errorf("unexpected placeholders: %v", names)
+	}
+
+	expected := `((namespace == $ns) and (((date > $since) or (name in [$name, "b"]))) and (not ((tags contains [$tags]))))`
+	if filter.String() != expected {
+		t.Errorf("unexpected filter\nexpected: %s\nactual:   %s", expected, filter)
+	}
+
+	reparsed, err := elemental.NewFilterParser(filter.String(), elemental.OptPlaceholders()).Parse()
+	if err != nil {
+		t.Fatalf("unable to parse the string representation of the filter: %s", err)
+	}
+	if reparsed.String() != filter.String() {
+		t.Errorf("unexpected reparsed filter\nexpected: %s\nactual:   %s", filter, reparsed)
+	}
+
+	if names := elemental.NewFilterComposer().WithKey("a").Equals("$a").Done().Placeholders(); len(names) != 0 {
+		t.Errorf("did not expect a string to be a placeholder: %v", names)
+	}
+}
+
+func TestFilter_Bind(t *testing.T) {
+
+	since := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := map[string]struct {
+		filter   string
+		values   map[string]any
+		expected string
+	}{
+		"strings and dates": {
+			filter:   `namespace == $ns and createTime > $since`,
+			values:   map[string]any{"ns": "/a", "since": since, "unused": 42},
+			expected: `((namespace == "/a") and (createTime > date("2020-01-02T03:04:05Z")))`,
+		},
+		"durations and numbers": {
+			filter:   `date > $since or count <= $max`,
+			values:   map[string]any{"since": -time.Hour, "max": 3},
+			expected: `((date > now("-1h0m0s")) or (count <= 3))`,
+		},
+		"expanded list": {
+			filter:   `tags contains $tags and name not in $names`,
+			values:   map[string]any{"tags": []string{"x", "y"}, "names": []any{"a", 1}},
+			expected: `((tags contains ["x", "y"]) and (name not in ["a", 1]))`,
+		},
+		"items of a list": {
+			filter:   `name in [$a, "b", $c]`,
+			values:   map[string]any{"a": "a", "c": true},
+			expected: `name in ["a", "b", true]`,
+		},
+		"string comparators": {
+			filter:   `name startswith $prefix and name matches $patterns`,
+			values:   map[string]any{"prefix": `a"b`, "patterns": []string{"^a", "b$"}},
+			expected: `((name startswith "a\"b") and (name matches ["^a", "b$"]))`,
+		},
+		"negated sub filter": {
+			filter:   `not (archived == $archived)`,
+			values:   map[string]any{"archived": false},
+			expected: `not ((archived == false))`,
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			filter, err := elemental.NewFilterParser(tc.filter, elemental.OptPlaceholders()).Parse()
+			if err != nil {
+				t.Fatalf("unable to parse filter: %s", err)
+			}
+			original := filter.String()
+
+			bound, err := filter.Bind(tc.values)
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %s", err)
+			}
+
+			if bound.String() != tc.expected {
+				t.Errorf("unexpected filter\nexpected: %s\nactual:   %s", tc.expected, bound)
+			}
+
+			if len(bound.Placeholders()) != 0 {
+				t.Errorf("expected all the placeholders to be bound: %v", bound.Placeholders())
+			}
+
+			if filter.String() != original {
+				t.Errorf("the original filter has been modified: %s", filter)
+			}
+		})
+	}
+}
+
+func TestFilter_BindErrors(t *testing.T) {
+
+	tests := map[string]struct {
+		filter         string
+		values         map[string]any
+		expectedErrors int
+	}{
+		"missing values": {
+			filter:         `namespace == $ns and (name == $name or name in [$other])`,
+			values:         map[string]any{"name": "a"},
+			expectedErrors: 2,
+		},
+		"string comparator with a number": {
+			filter:         `name startswith $prefix`,
+			values:         map[string]any{"prefix": 1},
+			expectedErrors: 1,
+		},
+		"regular expression with a date": {
+			filter:         `name matches $pattern`,
+			values:         map[string]any{"pattern": []any{"^a", time.Now()}},
+			expectedErrors: 1,
+		},
+		"ordering comparator with a boolean": {
+			filter:         `count > $count`,
+			values:         map[string]any{"count": true},
+			expectedErrors: 1,
+		},
+		"ordering comparator with a list": {
+			filter:         `count > $count`,
+			values:         map[string]any{"count": []int{1, 2}},
+			expectedErrors: 1,
+		},
+		"ordering comparator with null": {
+			filter:         `count > $count`,
+			values:         map[string]any{"count": nil},
+			expectedErrors: 1,
+		},
+		"unsupported type": {
+			filter:         `name == $name`,
+			values:         map[string]any{"name": struct{}{}},
+			expectedErrors: 1,
+		},
+		"placeholder": {
+			filter:         `name == $name`,
+			values:         map[string]any{"name": elemental.FilterPlaceholder{Name: "name"}},
+			expectedErrors: 1,
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			filter, err := elemental.NewFilterParser(tc.filter, elemental.OptPlaceholders()).Parse()
+			if err != nil {
+				t.Fatalf("unable to parse filter: %s", err)
+			}
+
+			bound, err := filter.Bind(tc.values)
+			if err == nil {
+				t.Fatalf("expected an error, got filter %s", bound)
+			}
+
+			errs, ok := err.(elemental.Errors)
+			if !ok {
+				t.Fatalf("expected an elemental.Errors, got %T", err)
+			}
+
+			if len(errs) != tc.expectedErrors {
+				t.Errorf("expected %d errors, got %d: %s", tc.expectedErrors, len(errs), errs)
+			}
+
+			if errs.Code() != http.StatusUnprocessableEntity {
+				t.Errorf("expected a 422 error, got %d", errs.Code())
+			}
+		})
+	}
+}
+
+func TestFilter_UnboundPlaceholders(t *testing.T) {
+
+	filter, err := elemental.NewFilterParser(`firstName == "Alice" and lastName == $name`, elemental.OptPlaceholders()).Parse()
+	if err != nil {
+		t.Fatalf("unable to parse filter: %s", err)
+	}
+
+	if _, err := elemental.CompileFilter(filter, testmodel.NewUser()); err == nil {
+		t.Errorf("expected CompileFilter to return an error")
+	}
+
+	var matcherError *elemental.MatcherError
+	if _, err := elemental.MatchesFilter(testmodel.NewUser(), filter); !errors.As(err, &matcherError) {
+		t.Errorf("expected MatchesFilter to return a MatcherError, got: %v", err)
+	}
+
+	if _, err := elemental.MatchesFilter(testmodel.NewUser(), filter, elemental.OptExplain(&elemental.MatchExplanation{})); err == nil {
+		t.Errorf("expected MatchesFilter to return an error when explaining")
+	}
+
+	if _, _, err := elemental.FilterToSQL(filter); err == nil {
+		t.Errorf("expected FilterToSQL to return an error")
+	}
+
+	if _, err := elemental.FilterToBSON(filter, nil, elemental.EmptyIdentity); err == nil {
+		t.Errorf("expected FilterToBSON to return an error")
+	}
+
+	if err := elemental.ValidateFilter(filter, testmodel.NewUser()); !elemental.IsErrorWithCode(err, http.StatusUnprocessableEntity) {
+		t.Errorf("expected ValidateFilter to return a 422 error, got: %v", err)
+	}
+
+	bound, err := filter.Bind(map[string]any{"name": "Bob"})
+	if err != nil {
+		t.Fatalf("did not expect to get an error, but received: %s", err)
+	}
+
+	if err := elemental.ValidateFilter(bound, testmodel.NewUser()); err != nil {
+		t.Errorf("did not expect to get an error, but received: %s", err)
+	}
+}
+
+func TestFilter_PlaceholdersDisabled(t *testing.T) {
+
+	filter, err := elemental.NewFilterFromString(`namespace == $ns and name in [$name]`)
+	if err != nil {
+		t.Fatalf("did not expect to get an error, but received: %s", err)
+	}
+
+	if names := filter.Placeholders(); len(names) != 0 {
+		t.Errorf("did not expect any placeholder, got: %v", names)
+	}
+
+	expected := `((namespace == "$ns") and (name in ["$name"]))`
+	if filter.String() != expected {
+		t.Errorf("unexpected filter\nexpected: %s\nactual:   %s", expected, filter)
+	}
+
+	if _, err := elemental.NewFilterFromString(`name == $1a`); err != nil {
+		t.Errorf("did not expect to get an error, but received: %s", err)
+	}
+}
+
+func TestFilter_InvalidPlaceholder(t *testing.T) {
+
+	for _, input := range []string{`name == $`, `name == $1a`, `name in [$a-b]`} {
+		t.Run(input, func(t *testing.T) {
+			if _, err := elemental.NewFilterParser(input, elemental.OptPlaceholders()).Parse(); !elemental.IsErrorWithCode(err, http.StatusBadRequest) {
+				t.Errorf("expected a 400 error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestFilter_EncodeDecodePlaceholders(t *testing.T) {
+
+	filter, err := elemental.NewFilterParser(`namespace == $ns and name in [$name, "b"]`, elemental.OptPlaceholders()).Parse()
+	if err != nil {
+		t.Fatalf("unable to parse filter: %s", err)
+	}
+
+	for _, encoding := range []elemental.EncodingType{elemental.EncodingTypeJSON, elemental.EncodingTypeMSGPACK} {
+		t.Run(string(encoding), func(t *testing.T) {
+
+			data, err := elemental.Encode(encoding, filter)
+			if err != nil {
+				t.Fatalf("unable to encode filter: %s", err)
+			}
+
+			decoded := elemental.NewFilter()
+			if err := elemental.Decode(encoding, data, decoded); err != nil {
+				t.Fatalf("unable to decode filter: %s", err)
+			}
+
+			if decoded.String() != filter.String() {
+				t.Errorf("unexpected filter\nexpected: %s\nactual:   %s", filter, decoded)
+			}
+		})
+	}
+}
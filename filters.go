@@ -41,6 +41,9 @@ const (
 	NotMatchComparator
 	ExistsComparator
 	NotExistsComparator
+	EqualIgnoreCaseComparator
+	StartWithComparator
+	EndWithComparator
 	emptyComparator
 )
 
@@ -64,6 +67,7 @@ const (
 	AndOperator FilterOperator = iota
 	OrFilterOperator
 	AndFilterOperator
+	NotFilterOperator
 )
 
 // FilterKeys represents a list of FilterKey.
@@ -105,8 +109,12 @@ type FilterValueComposer interface {
 	Contains(...any) FilterKeyComposer
 	NotContains(...any) FilterKeyComposer
 	Matches(...any) FilterKeyComposer
+	NotMatches(...any) FilterKeyComposer
 	Exists() FilterKeyComposer
 	NotExists() FilterKeyComposer
+	EqualsIgnoreCase(any) FilterKeyComposer
+	StartsWith(any) FilterKeyComposer
+	EndsWith(any) FilterKeyComposer
 }
 
 // FilterKeyComposer composes a filter.
@@ -115,6 +123,7 @@ type FilterKeyComposer interface {
 
 	And(...*Filter) FilterKeyComposer
 	Or(...*Filter) FilterKeyComposer
+	Not(...*Filter) FilterKeyComposer
 
 	Done() *Filter
 }
@@ -127,6 +136,7 @@ type Filter struct {
 	operators   FilterOperators
 	ands        SubFilters
 	ors         SubFilters
+	nots        SubFilters
 }
 
 // NewFilter returns a new filter.
@@ -139,6 +149,7 @@ func NewFilter() *Filter {
 		operators:   FilterOperators{},
 		ands:        SubFilters{},
 		ors:         SubFilters{},
+		nots:        SubFilters{},
 	}
 }
 
@@ -189,6 +200,11 @@ func (f *Filter) AndFilters() SubFilters {
 	return append(SubFilters{}, f.ands...)
 }
 
+// NotFilters returns the current negated sub filters.
+func (f *Filter) NotFilters() SubFilters {
+	return append(SubFilters{}, f.nots...)
+}
+
 // Equals adds a an equality comparator to the FilterComposer.
 func (f *Filter) Equals(value any) FilterKeyComposer {
 	f.values = f.values.add(value)
@@ -266,6 +282,13 @@ func (f *Filter) Matches(values ...any) FilterKeyComposer {
 	return f
 }
 
+// NotMatches adds a not match comparator to the FilterComposer.
+func (f *Filter) NotMatches(values ...any) FilterKeyComposer {
+	f.values = f.values.add(values...)
+	f.comparators = f.comparators.add(NotMatchComparator)
+	return f
+}
+
 // Exists adds an exists comparator to the FilterComposer.
 func (f *Filter) Exists() FilterKeyComposer {
 	f.values = f.values.add(true)
@@ -280,12 +303,34 @@ func (f *Filter) NotExists() FilterKeyComposer {
 	return f
 }
 
+// EqualsIgnoreCase adds a case insensitive equality comparator to the FilterComposer.
+func (f *Filter) EqualsIgnoreCase(value any) FilterKeyComposer {
+	f.values = f.values.add(value)
+	f.comparators = f.comparators.add(EqualIgnoreCaseComparator)
+	return f
+}
+
+// StartsWith adds a prefix comparator to the FilterComposer.
+func (f *Filter) StartsWith(value any) FilterKeyComposer {
+	f.values = f.values.add(value)
+	f.comparators = f.comparators.add(StartWithComparator)
+	return f
+}
+
+// EndsWith adds a suffix comparator to the FilterComposer.
+func (f *Filter) EndsWith(value any) FilterKeyComposer {
+	f.values = f.values.add(value)
+	f.comparators = f.comparators.add(EndWithComparator)
+	return f
+}
+
 // WithKey adds a key to FilterComposer.
 func (f *Filter) WithKey(key string) FilterValueComposer {
 	f.operators = append(f.operators, AndOperator)
 	f.keys = append(f.keys, key)
 	f.ands = append(f.ands, nil)
 	f.ors = append(f.ors, nil)
+	f.nots = append(f.nots, nil)
 	return f
 }
 
@@ -297,6 +342,7 @@ func (f *Filter) And(filters ...*Filter) FilterKeyComposer {
 	f.values = append(f.values, nil)
 	f.ands = append(f.ands, filters)
 	f.ors = append(f.ors, nil)
+	f.nots = append(f.nots, nil)
 	return f
 }
 
@@ -308,9 +354,34 @@ func (f *Filter) Or(filters ...*Filter) FilterKeyComposer {
 	f.values = append(f.values, nil)
 	f.ands = append(f.ands, nil)
 	f.ors = append(f.ors, filters)
+	f.nots = append(f.nots, nil)
+	return f
+}
+
+// Not adds a new negated sub filter to FilterComposer.
+// It matches when the given filters do not all match.
+func (f *Filter) Not(filters ...*Filter) FilterKeyComposer {
+	f.operators = append(f.operators, NotFilterOperator)
+	f.comparators = append(f.comparators, emptyComparator)
+	f.keys = append(f.keys, "")
+	f.values = append(f.values, nil)
+	f.ands = append(f.ands, nil)
+	f.ors = append(f.ors, nil)
+	f.nots = append(f.nots, filters)
 	return f
 }
 
+// addTerm adds a new key with the given comparator and values to the filter.
+func (f *Filter) addTerm(key string, comparator FilterComparator, values FilterValue) {
+	f.operators = append(f.operators, AndOperator)
+	f.keys = append(f.keys, key)
+	f.values = append(f.values, values)
+	f.comparators = append(f.comparators, comparator)
+	f.ands = append(f.ands, nil)
+	f.ors = append(f.ors, nil)
+	f.nots = append(f.nots, nil)
+}
+
 // Done terminates the filter composition and returns the *Filter.
 func (f *Filter) Done() *Filter {
 	return f
@@ -350,6 +421,13 @@ func (f *Filter) String() string {
 				strs = append(strs, fmt.Sprintf("(%s)", orf))
 			}
 			writeString(&buffer, fmt.Sprintf("(%s)", strings.Join(strs, " or ")))
+
+		case NotFilterOperator:
+			var strs []string
+			for _, notf := range f.nots[i] {
+				strs = append(strs, fmt.Sprintf("(%s)", notf))
+			}
+			writeString(&buffer, fmt.Sprintf("not (%s)", strings.Join(strs, " and ")))
 		}
 
 		if i+1 < len(f.operators) {
@@ -385,10 +463,18 @@ func translateComparator(comparator FilterComparator) string {
 		return "not contains"
 	case MatchComparator:
 		return "matches"
+	case NotMatchComparator:
+		return "not matches"
 	case ExistsComparator:
 		return "exists"
 	case NotExistsComparator:
 		return "not exists"
+	case EqualIgnoreCaseComparator:
+		return "=~"
+	case StartWithComparator:
+		return "startswith"
+	case EndWithComparator:
+		return "endswith"
 	default:
 		panic(fmt.Sprintf("Unknown comparator: %d", comparator))
 	}
@@ -397,7 +483,7 @@ func translateComparator(comparator FilterComparator) string {
 func translateOperator(operator FilterOperator) string {
 
 	switch operator {
-	case AndOperator, AndFilterOperator:
+	case AndOperator, AndFilterOperator, NotFilterOperator:
 		return "and"
 	case OrFilterOperator:
 		return "or"
@@ -412,16 +498,20 @@ func translateValue(comparator FilterComparator, value any) string {
 	if comparator != ContainComparator &&
 		comparator != InComparator &&
 		comparator != NotInComparator &&
-		comparator != MatchComparator {
+		comparator != MatchComparator &&
+		comparator != NotMatchComparator {
 		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
-			v = reflect.ValueOf(v.Index(0).Interface())
+			// not contains can hold multiple values, in which case they must all be written.
+			if comparator != NotContainComparator || v.Len() == 1 {
+				v = reflect.ValueOf(v.Index(0).Interface())
+			}
 		}
 	}
 
 	switch v.Kind() {
 
 	case reflect.String:
-		return fmt.Sprintf(`"%s"`, v.Interface())
+		return `"` + filterStringEscaper.Replace(v.String()) + `"`
 
 	case reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Int8, reflect.Uint, reflect.Uint16, reflect.Uint32,
@@ -450,6 +540,12 @@ func translateValue(comparator FilterComparator, value any) string {
 		return fmt.Sprintf(`[%s]`, strings.Join(final, ", "))
 
 	default:
+		switch vv := v.Interface().(type) {
+		case FilterPlaceholder:
+			return vv.String()
+		case FilterRelativeTime:
+			return vv.String()
+		}
 		if v.Type().Name() == "Time" {
 			return fmt.Sprintf(`date("%s")`, v.Interface().(time.Time).Format(time.RFC3339))
 		}
@@ -457,6 +553,11 @@ func translateValue(comparator FilterComparator, value any) string {
 	}
 }
 
+// filterStringEscaper escapes the characters that cannot be written
+// as is in a quoted string of a filter. The escaped strings are read
+// back as is by a FilterParser created with OptStringEscapes.
+var filterStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
 func writeString(buffer *bytes.Buffer, str string) {
 
 	if _, err := buffer.WriteString(str); err != nil {
@@ -383,7 +383,7 @@ func TestFilter_NewFilterFromString(t *testing.T) {
 
 			Convey("Then err should not be nil", func() {
 				So(err, ShouldNotBeNil)
-				So(err.Error(), ShouldEqual, `invalid operator. found = instead of (==, !=, <, <=, >, >=, contains, in, matches, exists)`)
+				So(err.(Error).Description, ShouldEqual, `invalid operator. found = instead of (==, !=, <, <=, >, >=, =~, contains, in, matches, exists, startswith, endswith)`)
 			})
 
 			Convey("Then filter should be nil", func() {
@@ -523,6 +523,78 @@ func TestFilter_Exists(t *testing.T) {
 	})
 }
 
+func TestFilter_StringNotContainsMultipleValues(t *testing.T) {
+
+	Convey("Given I create a filter with a not contains comparator with multiple values", t, func() {
+
+		f := NewFilterComposer().WithKey("key1").NotContains("a", "b").WithKey("key2").NotContains("c").Done()
+
+		Convey("When I call string it should be correct ", func() {
+			So(f.String(), ShouldEqual, `key1 not contains ["a", "b"] and key2 not contains "c"`)
+		})
+	})
+}
+
+func TestFilter_Not(t *testing.T) {
+
+	Convey("Given I create a filter with a negated sub filter", t, func() {
+
+		f := NewFilterComposer().
+			WithKey("a").Equals(1).
+			Not(
+				NewFilterComposer().WithKey("b").Equals(2).Done(),
+				NewFilterComposer().WithKey("c").NotMatches("^x", "y$").Done(),
+			).
+			Done()
+
+		Convey("Then the string representation should be correct", func() {
+			So(f.String(), ShouldEqual, `a == 1 and not ((b == 2) and (c not matches ["^x", "y$"]))`)
+		})
+
+		Convey("Then the accessors should be correct", func() {
+			So(f.Operators(), ShouldResemble, FilterOperators{AndOperator, NotFilterOperator})
+			So(len(f.NotFilters()), ShouldEqual, 2)
+			So(f.NotFilters()[0], ShouldBeNil)
+			So(len(f.NotFilters()[1]), ShouldEqual, 2)
+			So(len(f.AndFilters()), ShouldEqual, 2)
+			So(len(f.OrFilters()), ShouldEqual, 2)
+		})
+
+		Convey("Then it should be parsed back to a stable filter", func() {
+			pf, err := NewFilterFromString(f.String())
+			So(err, ShouldBeNil)
+			ppf, err := NewFilterFromString(pf.String())
+			So(err, ShouldBeNil)
+			So(ppf.String(), ShouldEqual, pf.String())
+		})
+	})
+}
+
+func TestFilter_StringComparators(t *testing.T) {
+
+	Convey("Given I create a filter with string comparators", t, func() {
+
+		f := NewFilterComposer().
+			WithKey("a").EqualsIgnoreCase("Hello").
+			WithKey("b").StartsWith("/ns").
+			WithKey("c").EndsWith(`say "hi" \o/`).
+			Done()
+
+		Convey("Then the string representation should be correct", func() {
+			So(f.String(), ShouldEqual, `a =~ "Hello" and b startswith "/ns" and c endswith "say \"hi\" \\o/"`)
+		})
+
+		Convey("Then it should be parsed back to the same values", func() {
+			pf, err := NewFilterParser(f.String(), OptStringEscapes()).Parse()
+			So(err, ShouldBeNil)
+			So(len(pf.AndFilters()), ShouldEqual, 1)
+			So(pf.AndFilters()[0][0].Values()[0][0], ShouldEqual, "Hello")
+			So(pf.AndFilters()[0][1].Values()[0][0], ShouldEqual, "/ns")
+			So(pf.AndFilters()[0][2].Values()[0][0], ShouldEqual, `say "hi" \o/`)
+		})
+	})
+}
+
 func Test_translateComparator(t *testing.T) {
 	type args struct {
 		comparator FilterComparator
@@ -543,8 +615,12 @@ func Test_translateComparator(t *testing.T) {
 		{"contains", args{ContainComparator}, "contains"},
 		{"not contains", args{NotContainComparator}, "not contains"},
 		{"matches", args{MatchComparator}, "matches"},
+		{"not matches", args{NotMatchComparator}, "not matches"},
 		{"exists", args{ExistsComparator}, "exists"},
 		{"not exists", args{NotExistsComparator}, "not exists"},
+		{"=~", args{EqualIgnoreCaseComparator}, "=~"},
+		{"startswith", args{StartWithComparator}, "startswith"},
+		{"endswith", args{EndWithComparator}, "endswith"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -573,6 +649,7 @@ func Test_translateOperator(t *testing.T) {
 		{"AndOperator", args{AndOperator}, "and"},
 		{"AndFilterOperator", args{AndOperator}, "and"},
 		{"OrFilterOperator", args{OrFilterOperator}, "or"},
+		{"NotFilterOperator", args{NotFilterOperator}, "and"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -588,3 +665,43 @@ func Test_translateOperator(t *testing.T) {
 		})
 	})
 }
+
+func Test_checkFilterBound(t *testing.T) {
+
+	tests := map[string]struct {
+		filter string
+		err    string
+	}{
+		"no placeholder":            {`a == 1 and (b == 2 or c in [3, 4])`, ""},
+		"placeholder":               {`a == 1 and b == $b`, "placeholder $b is not bound"},
+		"placeholder in a list":     {`a in [1, $a]`, "placeholder $a is not bound"},
+		"placeholder in a sublist":  {`a in [[1], [$a]]`, "placeholder $a is not bound"},
+		"placeholder in an or":      {`a == 1 and (b == 2 or c == $c)`, "placeholder $c is not bound"},
+		"placeholder in a negation": {`a == 1 and not (b == $b)`, "placeholder $b is not bound"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			filter, err := NewFilterParser(tt.filter, OptPlaceholders()).Parse()
+			if err != nil {
+				t.Fatalf("unable to parse filter: %s", err)
+			}
+
+			err = checkFilterBound(filter)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("did not expect to get an error, but received: %s", err)
+			case tt.err != "" && (err == nil || err.Error() != tt.err):
+				t.Errorf("expected error %q, got: %v", tt.err, err)
+			}
+
+			// it runs every time a filter is used.
+			if tt.err == "" {
+				if n := testing.AllocsPerRun(100, func() { _ = checkFilterBound(filter) }); n != 0 {
+					t.Errorf("expected no allocation, got %v", n)
+				}
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 // scanner scans a given input
 type scanner struct {
 	buf          bytes.Buffer
+	size         int
 	isWhitespace checkRuneFunc
 	isLetter     checkRuneFunc
 	isDigit      checkRuneFunc
@@ -34,6 +35,7 @@ func newScanner(
 
 	return &scanner{
 		buf:          buf,
+		size:         len(input),
 		isWhitespace: isWhitespace,
 		isLetter:     isLetter,
 		isDigit:      isDigit,
@@ -61,6 +63,11 @@ func (s *scanner) peekNextRune() rune {
 	return ch
 }
 
+// offset returns the byte offset of the next rune in the input.
+func (s *scanner) offset() int {
+	return s.size - s.buf.Len()
+}
+
 // unread a previously read rune
 func (s *scanner) unread() {
 	_ = s.buf.UnreadRune()
@@ -167,6 +174,31 @@ func (s *scanner) scanWord() (parserToken, string) {
 	return stringToToken(buf.String())
 }
 
+// scanQuoted consumes all the runes until the given closing quote, which is consumed
+// but not returned. A backslash escapes the next rune if it is a quote or a backslash.
+// The returned boolean is false if the input ends before the closing quote.
+func (s *scanner) scanQuoted(quote rune) (string, bool) {
+
+	var buf bytes.Buffer
+
+	for {
+		ch := s.read()
+
+		switch ch {
+		case runeEOF:
+			return buf.String(), false
+		case quote:
+			return buf.String(), true
+		case '\\':
+			if next := s.peekNextRune(); next == runeQUOTE || next == runeSINGLEQUOTE || next == '\\' {
+				ch = s.read()
+			}
+		}
+
+		_, _ = buf.WriteRune(ch)
+	}
+}
+
 func stringToToken(output string) (parserToken, string) {
 
 	upper := strings.ToUpper(output)
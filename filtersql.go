@@ -0,0 +1,244 @@
+package elemental
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FilterToSQL translates the given filter into a parameterized SQL WHERE clause (without the WHERE keyword)
+// and the list of arguments to bind to its placeholders. Values are never inlined in the clause, and column
+// names are always quoted, so the output is safe to use with user provided filters.
+//
+// The comparators are translated as follow:
+//
+//	==           "k" = ?            (or "k" IS NULL if the value is nil)
+//	!=           ("k" IS NULL OR "k" <> ?)   (or "k" IS NOT NULL if the value is nil)
+//	>, >=, <, <= "k" > ?
+//	in           "k" IN (?, ?)
+//	not in       ("k" IS NULL OR "k" NOT IN (?, ?))
+//	contains     "k" IN (?, ?)
+//	not contains ("k" IS NULL OR "k" NOT IN (?, ?))
+//	matches      ("k" ~ ? OR "k" ~ ?)
+//	not matches  ("k" IS NULL OR NOT ("k" ~ ? OR "k" ~ ?))
+//	exists       "k" IS NOT NULL
+//	not exists   "k" IS NULL
+//	=~           LOWER("k") = LOWER(?)
+//	startswith   "k" LIKE ? ESCAPE '\'   (with the value v%)
+//	endswith     "k" LIKE ? ESCAPE '\'   (with the value %v)
+//
+// As it is done in MatchesFilter, a NULL column matches !=, not in, not contains and not matches.
+// Contains and not contains are translated like in and not in, so they only support scalar columns,
+// and not array or JSON columns.
+//
+// The values of =~, startswith and endswith must be strings. The wildcards of LIKE are escaped so they are
+// matched literally. Note that SQLite's LIKE is case insensitive unless the case_sensitive_like pragma is on.
+//
+// Sub filters are combined using AND or OR, and negated sub filters are translated to NOT (...).
+// As it is done in MatchesFilter, time.Duration and FilterRelativeTime values are translated to a date relative to now.
+// An empty filter returns an empty clause. Dotted path keys (see ValueForPath) are not supported and return an error.
+func FilterToSQL(filter *Filter, opts ...SQLOption) (string, []any, error) {
+
+	if filter == nil {
+		panic(fmt.Errorf("elemental: filter cannot be nil"))
+	}
+
+	if err := checkFilterBound(filter); err != nil {
+		return "", nil, fmt.Errorf("elemental: unable to translate filter: %w", err)
+	}
+
+	var config sqlConfig
+	for _, o := range opts {
+		o(&config)
+	}
+
+	b := &sqlBuilder{config: config}
+	clause, err := b.build(filter)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return clause, b.args, nil
+}
+
+type sqlBuilder struct {
+	config sqlConfig
+	args   []any
+}
+
+func (b *sqlBuilder) build(filter *Filter) (string, error) {
+
+	items := make([]string, 0, len(filter.operators))
+
+	for i, op := range filter.operators {
+
+		switch op {
+
+		case AndOperator:
+			column, err := b.column(filter.keys[i])
+			if err != nil {
+				return "", err
+			}
+
+			item, err := b.comparator(column, filter.comparators[i], filter.values[i])
+			if err != nil {
+				return "", err
+			}
+
+			items = append(items, item)
+
+		case AndFilterOperator, OrFilterOperator, NotFilterOperator:
+			subs, sep := filter.ands[i], " AND "
+			switch op {
+			case OrFilterOperator:
+				subs, sep = filter.ors[i], " OR "
+			case NotFilterOperator:
+				subs = filter.nots[i]
+			}
+
+			subItems := make([]string, 0, len(subs))
+			for _, sub := range subs {
+				subItem, err := b.build(sub)
+				if err != nil {
+					return "", err
+				}
+				if subItem != "" {
+					subItems = append(subItems, "("+subItem+")")
+				}
+			}
+
+			switch {
+			case op == NotFilterOperator && len(subItems) == 0:
+				// the negation of a filter that always matches never matches.
+				items = append(items, "1 = 0")
+			case op == NotFilterOperator:
+				items = append(items, "NOT ("+strings.Join(subItems, sep)+")")
+			case len(subItems) > 0:
+				items = append(items, "("+strings.Join(subItems, sep)+")")
+			}
+		}
+	}
+
+	return strings.Join(items, " AND "), nil
+}
+
+func (b *sqlBuilder) comparator(column string, comparator FilterComparator, values FilterValue) (string, error) {
+
+	switch comparator {
+	case EqualComparator:
+		if values[0] == nil {
+			return column + " IS NULL", nil
+		}
+		return column + " = " + b.bind(values[0]), nil
+	case NotEqualComparator:
+		if values[0] == nil {
+			return column + " IS NOT NULL", nil
+		}
+		return "(" + column + " IS NULL OR " + column + " <> " + b.bind(values[0]) + ")", nil
+	case GreaterComparator:
+		return column + " > " + b.bind(values[0]), nil
+	case GreaterOrEqualComparator:
+		return column + " >= " + b.bind(values[0]), nil
+	case LesserComparator:
+		return column + " < " + b.bind(values[0]), nil
+	case LesserOrEqualComparator:
+		return column + " <= " + b.bind(values[0]), nil
+	case InComparator, ContainComparator:
+		if len(values) == 0 {
+			return "1 = 0", nil
+		}
+		return column + " IN (" + b.bindAll(values) + ")", nil
+	case NotInComparator, NotContainComparator:
+		if len(values) == 0 {
+			return "1 = 1", nil
+		}
+		return "(" + column + " IS NULL OR " + column + " NOT IN (" + b.bindAll(values) + "))", nil
+	case MatchComparator:
+		return b.regexes(column, values), nil
+	case NotMatchComparator:
+		return "(" + column + " IS NULL OR NOT " + b.regexes(column, values) + ")", nil
+	case ExistsComparator:
+		return column + " IS NOT NULL", nil
+	case NotExistsComparator:
+		return column + " IS NULL", nil
+	case EqualIgnoreCaseComparator, StartWithComparator, EndWithComparator:
+		s, ok := values[0].(string)
+		if !ok {
+			return "", fmt.Errorf("elemental: unable to translate filter: %s expects a string, got %T", translateComparator(comparator), values[0])
+		}
+		switch comparator {
+		case EqualIgnoreCaseComparator:
+			return "LOWER(" + column + ") = LOWER(" + b.bind(s) + ")", nil
+		case StartWithComparator:
+			return column + " LIKE " + b.bind(sqlLikeEscaper.Replace(s)+"%") + ` ESCAPE '\'`, nil
+		default:
+			return column + " LIKE " + b.bind("%"+sqlLikeEscaper.Replace(s)) + ` ESCAPE '\'`, nil
+		}
+	default:
+		return "", fmt.Errorf("elemental: unable to translate filter: unknown comparator %d", comparator)
+	}
+}
+
+func (b *sqlBuilder) regexes(column string, values FilterValue) string {
+
+	operator := " ~ "
+	if b.config.dialect == SQLDialectSQLite {
+		operator = " REGEXP "
+	}
+
+	items := make([]string, len(values))
+	for i, v := range values {
+		items[i] = column + operator + b.bind(v)
+	}
+
+	return "(" + strings.Join(items, " OR ") + ")"
+}
+
+// sqlLikeEscaper escapes the wildcards of a LIKE pattern.
+var sqlLikeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// bind adds the given value to the arguments and returns its placeholder.
+func (b *sqlBuilder) bind(value any) string {
+
+	if t, ok := relativeTimeValue(value, time.Now()); ok {
+		value = t
+	}
+
+	b.args = append(b.args, value)
+
+	if b.config.dialect == SQLDialectSQLite {
+		return "?"
+	}
+
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+func (b *sqlBuilder) bindAll(values FilterValue) string {
+
+	placeholders := make([]string, len(values))
+	for i, v := range values {
+		placeholders[i] = b.bind(v)
+	}
+
+	return strings.Join(placeholders, ", ")
+}
+
+// column returns the quoted column name for the given filter key.
+func (b *sqlBuilder) column(key string) (string, error) {
+
+	// nested values are stored differently by each database and schema.
+	if isPath(key) {
+		return "", fmt.Errorf("elemental: unable to translate filter: dotted path key %q is not supported", key)
+	}
+
+	if b.config.prototype != nil {
+		spec, ok := specificationForKey(b.config.prototype, key)
+		if !ok {
+			return "", fmt.Errorf("elemental: unable to translate filter: unknown attribute %q", key)
+		}
+		key = b.config.columnMapper(spec)
+	}
+
+	return `"` + strings.ReplaceAll(key, `"`, `""`) + `"`, nil
+}
@@ -0,0 +1,48 @@
+package elemental
+
+import "strings"
+
+// A SQLDialect represents the flavor of SQL that FilterToSQL will generate.
+type SQLDialect int
+
+// Various values for SQLDialect.
+const (
+	// SQLDialectPostgreSQL uses $1, $2... placeholders and the ~ operator for regular expressions.
+	SQLDialectPostgreSQL SQLDialect = iota
+
+	// SQLDialectSQLite uses ? placeholders and the REGEXP operator for regular expressions.
+	// Note that SQLite requires a regexp() user function to be registered to support it.
+	SQLDialectSQLite
+)
+
+type sqlConfig struct {
+	dialect      SQLDialect
+	prototype    AttributeSpecifiable
+	columnMapper func(AttributeSpecification) string
+}
+
+// SQLOption represents the type for the options that can be passed to `FilterToSQL` which can be used to
+// alter the generated SQL.
+type SQLOption func(*sqlConfig)
+
+// OptSQLDialect sets the SQL dialect to use. The default is SQLDialectPostgreSQL.
+func OptSQLDialect(dialect SQLDialect) SQLOption {
+	return func(config *sqlConfig) {
+		config.dialect = dialect
+	}
+}
+
+// OptSQLColumnMapper resolves the keys of the filter against the attribute specifications of the given prototype
+// and uses the given mapper to compute the column name from the resolved AttributeSpecification. If the mapper
+// is nil, the lower cased name of the attribute is used.
+//
+// When this option is used, FilterToSQL returns an error if a key is not an attribute of the prototype.
+func OptSQLColumnMapper(prototype AttributeSpecifiable, mapper func(AttributeSpecification) string) SQLOption {
+	return func(config *sqlConfig) {
+		config.prototype = prototype
+		config.columnMapper = mapper
+		if config.columnMapper == nil {
+			config.columnMapper = func(spec AttributeSpecification) string { return strings.ToLower(spec.Name) }
+		}
+	}
+}
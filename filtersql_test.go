@@ -0,0 +1,212 @@
+package elemental_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.aporeto.io/elemental"
+	testmodel "go.aporeto.io/elemental/test/model"
+)
+
+func TestFilterToSQL(t *testing.T) {
+
+	date := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		filter         *elemental.Filter
+		opts           []elemental.SQLOption
+		expectedClause string
+		expectedArgs   []any
+		expectedError  bool
+	}{
+		"empty filter": {
+			filter:         elemental.NewFilterComposer().Done(),
+			expectedClause: "",
+		},
+		"equal": {
+			filter:         elemental.NewFilterComposer().WithKey("name").Equals("Alice").Done(),
+			expectedClause: `"name" = $1`,
+			expectedArgs:   []any{"Alice"},
+		},
+		"equal and not equal to nil": {
+			filter:         elemental.NewFilterComposer().WithKey("a").Equals(nil).WithKey("b").NotEquals(nil).Done(),
+			expectedClause: `"a" IS NULL AND "b" IS NOT NULL`,
+		},
+		"ordering": {
+			filter: elemental.NewFilterComposer().
+				WithKey("a").NotEquals(0).
+				WithKey("b").GreaterThan(1).
+				WithKey("c").GreaterOrEqualThan(2).
+				WithKey("d").LesserThan(date).
+				WithKey("e").LesserOrEqualThan(4).
+				Done(),
+			expectedClause: `("a" IS NULL OR "a" <> $1) AND "b" > $2 AND "c" >= $3 AND "d" < $4 AND "e" <= $5`,
+			expectedArgs:   []any{0, 1, 2, date, 4},
+		},
+		"in and contains": {
+			filter: elemental.NewFilterComposer().
+				WithKey("a").In(1, 2).
+				WithKey("b").NotIn(3).
+				WithKey("c").Contains("x").
+				WithKey("d").NotContains("y", "z").
+				Done(),
+			expectedClause: `"a" IN ($1, $2) AND ("b" IS NULL OR "b" NOT IN ($3)) AND "c" IN ($4) AND ("d" IS NULL OR "d" NOT IN ($5, $6))`,
+			expectedArgs:   []any{1, 2, 3, "x", "y", "z"},
+		},
+		"empty in": {
+			filter:         elemental.NewFilterComposer().WithKey("a").In().WithKey("b").NotIn().Done(),
+			expectedClause: `1 = 0 AND 1 = 1`,
+		},
+		"matches with postgresql": {
+			filter:         elemental.NewFilterComposer().WithKey("a").Matches("^x", "y$").Done(),
+			expectedClause: `("a" ~ $1 OR "a" ~ $2)`,
+			expectedArgs:   []any{"^x", "y$"},
+		},
+		"matches with sqlite": {
+			filter:         elemental.NewFilterComposer().WithKey("a").Matches("^x", "y$").WithKey("b").Equals(1).Done(),
+			opts:           []elemental.SQLOption{elemental.OptSQLDialect(elemental.SQLDialectSQLite)},
+			expectedClause: `("a" REGEXP ? OR "a" REGEXP ?) AND "b" = ?`,
+			expectedArgs:   []any{"^x", "y$", 1},
+		},
+		"negative comparators match null columns": {
+			filter: elemental.NewFilterComposer().
+				WithKey("a").NotEquals("x").
+				WithKey("b").NotIn("y").
+				WithKey("c").NotContains("z").
+				WithKey("d").NotMatches("^x", "y$").
+				Done(),
+			opts:           []elemental.SQLOption{elemental.OptSQLDialect(elemental.SQLDialectSQLite)},
+			expectedClause: `("a" IS NULL OR "a" <> ?) AND ("b" IS NULL OR "b" NOT IN (?)) AND ("c" IS NULL OR "c" NOT IN (?)) AND ("d" IS NULL OR NOT ("d" REGEXP ? OR "d" REGEXP ?))`,
+			expectedArgs:   []any{"x", "y", "z", "^x", "y$"},
+		},
+		"exists": {
+			filter:         elemental.NewFilterComposer().WithKey("a").Exists().WithKey("b").NotExists().Done(),
+			expectedClause: `"a" IS NOT NULL AND "b" IS NULL`,
+		},
+		"sub filters": {
+			filter: elemental.NewFilterComposer().
+				WithKey("a").Equals(1).
+				Or(
+					elemental.NewFilterComposer().WithKey("b").Equals(2).Done(),
+					elemental.NewFilterComposer().And(
+						elemental.NewFilterComposer().WithKey("c").Equals(3).Done(),
+						elemental.NewFilterComposer().WithKey("d").Equals(4).Done(),
+					).Done(),
+				).
+				Done(),
+			expectedClause: `"a" = $1 AND (("b" = $2) OR ((("c" = $3) AND ("d" = $4))))`,
+			expectedArgs:   []any{1, 2, 3, 4},
+		},
+		"negated sub filter": {
+			filter: elemental.NewFilterComposer().
+				WithKey("a").Equals(1).
+				Not(
+					elemental.NewFilterComposer().WithKey("b").Equals(2).Done(),
+					elemental.NewFilterComposer().WithKey("c").NotMatches("^x").Done(),
+				).
+				Done(),
+			expectedClause: `"a" = $1 AND NOT (("b" = $2) AND (("c" IS NULL OR NOT ("c" ~ $3))))`,
+			expectedArgs:   []any{1, 2, "^x"},
+		},
+		"string comparators": {
+			filter: elemental.NewFilterComposer().
+				WithKey("a").EqualsIgnoreCase("Alice").
+				WithKey("b").StartsWith("50%_off").
+				WithKey("c").EndsWith(`\x`).
+				Done(),
+			expectedClause: `LOWER("a") = LOWER($1) AND "b" LIKE $2 ESCAPE '\' AND "c" LIKE $3 ESCAPE '\'`,
+			expectedArgs:   []any{"Alice", `50\%\_off%`, `%\\x`},
+		},
+		"string comparator with a non string value": {
+			filter:        elemental.NewFilterComposer().WithKey("a").StartsWith(1).Done(),
+			expectedError: true,
+		},
+		"negated empty sub filter": {
+			filter:         elemental.NewFilterComposer().Not(elemental.NewFilterComposer().Done()).Done(),
+			expectedClause: `1 = 0`,
+		},
+		"keys are quoted": {
+			filter:         elemental.NewFilterComposer().WithKey(`a" = 1; DROP TABLE users; --`).Equals(1).Done(),
+			expectedClause: `"a"" = 1; DROP TABLE users; --" = $1`,
+			expectedArgs:   []any{1},
+		},
+		"values are bound": {
+			filter:         elemental.NewFilterComposer().WithKey("a").Equals(`'; DROP TABLE users; --`).Done(),
+			expectedClause: `"a" = $1`,
+			expectedArgs:   []any{`'; DROP TABLE users; --`},
+		},
+		"column mapper with default mapping": {
+			filter:         elemental.NewFilterComposer().WithKey("firstName").Equals("Alice").Done(),
+			opts:           []elemental.SQLOption{elemental.OptSQLColumnMapper(testmodel.NewUser(), nil)},
+			expectedClause: `"firstname" = $1`,
+			expectedArgs:   []any{"Alice"},
+		},
+		"column mapper with custom mapping": {
+			filter: elemental.NewFilterComposer().WithKey("firstName").Equals("Alice").Done(),
+			opts: []elemental.SQLOption{
+				elemental.OptSQLColumnMapper(testmodel.NewUser(), func(spec elemental.AttributeSpecification) string {
+					return spec.BSONFieldName
+				}),
+			},
+			expectedClause: `"firstname" = $1`,
+			expectedArgs:   []any{"Alice"},
+		},
+		"dotted path key": {
+			filter:        elemental.NewFilterComposer().WithKey("name.owner").Equals("x").Done(),
+			expectedError: true,
+		},
+		"dotted path key with column mapper": {
+			filter:        elemental.NewFilterComposer().WithKey("name.owner").Equals("x").Done(),
+			opts:          []elemental.SQLOption{elemental.OptSQLColumnMapper(testmodel.NewTask(), nil)},
+			expectedError: true,
+		},
+		"column mapper with unknown attribute": {
+			filter:        elemental.NewFilterComposer().WithKey("unknown").Equals("Alice").Done(),
+			opts:          []elemental.SQLOption{elemental.OptSQLColumnMapper(testmodel.NewUser(), nil)},
+			expectedError: true,
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			clause, args, err := elemental.FilterToSQL(tc.filter, tc.opts...)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("error expectation failed: expected an error: %t, actual error: %v", tc.expectedError, err)
+			}
+
+			if clause != tc.expectedClause {
+				t.Errorf("unexpected clause\nexpected: %s\nactual:   %s", tc.expectedClause, clause)
+			}
+
+			if !reflect.DeepEqual(args, tc.expectedArgs) {
+				t.Errorf("unexpected args\nexpected: %#v\nactual:   %#v", tc.expectedArgs, args)
+			}
+		})
+	}
+}
+
+func TestFilterToSQL_Duration(t *testing.T) {
+
+	before := time.Now().Add(-time.Hour)
+
+	clause, args, err := elemental.FilterToSQL(elemental.NewFilterComposer().WithKey("date").GreaterThan(-time.Hour).Done())
+	if err != nil {
+		t.Fatalf("did not expect to get an error, but received: %s", err)
+	}
+
+	if !strings.HasPrefix(clause, `"date" > $1`) {
+		t.Errorf("unexpected clause: %s", clause)
+	}
+
+	value, ok := args[0].(time.Time)
+	if !ok {
+		t.Fatalf("expected the duration to be translated to a time.Time, got %T", args[0])
+	}
+
+	if value.Before(before) || value.After(time.Now().Add(-time.Hour)) {
+		t.Errorf("expected the duration to be relative to now, got %s", value)
+	}
+}
@@ -0,0 +1,204 @@
+package elemental
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A FilterTimeAnchor is the point in time a FilterRelativeTime is computed from.
+type FilterTimeAnchor string
+
+// Supported values for FilterTimeAnchor.
+const (
+	FilterTimeAnchorNow          FilterTimeAnchor = "now"
+	FilterTimeAnchorStartOfDay   FilterTimeAnchor = "startOfDay"
+	FilterTimeAnchorStartOfWeek  FilterTimeAnchor = "startOfWeek"
+	FilterTimeAnchorStartOfMonth FilterTimeAnchor = "startOfMonth"
+)
+
+// A FilterRelativeTime is a time of a Filter that is relative to the time the filter is evaluated,
+// like `now() - 7d` or `startOfDay("Europe/Paris") - 1mo`. It is computed when the filter is matched
+// or translated to a database query, in the same way time.Duration values are.
+//
+// In the filter grammar, a relative time is an anchor, now(), startOfDay(), startOfWeek() (weeks start
+// on monday) or startOfMonth(), followed by any number of offsets added or subtracted to it. An offset
+// is a number followed by a unit, which is either a calendar unit, mo (months), w (weeks) or d (days),
+// or a unit understood by time.ParseDuration, like h or m. Units can be combined, like `now() - 1d12h`.
+//
+// The start of the day, week and month, as well as the calendar units, are computed in the
+// given Location, which is an IANA time zone name like "Europe/Paris", or in UTC if empty.
+// The anchors other than now() can be given a location, like `startOfDay("America/New_York")`.
+type FilterRelativeTime struct {
+	Anchor   FilterTimeAnchor
+	Location string
+	Months   int
+	Days     int
+	Duration time.Duration
+}
+
+// Time returns the time represented by the FilterRelativeTime, relative to the given time.
+// If the location cannot be loaded, UTC is used. The parser checks the locations it reads.
+func (r FilterRelativeTime) Time(now time.Time) time.Time {
+
+	loc, err := loadFilterLocation(r.Location)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	t := now.In(loc)
+	year, month, day := t.Date()
+
+	switch r.Anchor {
+	case FilterTimeAnchorStartOfDay:
+		t = time.Date(year, month, day, 0, 0, 0, 0, loc)
+	case FilterTimeAnchorStartOfWeek:
+		t = time.Date(year, month, day-(int(t.Weekday())+6)%7, 0, 0, 0, 0, loc)
+	case FilterTimeAnchorStartOfMonth:
+		t = time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	}
+
+	return t.AddDate(0, r.Months, r.Days).Add(r.Duration)
+}
+
+// String returns the representation of the FilterRelativeTime in the filter grammar.
+func (r FilterRelativeTime) String() string {
+
+	var b strings.Builder
+
+	b.WriteString(string(r.Anchor))
+	b.WriteString("(")
+	if r.Location != "" {
+		b.WriteString(`"` + r.Location + `"`)
+	}
+	b.WriteString(")")
+
+	writeOffset := func(value int64, unit string) {
+		switch {
+		case value > 0:
+			b.WriteString(" + " + strconv.FormatInt(value, 10) + unit)
+		case value < 0:
+			b.WriteString(" - " + strconv.FormatInt(-value, 10) + unit)
+		}
+	}
+
+	writeOffset(int64(r.Months), "mo")
+	writeOffset(int64(r.Days), "d")
+
+	switch {
+	case r.Duration > 0:
+		b.WriteString(" + " + r.Duration.String())
+	case r.Duration < 0:
+		b.WriteString(" - " + (-r.Duration).String())
+	}
+
+	return b.String()
+}
+
+var filterTimeOffsetPattern = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)(mo|ms|us|µs|ns|w|d|h|m|s)`)
+
+// parseFilterTimeOffset parses an offset of a FilterRelativeTime, like 1mo2w3d4h.
+func parseFilterTimeOffset(offset string) (months int, days int, duration time.Duration, err error) {
+
+	if offset == "" {
+		return 0, 0, 0, fmt.Errorf("empty offset")
+	}
+
+	var clock string
+
+	for rest := offset; rest != ""; {
+
+		m := filterTimeOffsetPattern.FindStringSubmatch(rest)
+		if m == nil {
+			return 0, 0, 0, fmt.Errorf("invalid offset %q", rest)
+		}
+		rest = rest[len(m[0]):]
+
+		switch m[2] {
+		case "mo", "w", "d":
+			n, err := strconv.Atoi(m[1])
+			if err != nil {
+				return 0, 0, 0, fmt.Errorf("%s must be a whole number", m[2])
+			}
+			switch m[2] {
+			case "mo":
+				months += n
+			case "w":
+				days += 7 * n
+			case "d":
+				days += n
+			}
+		default:
+			clock += m[0]
+		}
+	}
+
+	if clock != "" {
+		if duration, err = time.ParseDuration(clock); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+
+	return months, days, duration, nil
+}
+
+// parseFilterRelativeTime parses a FilterRelativeTime written like FilterRelativeTime.String does.
+func parseFilterRelativeTime(s string) (FilterRelativeTime, error) {
+
+	f, err := NewFilterParser("t == " + s).Parse()
+	if err != nil {
+		return FilterRelativeTime{}, err
+	}
+
+	if values := f.Values(); len(values) == 1 && len(values[0]) == 1 {
+		switch v := values[0][0].(type) {
+		case FilterRelativeTime:
+			return v, nil
+		case time.Duration:
+			return FilterRelativeTime{Anchor: FilterTimeAnchorNow, Duration: v}, nil
+		}
+	}
+
+	return FilterRelativeTime{}, fmt.Errorf("invalid relative time %q", s)
+}
+
+var filterLocations sync.Map
+
+// loadFilterLocation returns the location with the given name, or UTC if it is empty.
+// Locations are cached as loading them reads the time zone database.
+func loadFilterLocation(name string) (*time.Location, error) {
+
+	if name == "" {
+		return time.UTC, nil
+	}
+
+	if loc, ok := filterLocations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+
+	filterLocations.Store(name, loc)
+
+	return loc, nil
+}
+
+// relativeTimeValue returns the time represented by the given filter value
+// if it is a time relative to the given time, like a time.Duration.
+func relativeTimeValue(value any, now time.Time) (time.Time, bool) {
+
+	switch v := value.(type) {
+	case time.Duration:
+		return now.Add(v), true
+	case FilterRelativeTime:
+		return v.Time(now), true
+	default:
+		return time.Time{}, false
+	}
+}
@@ -0,0 +1,248 @@
+package elemental_test
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // the tests must not depend on the time zone database of the system
+
+	"go.aporeto.io/elemental"
+	testmodel "go.aporeto.io/elemental/test/model"
+)
+
+func TestFilterRelativeTime_Time(t *testing.T) {
+
+	// a wednesday
+	now := time.Date(2024, 3, 13, 15, 4, 5, 0, time.UTC)
+
+	tests := map[string]struct {
+		relative elemental.FilterRelativeTime
+		expected time.Time
+	}{
+		"now": {
+			relative: elemental.FilterRelativeTime{Anchor: elemental.FilterTimeAnchorNow},
+			expected: now,
+		},
+		"now with offsets": {
+			relative: elemental.FilterRelativeTime{Anchor: elemental.FilterTimeAnchorNow, Months: -1, Days: -7, Duration: 2 * time.Hour},
+			expected: time.Date(2024, 2, 6, 17, 4, 5, 0, time.UTC),
+		},
+		"start of day": {
+			relative: elemental.FilterRelativeTime{Anchor: elemental.FilterTimeAnchorStartOfDay},
+			expected: time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC),
+		},
+		"start of day in a time zone": {
+			relative: elemental.FilterRelativeTime{Anchor: elemental.FilterTimeAnchorStartOfDay, Location: "Asia/Tokyo"},
+			expected: time.Date(2024, 3, 13, 15, 0, 0, 0, time.UTC),
+		},
+		"start of week": {
+			relative: elemental.FilterRelativeTime{Anchor: elemental.FilterTimeAnchorStartOfWeek},
+			expected: time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+		},
+		"start of month with offsets": {
+			relative: elemental.FilterRelativeTime{Anchor: elemental.FilterTimeAnchorStartOfMonth, Months: 1, Days: -1},
+			expected: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		},
+		"calendar units across a daylight saving time change": {
+			relative: elemental.FilterRelativeTime{Anchor: elemental.FilterTimeAnchorStartOfDay, Location: "Europe/Paris", Days: 19},
+			expected: time.Date(2024, 3, 31, 22, 0, 0, 0, time.UTC),
+		},
+		"unknown location": {
+			relative: elemental.FilterRelativeTime{Anchor: elemental.FilterTimeAnchorStartOfDay, Location: "Mars/Olympus"},
+			expected: time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+			if actual := tc.relative.Time(now); !actual.Equal(tc.expected) {
+				t.Errorf("unexpected time\nexpected: %s\nactual:   %s", tc.expected, actual.UTC())
+			}
+		})
+	}
+}
+
+func TestParser_RelativeTimes(t *testing.T) {
+
+	tests := map[string]struct {
+		filter   string
+		expected any
+		str      string
+	}{
+		"now without offsets": {
+			filter:   `date > now()`,
+			expected: time.Duration(0),
+			str:      `date > now()`,
+		},
+		"now with a duration": {
+			filter:   `date > now("-1h")`,
+			expected: -time.Hour,
+			str:      `date > now("-1h0m0s")`,
+		},
+		"now minus days": {
+			filter:   `date > now() - 7d`,
+			expected: elemental.FilterRelativeTime{Anchor: elemental.FilterTimeAnchorNow, Days: -7},
+			str:      `date > now() - 7d`,
+		},
+		"offsets without spaces": {
+			filter:   `date > now()-1w+12h`,
+			expected: elemental.FilterRelativeTime{Anchor: elemental.FilterTimeAnchorNow, Days: -7, Duration: 12 * time.Hour},
+			str:      `date > now() - 7d + 12h0m0s`,
+		},
+		"now with a duration and offsets": {
+			filter:   `date > now("-1h") + 1mo2d30m`,
+			expected: elemental.FilterRelativeTime{Anchor: elemental.FilterTimeAnchorNow, Months: 1, Days: 2, Duration: -30 * time.Minute},
+			str:      `date > now() + 1mo + 2d - 30m0s`,
+		},
+		"start of day": {
+			filter:   `date >= startOfDay()`,
+			expected: elemental.FilterRelativeTime{Anchor: elemental.FilterTimeAnchorStartOfDay},
+			str:      `date >= startOfDay()`,
+		},
+		"start of week in a time zone": {
+			filter:   `date < startOfWeek("Europe/Paris") - 1w`,
+			expected: elemental.FilterRelativeTime{Anchor: elemental.FilterTimeAnchorStartOfWeek, Location: "Europe/Paris", Days: -7},
+			str:      `date < startOfWeek("Europe/Paris") - 7d`,
+		},
+		"start of month": {
+			filter:   `date < startOfMonth() - 1mo - 1mo`,
+			expected: elemental.FilterRelativeTime{Anchor: elemental.FilterTimeAnchorStartOfMonth, Months: -2},
+			str:      `date < startOfMonth() - 2mo`,
+		},
+		"date in a time zone": {
+			filter:   `date > date("2020-01-02 10:00", "America/New_York")`,
+			expected: time.Date(2020, 1, 2, 15, 0, 0, 0, time.UTC),
+			str:      `date > date("2020-01-02T10:00:00-05:00")`,
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			filter, err := elemental.NewFilterFromString(tc.filter)
+			if err != nil {
+				t.Fatalf("unable to parse filter: %s", err)
+			}
+
+			value := filter.Values()[0][0]
+			if et, ok := tc.expected.(time.Time); ok {
+				if vt, ok := value.(time.Time); !ok || !vt.Equal(et) {
+					t.Errorf("unexpected value\nexpected: %#v\nactual:   %#v", tc.expected, value)
+				}
+			} else if value != tc.expected {
+				t.Errorf("unexpected value\nexpected: %#v\nactual:   %#v", tc.expected, value)
+			}
+
+			if filter.String() != tc.str {
+				t.Errorf("unexpected filter\nexpected: %s\nactual:   %s", tc.str, filter)
+			}
+
+			reparsed, err := elemental.NewFilterFromString(filter.String())
+			if err != nil {
+				t.Fatalf("unable to parse the string representation of the filter: %s", err)
+			}
+			if reparsed.String() != filter.String() {
+				t.Errorf("unexpected reparsed filter\nexpected: %s\nactual:   %s", filter, reparsed)
+			}
+		})
+	}
+}
+
+func TestParser_RelativeTimeErrors(t *testing.T) {
+
+	tests := map[string]struct {
+		filter         string
+		expectedOffset int
+	}{
+		"unknown unit":              {filter: `date > now() - 7x`, expectedOffset: 15},
+		"missing offset":            {filter: `date > now() -`, expectedOffset: 14},
+		"missing offset before and": {filter: `date > now() + and a == 1`, expectedOffset: 15},
+		"fractional days":           {filter: `date > now() - 1.5d`, expectedOffset: 15},
+		"unknown time zone":         {filter: `date > startOfDay("Mars/Olympus")`, expectedOffset: 7},
+		"unknown date time zone":    {filter: `date > date("2020-01-02", "Mars/Olympus")`, expectedOffset: 7},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			_, err := elemental.NewFilterFromString(tc.filter)
+			perr, ok := err.(elemental.Error)
+			if !ok {
+				t.Fatalf("expected an elemental.Error, got %T: %v", err, err)
+			}
+
+			if offset := perr.Data.(map[string]any)["offset"]; offset != tc.expectedOffset {
+				t.Errorf("unexpected offset: expected %d, got %v (%s)", tc.expectedOffset, offset, perr)
+			}
+		})
+	}
+}
+
+func TestMatchesFilter_RelativeTimes(t *testing.T) {
+
+	list := testmodel.NewList()
+	list.Date = time.Now().Add(-36 * time.Hour)
+
+	tests := map[string]struct {
+		filter        string
+		expectedMatch bool
+	}{
+		"after a week ago":           {filter: `date > now() - 1w`, expectedMatch: true},
+		"after a day ago":            {filter: `date > now() - 1d`, expectedMatch: false},
+		"before the start of day":    {filter: `date < startOfDay()`, expectedMatch: true},
+		"after three days ago":       {filter: `date >= startOfDay("Asia/Tokyo") - 3d`, expectedMatch: true},
+		"before two months from now": {filter: `date < startOfMonth() + 2mo`, expectedMatch: true},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			filter, err := elemental.NewFilterFromString(tc.filter)
+			if err != nil {
+				t.Fatalf("unable to parse filter: %s", err)
+			}
+
+			matched, err := elemental.MatchesFilter(list, filter)
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %s", err)
+			}
+			if matched != tc.expectedMatch {
+				t.Errorf("match expectation failed: expected a match: %t, matched occurred: %t", tc.expectedMatch, matched)
+			}
+
+			cf, err := elemental.CompileFilter(filter, list)
+			if err != nil {
+				t.Fatalf("unable to compile filter: %s", err)
+			}
+			if matched := cf.Match(list); matched != tc.expectedMatch {
+				t.Errorf("compiled match expectation failed: expected a match: %t, matched occurred: %t", tc.expectedMatch, matched)
+			}
+		})
+	}
+}
+
+func TestFilter_EncodeDecodeRelativeTimes(t *testing.T) {
+
+	filter, err := elemental.NewFilterFromString(`date > startOfWeek("Europe/Paris") - 1mo + 3d4h and date < now() + 1d`)
+	if err != nil {
+		t.Fatalf("unable to parse filter: %s", err)
+	}
+
+	for _, encoding := range []elemental.EncodingType{elemental.EncodingTypeJSON, elemental.EncodingTypeMSGPACK} {
+		t.Run(string(encoding), func(t *testing.T) {
+
+			data, err := elemental.Encode(encoding, filter)
+			if err != nil {
+				t.Fatalf("unable to encode filter: %s", err)
+			}
+
+			decoded := elemental.NewFilter()
+			if err := elemental.Decode(encoding, data, decoded); err != nil {
+				t.Fatalf("unable to decode filter: %s", err)
+			}
+
+			if decoded.String() != filter.String() {
+				t.Errorf("unexpected filter\nexpected: %s\nactual:   %s", filter, decoded)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package elemental
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A FilterTerm represents one comparison of a Filter, like `name == "a"`.
+type FilterTerm struct {
+	Key        string
+	Comparator FilterComparator
+	Values     FilterValue
+}
+
+// WalkFilter calls the given function on each term of the given filter, including the terms
+// of all its sub filters, depth first and in order. The walk stops at the first error returned
+// by the function, which is then returned.
+func WalkFilter(filter *Filter, visit func(term FilterTerm) error) error {
+
+	if filter == nil {
+		panic(fmt.Errorf("elemental: filter cannot be nil"))
+	}
+
+	for i, op := range filter.operators {
+
+		if op == AndOperator {
+			if err := visit(FilterTerm{
+				Key:        filter.keys[i],
+				Comparator: filter.comparators[i],
+				Values:     append(FilterValue{}, filter.values[i]...),
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, sub := range filter.subFilters(i) {
+			if err := WalkFilter(sub, visit); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// TransformFilter returns a new Filter where each term of the given filter, including the terms
+// of all its sub filters, is replaced by the term returned by the given function. If the function
+// returns false, the term is removed. The given filter is never modified.
+//
+// Sub filters that are left without any term are removed, as well as the and and or operators
+// that are left without any sub filter. Removing some terms of a negated sub filter makes the
+// negation match less objects, so a not operator left without any term is not removed, as this
+// would make it match all objects: it is kept with an empty sub filter, like `not (())`, and
+// never matches.
+func TransformFilter(filter *Filter, transform func(term FilterTerm) (FilterTerm, bool)) *Filter {
+
+	if filter == nil {
+		panic(fmt.Errorf("elemental: filter cannot be nil"))
+	}
+
+	out := NewFilter()
+
+	for i, op := range filter.operators {
+
+		if op == AndOperator {
+			term, keep := transform(FilterTerm{
+				Key:        filter.keys[i],
+				Comparator: filter.comparators[i],
+				Values:     append(FilterValue{}, filter.values[i]...),
+			})
+			if keep {
+				out.addTerm(term.Key, term.Comparator, append(FilterValue{}, term.Values...))
+			}
+			continue
+		}
+
+		subs := filter.subFilters(i)
+
+		transformed := make(SubFilter, 0, len(subs))
+		for _, sub := range subs {
+			tsub := TransformFilter(sub, transform)
+			if len(tsub.operators) == 0 && len(sub.operators) != 0 {
+				continue
+			}
+			transformed = append(transformed, tsub)
+		}
+
+		if len(transformed) == 0 && len(subs) != 0 {
+			if op != NotFilterOperator {
+				continue
+			}
+			transformed = append(transformed, NewFilter())
+		}
+
+		switch op {
+		case AndFilterOperator:
+			out.And(transformed...)
+		case OrFilterOperator:
+			out.Or(transformed...)
+		case NotFilterOperator:
+			out.Not(transformed...)
+		}
+	}
+
+	return out
+}
+
+// RenameFilterKeys returns a new Filter where the keys of the given filter are renamed using the
+// given mapping of old to new names. Keys are matched case insensitively, and dotted paths are
+// renamed using their root attribute. The given filter is never modified.
+//
+// It panics if two keys of the mapping only differ by their case, as a key of the filter
+// could then be renamed to either name.
+func RenameFilterKeys(filter *Filter, mapping map[string]string) *Filter {
+
+	lowered := make(map[string]string, len(mapping))
+	for from := range mapping {
+		if other, ok := lowered[strings.ToLower(from)]; ok {
+			panic(fmt.Errorf("elemental: mapping keys '%s' and '%s' only differ by their case", other, from))
+		}
+		lowered[strings.ToLower(from)] = from
+	}
+
+	return TransformFilter(filter, func(term FilterTerm) (FilterTerm, bool) {
+
+		root, rest, nested := strings.Cut(term.Key, ".")
+
+		to, ok := mapping[root]
+		if !ok {
+			for from, name := range mapping {
+				if strings.EqualFold(root, from) {
+					to, ok = name, true
+					break
+				}
+			}
+		}
+
+		if !ok {
+			return term, true
+		}
+
+		term.Key = to
+		if nested {
+			term.Key += "." + rest
+		}
+
+		return term, true
+	})
+}
+
+// RemoveFilterKeys returns a new Filter where the terms of the given filter using one of the given
+// keys are removed, as described by TransformFilter. Keys are matched case insensitively, and dotted
+// paths are matched using their root attribute. The given filter is never modified.
+func RemoveFilterKeys(filter *Filter, keys ...string) *Filter {
+
+	return TransformFilter(filter, func(term FilterTerm) (FilterTerm, bool) {
+
+		for _, key := range keys {
+			if strings.EqualFold(pathRoot(term.Key), key) {
+				return term, false
+			}
+		}
+
+		return term, true
+	})
+}
+
+// ConjoinFilters returns a new Filter that only matches the objects matching the given filter and all
+// the other given filters, like `(filter) and (other1) and (other2)`. This can be used to restrict a
+// user provided filter, for instance to a namespace. The given filters are never modified.
+func ConjoinFilters(filter *Filter, others ...*Filter) *Filter {
+
+	identity := func(term FilterTerm) (FilterTerm, bool) { return term, true }
+
+	// the operators of a filter are combined with an and, so
+	// the operators of the others can simply be appended.
+	out := TransformFilter(filter, identity)
+
+	for _, other := range others {
+
+		if other == nil {
+			panic(fmt.Errorf("elemental: filter cannot be nil"))
+		}
+
+		o := TransformFilter(other, identity)
+
+		out.operators = append(out.operators, o.operators...)
+		out.keys = append(out.keys, o.keys...)
+		out.values = append(out.values, o.values...)
+		out.comparators = append(out.comparators, o.comparators...)
+		out.ands = append(out.ands, o.ands...)
+		out.ors = append(out.ors, o.ors...)
+		out.nots = append(out.nots, o.nots...)
+	}
+
+	return out
+}
+
+// subFilters returns the sub filters of the operator at the given index.
+func (f *Filter) subFilters(i int) SubFilter {
+
+	switch f.operators[i] {
+	case AndFilterOperator:
+		return f.ands[i]
+	case OrFilterOperator:
+		return f.ors[i]
+	case NotFilterOperator:
+		return f.nots[i]
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,229 @@
+package elemental_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.aporeto.io/elemental"
+	testmodel "go.aporeto.io/elemental/test/model"
+)
+
+func makeTransformFilter() *elemental.Filter {
+
+	return elemental.NewFilterComposer().
+		WithKey("name").Equals("a").
+		Or(
+			elemental.NewFilterComposer().WithKey("Namespace").StartsWith("/a").Done(),
+			elemental.NewFilterComposer().WithKey("tags").Contains("x", "y").WithKey("namespace.owner").Exists().Done(),
+		).
+		Not(
+			elemental.NewFilterComposer().WithKey("namespace").Equals("/b").Done(),
+		).
+		Done()
+}
+
+func TestWalkFilter(t *testing.T) {
+
+	filter := makeTransformFilter()
+
+	var keys []string
+	var comparators elemental.FilterComparators
+	err := elemental.WalkFilter(filter, func(term elemental.FilterTerm) error {
+		keys = append(keys, term.Key)
+		comparators = append(comparators, term.Comparator)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("did not expect to get an error, but received: %s", err)
+	}
+
+	expectedKeys := []string{"name", "Namespace", "tags", "namespace.owner", "namespace"}
+	if !reflect.DeepEqual(keys, expectedKeys) {
+		t.Errorf("unexpected keys\nexpected: %v\nactual:   %v", expectedKeys, keys)
+	}
+
+	expectedComparators := elemental.FilterComparators{
+		elemental.EqualComparator,
+		elemental.StartWithComparator,
+		elemental.ContainComparator,
+		elemental.ExistsComparator,
+		elemental.EqualComparator,
+	}
+	if !reflect.DeepEqual(comparators, expectedComparators) {
+		t.Errorf("unexpected comparators\nexpected: %v\nactual:   %v", expectedComparators, comparators)
+	}
+
+	errStop := errors.New("stop")
+	var visited int
+	err = elemental.WalkFilter(filter, func(term elemental.FilterTerm) error {
+		visited++
+		if term.Comparator == elemental.StartWithComparator {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop {
+		t.Errorf("expected the error of the visitor, got: %v", err)
+	}
+	if visited != 2 {
+		t.Errorf("expected the walk to stop after 2 terms, got %d", visited)
+	}
+}
+
+func TestTransformFilter(t *testing.T) {
+
+	tests := map[string]struct {
+		transform func(elemental.FilterTerm) (elemental.FilterTerm, bool)
+		expected  string
+	}{
+		"identity": {
+			transform: func(term elemental.FilterTerm) (elemental.FilterTerm, bool) { return term, true },
+			expected:  `name == "a" or ((Namespace startswith "/a") or (tags contains ["x", "y"] and namespace.owner exists)) and not ((namespace == "/b"))`,
+		},
+		"change values": {
+			transform: func(term elemental.FilterTerm) (elemental.FilterTerm, bool) {
+				if s, ok := term.Values[0].(string); ok {
+					term.Values[0] = strings.ToUpper(s)
+				}
+				return term, true
+			},
+			expected: `name == "A" or ((Namespace startswith "/A") or (tags contains ["X", "y"] and namespace.owner exists)) and not ((namespace == "/B"))`,
+		},
+		"remove terms and empty sub filters": {
+			transform: func(term elemental.FilterTerm) (elemental.FilterTerm, bool) {
+				return term, term.Comparator != elemental.StartWithComparator && term.Comparator != elemental.EqualComparator
+			},
+			expected: `((tags contains ["x", "y"] and namespace.owner exists)) and not (())`,
+		},
+		"remove everything": {
+			transform: func(term elemental.FilterTerm) (elemental.FilterTerm, bool) { return term, false },
+			expected:  `not (())`,
+		},
+		"remove everything but the negation": {
+			transform: func(term elemental.FilterTerm) (elemental.FilterTerm, bool) { return term, term.Key == "namespace" },
+			expected:  `not ((namespace == "/b"))`,
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			filter := makeTransformFilter()
+			original := filter.String()
+
+			out := elemental.TransformFilter(filter, tc.transform)
+			if out.String() != tc.expected {
+				t.Errorf("unexpected filter\nexpected: %s\nactual:   %s", tc.expected, out)
+			}
+
+			if filter.String() != original {
+				t.Errorf("the original filter has been modified: %s", filter)
+			}
+		})
+	}
+}
+
+func TestTransformFilter_EmptiedNegation(t *testing.T) {
+
+	obj := &testmodel.List{Name: "a", Description: "b"}
+
+	filter := elemental.NewFilterComposer().
+		WithKey("name").Equals("a").
+		Not(elemental.NewFilterComposer().WithKey("description").Equals("b").Done()).
+		Done()
+
+	matched, err := elemental.MatchesFilter(obj, filter)
+	if err != nil {
+		t.Fatalf("did not expect to get an error, but received: %s", err)
+	}
+	if matched {
+		t.Fatalf("test setup invalid - the original filter should not match")
+	}
+
+	// the negation must not match more objects once its terms are removed.
+	out := elemental.RemoveFilterKeys(filter, "description")
+
+	matched, err = elemental.MatchesFilter(obj, out)
+	if err != nil {
+		t.Fatalf("did not expect to get an error, but received: %s", err)
+	}
+	if matched {
+		t.Errorf("expected the emptied negation to never match: %s", out)
+	}
+}
+
+func TestRenameFilterKeys(t *testing.T) {
+
+	filter := makeTransformFilter()
+	original := filter.String()
+
+	out := elemental.RenameFilterKeys(filter, map[string]string{"namespace": "ns", "tags": "labels"})
+
+	expected := `name == "a" or ((ns startswith "/a") or (labels contains ["x", "y"] and ns.owner exists)) and not ((ns == "/b"))`
+	if out.String() != expected {
+		t.Errorf("unexpected filter\nexpected: %s\nactual:   %s", expected, out)
+	}
+
+	if filter.String() != original {
+		t.Errorf("the original filter has been modified: %s", filter)
+	}
+}
+
+func TestRenameFilterKeys_CaseCollision(t *testing.T) {
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected a panic when two keys of the mapping only differ by their case")
+		}
+	}()
+
+	elemental.RenameFilterKeys(makeTransformFilter(), map[string]string{"namespace": "ns", "Namespace": "namespaceID"})
+}
+
+func TestRemoveFilterKeys(t *testing.T) {
+
+	filter := makeTransformFilter()
+	original := filter.String()
+
+	out := elemental.RemoveFilterKeys(filter, "NAMESPACE")
+
+	expected := `name == "a" or ((tags contains ["x", "y"])) and not (())`
+	if out.String() != expected {
+		t.Errorf("unexpected filter\nexpected: %s\nactual:   %s", expected, out)
+	}
+
+	if filter.String() != original {
+		t.Errorf("the original filter has been modified: %s", filter)
+	}
+}
+
+func TestConjoinFilters(t *testing.T) {
+
+	filter := makeTransformFilter()
+	original := filter.String()
+
+	restriction := elemental.NewFilterComposer().WithKey("namespace").Equals("/tenant").Done()
+
+	out := elemental.ConjoinFilters(elemental.RemoveFilterKeys(filter, "namespace"), restriction)
+
+	expected := `name == "a" or ((tags contains ["x", "y"])) and not (()) and namespace == "/tenant"`
+	if out.String() != expected {
+		t.Errorf("unexpected filter\nexpected: %s\nactual:   %s", expected, out)
+	}
+
+	if filter.String() != original {
+		t.Errorf("the original filter has been modified: %s", filter)
+	}
+
+	// the result must be independent from its sources.
+	elemental.ConjoinFilters(elemental.NewFilter(), restriction).Values()[0][0] = "changed"
+	if restriction.Values()[0][0] != "/tenant" {
+		t.Errorf("the restriction has been modified: %s", restriction)
+	}
+
+	if s := elemental.ConjoinFilters(elemental.NewFilter(), restriction).String(); s != `namespace == "/tenant"` {
+		t.Errorf("unexpected filter when conjoining an empty filter: %s", s)
+	}
+}
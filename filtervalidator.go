@@ -0,0 +1,241 @@
+package elemental
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+)
+
+const (
+	filterUnknownAttributeFormat        = `Unknown attribute '%s' in filter`
+	filterNotFilterableFormat           = `Attribute '%s' is not filterable`
+	filterInvalidPathFormat             = `Invalid key '%s' in filter: attribute '%s' of type '%s' has no nested values`
+	filterInvalidComparatorFormat       = `Comparator '%s' cannot be used on attribute '%s' of type '%s'`
+	filterInvalidValueFormat            = `Invalid value for attribute '%s' in filter: %s`
+	filterInvalidRegexpFormat           = `Invalid regular expression for attribute '%s' in filter: %s`
+	filterUnknownIdentityFormat         = `Unknown identity '%s'`
+	filterNotAttributeSpecifiableFormat = `Identity '%s' does not support attribute specifications`
+)
+
+// ValidateFilter validates the given filter against the attribute specifications of the given
+// AttributeSpecifiable, before it is used to query a database for instance.
+//
+// It returns nil if the filter is valid, or an Errors containing:
+//   - a 400 error for each key that is not an attribute of the model
+//   - a 400 error for each key that is an attribute that is not Filterable
+//   - a 400 error for each dotted path key under an attribute that has no nested values, like a string
+//   - a 422 error for each comparator that cannot be used with the type of the attribute
+//     (matches, =~, startswith and endswith on non string types)
+//   - a 422 error for each value that does not match the type of the attribute, or that
+//     is not a valid regular expression
+//   - a 422 error for each placeholder that has not been bound (see Filter.Bind)
+//
+// Keys that are dotted paths (see ValueForPath) are checked using their root attribute, and the values
+// compared to nested values are not type checked.
+func ValidateFilter(filter *Filter, as AttributeSpecifiable) error {
+
+	if filter == nil {
+		panic(fmt.Errorf("elemental: filter cannot be nil"))
+	}
+
+	if as == nil {
+		panic(fmt.Errorf("elemental: attribute specifiable cannot be nil"))
+	}
+
+	if errs := validateFilter(filter, as, NewErrors()); len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// ValidateFilterForIdentity validates the given filter against the attribute specifications of
+// the model of the given identity. See ValidateFilter for details.
+func ValidateFilterForIdentity(filter *Filter, manager ModelManager, identity Identity) error {
+
+	identifiable := manager.Identifiable(identity)
+	if identifiable == nil {
+		return NewErrors(NewError("Bad Request", fmt.Sprintf(filterUnknownIdentityFormat, identity.Name), "elemental", http.StatusBadRequest))
+	}
+
+	as, ok := identifiable.(AttributeSpecifiable)
+	if !ok {
+		return NewErrors(NewError("Bad Request", fmt.Sprintf(filterNotAttributeSpecifiableFormat, identity.Name), "elemental", http.StatusBadRequest))
+	}
+
+	return ValidateFilter(filter, as)
+}
+
+func validateFilter(filter *Filter, as AttributeSpecifiable, errs Errors) Errors {
+
+	for i, op := range filter.operators {
+
+		switch op {
+
+		case AndOperator:
+			errs = validateFilterTerm(filter.keys[i], filter.comparators[i], filter.values[i], as, errs)
+
+		case AndFilterOperator:
+			for _, sub := range filter.ands[i] {
+				errs = validateFilter(sub, as, errs)
+			}
+
+		case OrFilterOperator:
+			for _, sub := range filter.ors[i] {
+				errs = validateFilter(sub, as, errs)
+			}
+
+		case NotFilterOperator:
+			for _, sub := range filter.nots[i] {
+				errs = validateFilter(sub, as, errs)
+			}
+		}
+	}
+
+	return errs
+}
+
+func validateFilterTerm(key string, comparator FilterComparator, values FilterValue, as AttributeSpecifiable, errs Errors) Errors {
+
+	spec, ok := specificationForKey(as, pathRoot(key))
+	if !ok {
+		return append(errs, makeFilterError(http.StatusBadRequest, key, filterUnknownAttributeFormat, key))
+	}
+
+	if !spec.Filterable {
+		return append(errs, makeFilterError(http.StatusBadRequest, key, filterNotFilterableFormat, key))
+	}
+
+	if isPath(key) && !hasNestedValues(spec) {
+		return append(errs, makeFilterError(http.StatusBadRequest, key, filterInvalidPathFormat, key, spec.Name, spec.Type))
+	}
+
+	for _, v := range values {
+		for _, item := range filterValueItems(v, true) {
+			if p, ok := item.(FilterPlaceholder); ok {
+				return append(errs, makeFilterError(http.StatusUnprocessableEntity, key, filterUnboundPlaceholderFormat, p.Name, key))
+			}
+		}
+	}
+
+	// the type of nested values is not described by the specifications.
+	nested := isPath(key)
+	if nested {
+		spec.Type, spec.SubType = "", ""
+	}
+
+	switch comparator {
+
+	case ExistsComparator, NotExistsComparator:
+		return errs
+
+	case MatchComparator, NotMatchComparator:
+		switch {
+		case nested, spec.Type == "string", spec.Type == "enum", spec.Type == "list":
+		default:
+			return append(errs, makeFilterError(http.StatusUnprocessableEntity, key, filterInvalidComparatorFormat, translateComparator(comparator), key, spec.Type))
+		}
+
+		for _, v := range values {
+			s, ok := v.(string)
+			if !ok {
+				errs = append(errs, makeFilterError(http.StatusUnprocessableEntity, key, filterInvalidRegexpFormat, key, fmt.Sprintf("expected a string, got %T", v)))
+				continue
+			}
+			if _, err := regexp.Compile(s); err != nil {
+				errs = append(errs, makeFilterError(http.StatusUnprocessableEntity, key, filterInvalidRegexpFormat, key, err))
+			}
+		}
+
+		return errs
+
+	case EqualIgnoreCaseComparator, StartWithComparator, EndWithComparator:
+		switch {
+		case nested, spec.Type == "string", spec.Type == "enum", spec.Type == "list":
+		default:
+			return append(errs, makeFilterError(http.StatusUnprocessableEntity, key, filterInvalidComparatorFormat, translateComparator(comparator), key, spec.Type))
+		}
+
+		for _, v := range values {
+			if _, ok := v.(string); !ok {
+				errs = append(errs, makeFilterError(http.StatusUnprocessableEntity, key, filterInvalidValueFormat, key, fmt.Sprintf("expected a string, got %T", v)))
+			}
+		}
+
+		return errs
+	}
+
+	// lists are checked against the type of their elements.
+	typ := spec.Type
+	if typ == "list" {
+		typ = spec.SubType
+	}
+
+	for _, v := range values {
+		for _, item := range filterValueItems(v, spec.Type == "list") {
+			if _, err := convertValueForType(typ, item); err != nil {
+				errs = append(errs, makeFilterError(http.StatusUnprocessableEntity, key, filterInvalidValueFormat, key, err))
+			}
+		}
+	}
+
+	return errs
+}
+
+// scalarAttributeTypes are the attribute types that have no nested values.
+var scalarAttributeTypes = map[string]struct{}{
+	"string":  {},
+	"enum":    {},
+	"integer": {},
+	"float":   {},
+	"boolean": {},
+	"time":    {},
+}
+
+// hasNestedValues returns true if the attribute of the given specification
+// can hold values that can be reached using a dotted path.
+func hasNestedValues(spec AttributeSpecification) bool {
+
+	if spec.Type == "list" {
+		_, scalar := scalarAttributeTypes[spec.SubType]
+		return !scalar
+	}
+
+	_, scalar := scalarAttributeTypes[spec.Type]
+	return !scalar
+}
+
+// filterValueItems returns the items to type check for the given filter value. When the attribute
+// is a list, the value can either be one element or a list of elements.
+func filterValueItems(value any, list bool) []any {
+
+	if !list || value == nil {
+		return []any{value}
+	}
+
+	v := reflect.ValueOf(value)
+	if !isArrayLike(v) {
+		return []any{value}
+	}
+
+	items := make([]any, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		items[i] = v.Index(i).Interface()
+	}
+
+	return items
+}
+
+func makeFilterError(code int, attribute string, format string, args ...any) Error {
+
+	title := "Validation Error"
+	if code == http.StatusBadRequest {
+		title = "Bad Request"
+	}
+
+	err := NewError(title, fmt.Sprintf(format, args...), "elemental", code)
+	err.Data = map[string]any{"attribute": attribute}
+
+	return err
+}
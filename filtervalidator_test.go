@@ -0,0 +1,254 @@
+package elemental_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"go.aporeto.io/elemental"
+	"go.aporeto.io/elemental/internal"
+	testmodel "go.aporeto.io/elemental/test/model"
+)
+
+func TestValidateFilter(t *testing.T) {
+
+	tests := map[string]struct {
+		filter        string
+		prototype     elemental.AttributeSpecifiable
+		expectedCodes []int
+	}{
+		"valid filter": {
+			filter:    `name == "x" and description matches "^a" and slice contains ["a", "b"] and date > now("-1h")`,
+			prototype: testmodel.NewList(),
+		},
+		"valid filter using the exposed name": {
+			filter:    `firstName == "x" and userName != nil`,
+			prototype: testmodel.NewUser(),
+		},
+		"valid filter with sub filters": {
+			filter:    `name == "x" and (description == "a" or (slice == "b" and date exists))`,
+			prototype: testmodel.NewList(),
+		},
+		"unknown attribute": {
+			filter:        `nope == "x"`,
+			prototype:     testmodel.NewList(),
+			expectedCodes: []int{http.StatusBadRequest},
+		},
+		"non filterable attribute": {
+			filter:        `archived == true`,
+			prototype:     testmodel.NewUser(),
+			expectedCodes: []int{http.StatusBadRequest},
+		},
+		"type mismatch": {
+			filter:        `name == 3`,
+			prototype:     testmodel.NewList(),
+			expectedCodes: []int{http.StatusUnprocessableEntity},
+		},
+		"type mismatch in list": {
+			filter:        `name in ["a", 3]`,
+			prototype:     testmodel.NewList(),
+			expectedCodes: []int{http.StatusUnprocessableEntity},
+		},
+		"type mismatch on list sub type": {
+			filter:        `slice contains ["a", true]`,
+			prototype:     testmodel.NewList(),
+			expectedCodes: []int{http.StatusUnprocessableEntity},
+		},
+		"type mismatch on date": {
+			filter:        `date > "yesterday"`,
+			prototype:     testmodel.NewList(),
+			expectedCodes: []int{http.StatusUnprocessableEntity},
+		},
+		"matches on a date": {
+			filter:        `date matches "^2020"`,
+			prototype:     testmodel.NewList(),
+			expectedCodes: []int{http.StatusUnprocessableEntity},
+		},
+		"string comparators": {
+			filter:    `name =~ "x" and description startswith "a" and slice endswith "b"`,
+			prototype: testmodel.NewList(),
+		},
+		"string comparator on a date": {
+			filter:        `date startswith "2020"`,
+			prototype:     testmodel.NewList(),
+			expectedCodes: []int{http.StatusUnprocessableEntity},
+		},
+		"string comparator with a non string value": {
+			filter:        `name =~ 3`,
+			prototype:     testmodel.NewList(),
+			expectedCodes: []int{http.StatusUnprocessableEntity},
+		},
+		"invalid regular expression": {
+			filter:        `name matches "(a"`,
+			prototype:     testmodel.NewList(),
+			expectedCodes: []int{http.StatusUnprocessableEntity},
+		},
+		"errors in negated sub filters are collected": {
+			filter:        `not (name == 1 or nope == "x")`,
+			prototype:     testmodel.NewList(),
+			expectedCodes: []int{http.StatusUnprocessableEntity, http.StatusBadRequest},
+		},
+		"errors in sub filters are collected": {
+			filter:        `nope == "x" and (name == 1 or (date matches "a"))`,
+			prototype:     testmodel.NewList(),
+			expectedCodes: []int{http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusUnprocessableEntity},
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			filter, err := elemental.NewFilterFromString(tc.filter)
+			if err != nil {
+				t.Fatalf("test setup invalid - unable to parse filter: %s", err)
+			}
+
+			err = elemental.ValidateFilter(filter, tc.prototype)
+			if len(tc.expectedCodes) == 0 {
+				if err != nil {
+					t.Fatalf("did not expect to get an error, but received: %s", err)
+				}
+				return
+			}
+
+			var errs elemental.Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("expected an elemental.Errors, got: %#v", err)
+			}
+
+			if len(errs) != len(tc.expectedCodes) {
+				t.Fatalf("expected %d errors, got %d: %s", len(tc.expectedCodes), len(errs), errs)
+			}
+
+			for i, code := range tc.expectedCodes {
+				if errs[i].Code != code {
+					t.Errorf("expected error %d to have code %d, got: %s", i, code, errs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateFilter_Types(t *testing.T) {
+
+	specs := map[string]elemental.AttributeSpecification{
+		"count":  {Name: "count", Type: "integer", Filterable: true},
+		"ratio":  {Name: "ratio", Type: "float", Filterable: true},
+		"active": {Name: "active", Type: "boolean", Filterable: true},
+	}
+
+	tests := map[string]struct {
+		filter        string
+		expectedError bool
+	}{
+		"integer with an integer":  {filter: `count > 3`},
+		"integer with a float":     {filter: `count > 3.5`, expectedError: true},
+		"integer with a string":    {filter: `count == "3"`, expectedError: true},
+		"float with an integer":    {filter: `ratio <= 1`},
+		"float with a float":       {filter: `ratio <= 0.5`},
+		"boolean with a boolean":   {filter: `active == true`},
+		"boolean with a string":    {filter: `active == "true"`, expectedError: true},
+		"boolean with an ordering": {filter: `active > false`},
+		"integer with matches":     {filter: `count matches "1"`, expectedError: true},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAS := internal.NewMockAttributeSpecifiable(ctrl)
+			mockAS.
+				EXPECT().
+				SpecificationForAttribute(gomock.Any()).
+				DoAndReturn(func(name string) elemental.AttributeSpecification { return specs[name] }).
+				AnyTimes()
+
+			filter, err := elemental.NewFilterFromString(tc.filter)
+			if err != nil {
+				t.Fatalf("test setup invalid - unable to parse filter: %s", err)
+			}
+
+			err = elemental.ValidateFilter(filter, mockAS)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("error expectation failed: expected an error: %t, actual error: %v", tc.expectedError, err)
+			}
+
+			if err != nil && err.(elemental.Errors).Code() != http.StatusUnprocessableEntity {
+				t.Errorf("expected a validation error, got: %s", err)
+			}
+		})
+	}
+}
+
+func TestValidateFilter_Paths(t *testing.T) {
+
+	specs := map[string]elemental.AttributeSpecification{
+		"name":        {Name: "name", Type: "string", Filterable: true},
+		"tags":        {Name: "tags", Type: "list", SubType: "string", Filterable: true},
+		"annotations": {Name: "annotations", Type: "external", SubType: "map[string]string", Filterable: true},
+		"children":    {Name: "children", Type: "refList", SubType: "child", Filterable: true},
+		"objects":     {Name: "objects", Type: "list", SubType: "object", Filterable: true},
+	}
+
+	tests := map[string]struct {
+		filter        string
+		expectedError bool
+	}{
+		"path under a map":             {filter: `annotations.owner == "x"`},
+		"path under a ref list":        {filter: `children.name == "x"`},
+		"path under a list of objects": {filter: `objects.name exists`},
+		"path under a string":          {filter: `name.owner == "x"`, expectedError: true},
+		"path under a list of strings": {filter: `tags.owner == "x"`, expectedError: true},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAS := internal.NewMockAttributeSpecifiable(ctrl)
+			mockAS.
+				EXPECT().
+				SpecificationForAttribute(gomock.Any()).
+				DoAndReturn(func(name string) elemental.AttributeSpecification { return specs[name] }).
+				AnyTimes()
+
+			filter, err := elemental.NewFilterFromString(tc.filter)
+			if err != nil {
+				t.Fatalf("test setup invalid - unable to parse filter: %s", err)
+			}
+
+			err = elemental.ValidateFilter(filter, mockAS)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("error expectation failed: expected an error: %t, actual error: %v", tc.expectedError, err)
+			}
+
+			if err != nil && err.(elemental.Errors).Code() != http.StatusBadRequest {
+				t.Errorf("expected a bad request error, got: %s", err)
+			}
+		})
+	}
+}
+
+func TestValidateFilterForIdentity(t *testing.T) {
+
+	filter := elemental.NewFilterComposer().WithKey("name").Equals(1).Done()
+
+	err := elemental.ValidateFilterForIdentity(filter, testmodel.Manager(), testmodel.ListIdentity)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	if !elemental.IsValidationError(err, "Validation Error", "name") {
+		t.Errorf("expected a validation error on name, got: %s", err)
+	}
+
+	err = elemental.ValidateFilterForIdentity(filter, testmodel.Manager(), elemental.MakeIdentity("nope", "nopes"))
+	if err == nil {
+		t.Fatalf("expected an error for an unknown identity")
+	}
+}
@@ -0,0 +1,158 @@
+package elemental
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Filter returns the objects of the IdentifiablesList matching the given filter,
+// in the same order. The objects must be AttributeSpecifiable. See MatchesFilter.
+func (l IdentifiablesList) Filter(filter *Filter) (IdentifiablesList, error) {
+
+	matching, _, err := l.Partition(filter)
+
+	return matching, err
+}
+
+// Count returns the number of objects of the IdentifiablesList matching the given filter.
+// The objects must be AttributeSpecifiable. See MatchesFilter.
+func (l IdentifiablesList) Count(filter *Filter) (int, error) {
+
+	matching, _, err := l.Partition(filter)
+
+	return len(matching), err
+}
+
+// Partition returns the objects of the IdentifiablesList matching the given filter, and the others,
+// in the same order. The objects must be AttributeSpecifiable. See MatchesFilter.
+func (l IdentifiablesList) Partition(filter *Filter) (matching IdentifiablesList, others IdentifiablesList, err error) {
+
+	if filter == nil {
+		panic(fmt.Errorf("elemental: filter cannot be nil"))
+	}
+
+	matching, others = IdentifiablesList{}, IdentifiablesList{}
+
+	for _, obj := range l {
+
+		as, ok := obj.(AttributeSpecifiable)
+		if !ok {
+			return nil, nil, fmt.Errorf("elemental: unable to match filter: %s is not an attribute specifiable", obj.Identity().Name)
+		}
+
+		matched, err := MatchesFilter(as, filter)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if matched {
+			matching = append(matching, obj)
+		} else {
+			others = append(others, obj)
+		}
+	}
+
+	return matching, others, nil
+}
+
+// Sort sorts the IdentifiablesList in place using the given order, with the semantics of the Order
+// of a Request: the objects are sorted by the value of the first attribute, then by the second one,
+// and so on. An attribute prefixed by - is sorted in descending order. Attribute names are matched
+// case insensitively, and can be dotted paths, as in filters.
+//
+// If no order is given, the DefaultOrder of the objects is used, if they have one. Values are compared as they are
+// by the comparators of filters. Missing values are lesser than any other value, and values that cannot be compared
+// are considered equal. The sort is stable. The objects must be AttributeSpecifiable.
+func (l IdentifiablesList) Sort(order ...string) error {
+
+	if len(l) == 0 {
+		return nil
+	}
+
+	if len(order) == 0 {
+		if o, ok := l[0].(DefaultOrderer); ok {
+			order = o.DefaultOrder()
+		}
+	}
+
+	if len(order) == 0 {
+		return nil
+	}
+
+	// values are only retrieved once per object. They are kept along with their object
+	// as the objects may be duplicated or may not be usable as the keys of a map.
+	type sortedIdentifiable struct {
+		obj    Identifiable
+		values []any
+	}
+
+	sorted := make([]sortedIdentifiable, len(l))
+	for i, obj := range l {
+
+		as, ok := obj.(AttributeSpecifiable)
+		if !ok {
+			return fmt.Errorf("elemental: unable to sort: %s is not an attribute specifiable", obj.Identity().Name)
+		}
+
+		vs := make([]any, len(order))
+		for j, key := range order {
+			vs[j] = valueForOrderKey(as, strings.TrimPrefix(key, "-"))
+		}
+		sorted[i] = sortedIdentifiable{obj: obj, values: vs}
+	}
+
+	slices.SortStableFunc(sorted, func(a, b sortedIdentifiable) int {
+
+		for i, key := range order {
+
+			c := compareOrderValues(a.values[i], b.values[i])
+			if strings.HasPrefix(key, "-") {
+				c = -c
+			}
+
+			if c != 0 {
+				return c
+			}
+		}
+
+		return 0
+	})
+
+	for i, s := range sorted {
+		l[i] = s.obj
+	}
+
+	return nil
+}
+
+// valueForOrderKey returns the value of the given attribute or path, resolving the name
+// of its root attribute case insensitively.
+func valueForOrderKey(as AttributeSpecifiable, key string) any {
+
+	if !isPath(key) {
+		if spec, ok := specificationForKey(as, key); ok {
+			key = spec.Name
+		}
+	}
+
+	return ValueForPath(as, key)
+}
+
+func compareOrderValues(a, b any) int {
+
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	if c, ok := compareValues(a, b); ok {
+		return c
+	}
+
+	return 0
+}
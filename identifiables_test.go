@@ -0,0 +1,214 @@
+package elemental_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.aporeto.io/elemental"
+	testmodel "go.aporeto.io/elemental/test/model"
+)
+
+func makeIdentifiablesList() elemental.IdentifiablesList {
+
+	now := time.Now()
+
+	newList := func(name string, description string, date time.Time) *testmodel.List {
+		l := testmodel.NewList()
+		l.Name = name
+		l.Description = description
+		l.Date = date
+		return l
+	}
+
+	return elemental.IdentifiablesList{
+		newList("c", "groceries", now.Add(-3*time.Hour)),
+		newList("a", "chores", now.Add(-1*time.Hour)),
+		newList("b", "groceries", now.Add(-2*time.Hour)),
+		newList("a", "groceries", now),
+	}
+}
+
+func identifiablesListNames(l elemental.IdentifiablesList) []string {
+
+	names := make([]string, len(l))
+	for i, obj := range l {
+		names[i] = obj.(*testmodel.List).Name + "/" + obj.(*testmodel.List).Description
+	}
+
+	return names
+}
+
+func TestIdentifiablesList_FilterCountPartition(t *testing.T) {
+
+	tests := map[string]struct {
+		filter           string
+		expectedMatching []string
+		expectedOthers   []string
+	}{
+		"some": {
+			filter:           `description == "groceries" and date > now("-150m")`,
+			expectedMatching: []string{"b/groceries", "a/groceries"},
+			expectedOthers:   []string{"c/groceries", "a/chores"},
+		},
+		"all": {
+			filter:           `name in ["a", "b", "c"]`,
+			expectedMatching: []string{"c/groceries", "a/chores", "b/groceries", "a/groceries"},
+			expectedOthers:   []string{},
+		},
+		"none": {
+			filter:           `name == "d"`,
+			expectedMatching: []string{},
+			expectedOthers:   []string{"c/groceries", "a/chores", "b/groceries", "a/groceries"},
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			l := makeIdentifiablesList()
+
+			filter, err := elemental.NewFilterFromString(tc.filter)
+			if err != nil {
+				t.Fatalf("unable to parse filter: %s", err)
+			}
+
+			matching, others, err := l.Partition(filter)
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %s", err)
+			}
+			if names := identifiablesListNames(matching); !reflect.DeepEqual(names, tc.expectedMatching) {
+				t.Errorf("unexpected matching objects\nexpected: %v\nactual:   %v", tc.expectedMatching, names)
+			}
+			if names := identifiablesListNames(others); !reflect.DeepEqual(names, tc.expectedOthers) {
+				t.Errorf("unexpected other objects\nexpected: %v\nactual:   %v", tc.expectedOthers, names)
+			}
+
+			filtered, err := l.Filter(filter)
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %s", err)
+			}
+			if !reflect.DeepEqual(filtered, matching) {
+				t.Errorf("unexpected filtered objects: %v", identifiablesListNames(filtered))
+			}
+
+			count, err := l.Count(filter)
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %s", err)
+			}
+			if count != len(tc.expectedMatching) {
+				t.Errorf("unexpected count: expected %d, got %d", len(tc.expectedMatching), count)
+			}
+		})
+	}
+}
+
+func TestIdentifiablesList_Sort(t *testing.T) {
+
+	tests := map[string]struct {
+		order    []string
+		expected []string
+	}{
+		"single key": {
+			order:    []string{"name"},
+			expected: []string{"a/chores", "a/groceries", "b/groceries", "c/groceries"},
+		},
+		"descending": {
+			order:    []string{"-name"},
+			expected: []string{"c/groceries", "b/groceries", "a/chores", "a/groceries"},
+		},
+		"multiple keys": {
+			order:    []string{"-description", "name"},
+			expected: []string{"a/groceries", "b/groceries", "c/groceries", "a/chores"},
+		},
+		"case insensitive keys": {
+			order:    []string{"Name", "-DATE"},
+			expected: []string{"a/groceries", "a/chores", "b/groceries", "c/groceries"},
+		},
+		"dates": {
+			order:    []string{"date"},
+			expected: []string{"c/groceries", "b/groceries", "a/chores", "a/groceries"},
+		},
+		"unknown key keeps the order": {
+			order:    []string{"unknown"},
+			expected: []string{"c/groceries", "a/chores", "b/groceries", "a/groceries"},
+		},
+		"default order": {
+			order:    nil,
+			expected: []string{"c/groceries", "a/chores", "b/groceries", "a/groceries"},
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			l := makeIdentifiablesList()
+
+			if err := l.Sort(tc.order...); err != nil {
+				t.Fatalf("did not expect to get an error, but received: %s", err)
+			}
+			if names := identifiablesListNames(l); !reflect.DeepEqual(names, tc.expected) {
+				t.Errorf("unexpected order\nexpected: %v\nactual:   %v", tc.expected, names)
+			}
+		})
+	}
+}
+
+// unhashableList is an identifiable that cannot be used as a map key.
+type unhashableList struct {
+	*testmodel.List
+	Tags []string
+}
+
+func TestIdentifiablesList_SortUnhashableAndDuplicates(t *testing.T) {
+
+	newList := func(name string) unhashableList {
+		l := testmodel.NewList()
+		l.Name = name
+		return unhashableList{List: l, Tags: []string{name}}
+	}
+
+	b := newList("b")
+	l := elemental.IdentifiablesList{b, newList("c"), newList("a"), b}
+
+	if err := l.Sort("-name"); err != nil {
+		t.Fatalf("did not expect to get an error, but received: %s", err)
+	}
+
+	names := make([]string, len(l))
+	for i, obj := range l {
+		names[i] = obj.(unhashableList).Name
+	}
+
+	if expected := []string{"c", "b", "b", "a"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("unexpected order\nexpected: %v\nactual:   %v", expected, names)
+	}
+
+	p := testmodel.NewList()
+	p.Name = "b"
+	l = elemental.IdentifiablesList{p, newList("a").List, p}
+
+	if err := l.Sort("name"); err != nil {
+		t.Fatalf("did not expect to get an error, but received: %s", err)
+	}
+
+	if names := identifiablesListNames(l); !reflect.DeepEqual(names, []string{"a/", "b/", "b/"}) {
+		t.Errorf("unexpected order of duplicated objects: %v", names)
+	}
+}
+
+func TestIdentifiablesList_NotAttributeSpecifiable(t *testing.T) {
+
+	l := elemental.IdentifiablesList{testmodel.NewList(), testmodel.NewSparseList()}
+	filter := elemental.NewFilterComposer().WithKey("name").Equals("a").Done()
+
+	if _, err := l.Filter(filter); err == nil {
+		t.Errorf("expected an error when filtering")
+	}
+	if _, _, err := l.Partition(filter); err == nil {
+		t.Errorf("expected an error when partitioning")
+	}
+	if err := l.Sort("name"); err == nil {
+		t.Errorf("expected an error when sorting")
+	}
+}
@@ -1,12 +1,18 @@
 package elemental
 
 import (
+	"cmp"
 	"fmt"
 	"reflect"
 	"regexp"
+	"strings"
+	"time"
 )
 
-// MatchesFilter determines whether an identity matches a filter
+// MatchesFilter determines whether an identity matches a filter.
+//
+// It returns an error if the filter contains placeholders that have not been bound (see Filter.Bind).
+// The OptExplain option can be given to record why the identity matched or not.
 func MatchesFilter(identifiable AttributeSpecifiable, filter *Filter, opts ...MatcherOption) (bool, error) {
 
 	if filter == nil {
@@ -17,79 +23,118 @@ func MatchesFilter(identifiable AttributeSpecifiable, filter *Filter, opts ...Ma
 		panic(fmt.Errorf("elemental: identifiable cannot be nil"))
 	}
 
-	matched := true
-	var err error
+	if err := checkFilterBound(filter); err != nil {
+		return false, &MatcherError{Err: err}
+	}
+
+	var config matchConfig
+	for _, o := range opts {
+		o(&config)
+	}
+
+	if config.explanation != nil {
+		*config.explanation = *explainFilter(identifiable, filter)
+		return config.explanation.Matched, nil
+	}
+
+	return matchesFilter(identifiable, filter)
+}
+
+// matchesFilter is MatchesFilter without the checks of the
+// arguments, which are done once for the whole filter.
+func matchesFilter(identifiable AttributeSpecifiable, filter *Filter) (bool, error) {
 
 	for i, op := range filter.Operators() {
 		switch op {
 		case AndOperator:
-			comparator := filter.Comparators()[i]
-			attributeName := filter.Keys()[i]
-			attributeValue := identifiable.ValueForAttribute(attributeName)
-
-			// important: when adding support for a new comparator, you must only short circuit and return under two conditions:
-			//    - an error occurred by your comparator
-			//    - the comparator failed to find a match
-			//
-			// this is because we are dealing with AND semantics here; success is only possible when all AndOperator's find a match
-
-			switch comparator {
-			case EqualComparator:
-				if !equals(attributeValue, filter.Values()[i][0]) {
-					return false, nil
-				}
-			case NotEqualComparator:
-				if !notEquals(attributeValue, filter.Values()[i][0]) {
-					return false, nil
-				}
-			case ExistsComparator:
-				if !exists(attributeName, identifiable.AttributeSpecifications()) {
-					return false, nil
-				}
-			case NotExistsComparator:
-				if !notExists(attributeName, identifiable.AttributeSpecifications()) {
-					return false, nil
-				}
-			case MatchComparator:
-				if !matches(attributeValue, filter.Values()[i]) {
-					return false, nil
-				}
-			case NotMatchComparator,
-				GreaterComparator,
-				GreaterOrEqualComparator,
-				LesserComparator,
-				LesserOrEqualComparator,
-				InComparator,
-				NotInComparator,
-				ContainComparator,
-				NotContainComparator:
-				return false, &MatcherError{
-					Err: ErrUnsupportedComparator{Err: fmt.Errorf("%q", translateComparator(filter.Comparators()[i]))},
-				}
-			default:
-				panic(fmt.Errorf("elemental: unknown comparator %q", translateComparator(filter.Comparators()[i])))
+			// success is only possible when all AndOperator's find a match
+			if matched, _ := matchesTerm(identifiable, filter.Keys()[i], filter.Comparators()[i], filter.Values()[i]); !matched {
+				return false, nil
 			}
 		case OrFilterOperator:
+			// only one 'or' filter must match for it to be considered a successful match
+			subFilterMatched := true
 			for _, f := range filter.OrFilters()[i] {
-				// only one 'or' filter must match for it to be considered a successful match
-				if matched, err = MatchesFilter(identifiable, f); err != nil || matched {
+				var err error
+				if subFilterMatched, err = matchesFilter(identifiable, f); err != nil {
+					return false, err
+				}
+				if subFilterMatched {
 					break
 				}
 			}
+			if !subFilterMatched {
+				return false, nil
+			}
 		case AndFilterOperator:
-			var subFilterMatched bool
+			// all 'and' filters must match for it to be considered a successful match
 			for _, f := range filter.AndFilters()[i] {
-				// all 'and' filters must match for it to be considered a successful match
-				subFilterMatched, err = MatchesFilter(identifiable, f)
-				matched = matched && subFilterMatched
-				if err != nil || !matched {
+				subFilterMatched, err := matchesFilter(identifiable, f)
+				if err != nil || !subFilterMatched {
+					return false, err
+				}
+			}
+		case NotFilterOperator:
+			// the negated sub filters are considered a successful match if they do not all match
+			subFilterMatched := true
+			for _, f := range filter.NotFilters()[i] {
+				var err error
+				if subFilterMatched, err = matchesFilter(identifiable, f); err != nil {
+					return false, err
+				}
+				if !subFilterMatched {
 					break
 				}
 			}
+			if subFilterMatched {
+				return false, nil
+			}
 		}
 	}
 
-	return matched, err
+	return true, nil
+}
+
+// matchesTerm returns true if the given identifiable matches the given term, along with the value
+// of the attribute that has been compared.
+func matchesTerm(identifiable AttributeSpecifiable, attributeName string, comparator FilterComparator, values FilterValue) (bool, any) {
+
+	attributeValue := ValueForPath(identifiable, attributeName)
+
+	switch comparator {
+	case EqualComparator:
+		return equals(attributeValue, values[0]), attributeValue
+	case NotEqualComparator:
+		return notEquals(attributeValue, values[0]), attributeValue
+	case ExistsComparator:
+		if isPath(attributeName) {
+			// a nested value exists if the path can be resolved
+			return attributeValue != nil, attributeValue
+		}
+		return exists(attributeName, identifiable.AttributeSpecifications()), attributeValue
+	case NotExistsComparator:
+		if isPath(attributeName) {
+			return attributeValue == nil, attributeValue
+		}
+		return notExists(attributeName, identifiable.AttributeSpecifications()), attributeValue
+	case MatchComparator:
+		return matches(attributeValue, values), attributeValue
+	case NotMatchComparator:
+		return !matches(attributeValue, values), attributeValue
+	case EqualIgnoreCaseComparator, StartWithComparator, EndWithComparator:
+		return comparesStrings(attributeValue, values[0], comparator), attributeValue
+	case GreaterComparator,
+		GreaterOrEqualComparator,
+		LesserComparator,
+		LesserOrEqualComparator:
+		return compares(attributeValue, values[0], comparator), attributeValue
+	case InComparator, ContainComparator:
+		return in(attributeValue, values), attributeValue
+	case NotInComparator, NotContainComparator:
+		return !in(attributeValue, values), attributeValue
+	default:
+		panic(fmt.Errorf("elemental: unknown comparator %q", translateComparator(comparator)))
+	}
 }
 
 // matches applies the slice a regular expressions (strings) supplied with the comparator to the attribute - a match is
@@ -155,6 +200,50 @@ func matches(attributeValue any, queries FilterValue) bool {
 	return false
 }
 
+// comparesStrings implements the elemental.EqualIgnoreCaseComparator, elemental.StartWithComparator and
+// elemental.EndWithComparator behaviours. They are the Go equivalent of the following regular expressions,
+// where the value is escaped to be matched literally:
+//
+//	{ field: { $regex: "^value$", $options: "i" } }
+//	{ field: { $regex: "^value" } }
+//	{ field: { $regex: "value$" } }
+//
+// restrictions:
+//   - like for matches, the attribute MUST exist and must be a string or a slice/array of strings, in which case a
+//     match is found if any of its elements satisfies the comparison
+//   - the comparator query value must be a string
+func comparesStrings(field, value any, comparator FilterComparator) bool {
+
+	// if the attribute doesn't exist, no match is possible
+	if field == nil {
+		return false
+	}
+
+	vs, ok := isString(reflect.ValueOf(value))
+	if !ok {
+		return false
+	}
+
+	for _, fs := range toStringSlice(field) {
+		switch comparator {
+		case EqualIgnoreCaseComparator:
+			if strings.EqualFold(fs, vs) {
+				return true
+			}
+		case StartWithComparator:
+			if strings.HasPrefix(fs, vs) {
+				return true
+			}
+		case EndWithComparator:
+			if strings.HasSuffix(fs, vs) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // exists implements the elemental.ExistsComparator behaviour by implementing the Go equivalent of
 // https://docs.mongodb.com/manual/reference/operator/query/exists/ where the value of the boolean is TRUE
 //
@@ -219,6 +308,117 @@ func notEquals(field, value any) bool {
 	return !equalsCommon(field, value)
 }
 
+// in implements the elemental.InComparator and elemental.ContainComparator behaviour by implementing the Go equivalent of
+// https://docs.mongodb.com/manual/reference/operator/query/in
+//
+// { field: { $in: [<value1>, <value2>, ... <valueN> ] } }
+//
+//	Quote from docs:
+//	   If the field holds an array, then the $in operator selects the documents whose field holds an array that
+//	   contains at least one element that matches a value in the specified array
+//
+// The negated comparators (elemental.NotInComparator and elemental.NotContainComparator) are implemented by negating the
+// result of in, which is the equivalent of $nin: documents that do not contain the field are also selected.
+func in(field any, values FilterValue) bool {
+
+	// we are dealing with OR semantics here - we can short-circuit in the moment we find one successful match
+	for _, v := range values {
+		if equals(field, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compares implements the elemental.GreaterComparator, elemental.GreaterOrEqualComparator, elemental.LesserComparator and
+// elemental.LesserOrEqualComparator behaviours by implementing the Go equivalent of
+// https://docs.mongodb.com/manual/reference/operator/query/gt (and its $gte, $lt and $lte siblings)
+//
+// { field: { $gt: value } }
+//
+// If the attribute is an array/slice, a match is found if any of its elements satisfies the comparison. Like MongoDB,
+// values of different types (e.g. a string and a number) are never considered comparable and will never yield a match.
+func compares(field, value any, comparator FilterComparator) bool {
+
+	// if the attribute doesn't exist, no match is possible
+	if field == nil || value == nil {
+		return false
+	}
+
+	fieldV := reflect.ValueOf(field)
+	if isArrayLike(fieldV) {
+		for i := 0; i < fieldV.Len(); i++ {
+			if compares(fieldV.Index(i).Interface(), value, comparator) {
+				return true
+			}
+		}
+		return false
+	}
+
+	result, ok := compareValues(field, value)
+	if !ok {
+		return false
+	}
+
+	switch comparator {
+	case GreaterComparator:
+		return result > 0
+	case GreaterOrEqualComparator:
+		return result >= 0
+	case LesserComparator:
+		return result < 0
+	case LesserOrEqualComparator:
+		return result <= 0
+	default:
+		return false
+	}
+}
+
+// compareValues compares the given field to the given value. It returns -1, 0 or +1 depending on whether the field is
+// lesser, equal or greater than the value. The boolean return value will be false if the two values cannot be compared.
+//
+// Supported types are numbers (which are compared regardless of their actual Go type), strings, booleans and time.Time.
+// As it is done when translating a filter to a database query, a time.Duration or a FilterRelativeTime compared to a
+// time.Time is considered to be relative to the current time.
+func compareValues(field, value any) (int, bool) {
+
+	if ft, ok := field.(time.Time); ok {
+		if v, ok := value.(time.Time); ok {
+			return ft.Compare(v), true
+		}
+		if v, ok := relativeTimeValue(value, time.Now()); ok {
+			return ft.Compare(v), true
+		}
+		return 0, false
+	}
+
+	fieldV, valueV := reflect.ValueOf(field), reflect.ValueOf(value)
+
+	switch {
+	case isInt(fieldV) && isInt(valueV):
+		return cmp.Compare(fieldV.Int(), valueV.Int()), true
+	case isUint(fieldV) && isUint(valueV):
+		return cmp.Compare(fieldV.Uint(), valueV.Uint()), true
+	case isNumber(fieldV) && isNumber(valueV):
+		return cmp.Compare(toFloat(fieldV), toFloat(valueV)), true
+	case fieldV.Kind() == reflect.String && valueV.Kind() == reflect.String:
+		return strings.Compare(fieldV.String(), valueV.String()), true
+	case fieldV.Kind() == reflect.Bool && valueV.Kind() == reflect.Bool:
+		// false is lesser than true
+		switch fb, vb := fieldV.Bool(), valueV.Bool(); {
+		case fb == vb:
+			return 0, true
+		case vb:
+			return -1, true
+		default:
+			return 1, true
+		}
+	}
+
+	return 0, false
+}
+
 func equalsCommon(field, value any) bool {
 
 	// check to see if we are dealing with an attribute that does not exist on the provided identifiable.
@@ -298,6 +498,11 @@ func equalsCommon(field, value any) bool {
 			if reflect.DeepEqual(fieldV.Index(i).Interface(), valueI) {
 				return true
 			}
+			// numbers are equal regardless of their actual Go type, as the parser always returns int64 or float64
+			// values while the elements of the attribute, or the values found by walking an attribute path, may not.
+			if r, ok := compareValues(fieldV.Index(i).Interface(), valueI); ok && r == 0 && isNumber(valueV) {
+				return true
+			}
 		}
 	default:
 		// if our field and value are not arrays/slices, then we just do a recursive equality check using Go's `==` operator via
@@ -334,7 +539,11 @@ func toStringSlice(v any) []string {
 		ss := make([]string, 0, v.Len())
 		// we only add to the slice if the element is a string (or can be converted to one)
 		for i := 0; i < v.Len(); i++ {
-			if s, ok := isString(v.Index(i)); ok {
+			e := v.Index(i)
+			if e.Kind() == reflect.Interface {
+				e = e.Elem()
+			}
+			if s, ok := isString(e); ok {
 				ss = append(ss, s)
 			}
 		}
@@ -352,6 +561,39 @@ func isString(v reflect.Value) (string, bool) {
 	return v.String(), true
 }
 
+func isInt(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isUint(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return false
+	}
+}
+
+func isNumber(v reflect.Value) bool {
+	return isInt(v) || isUint(v) || v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64
+}
+
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case isInt(v):
+		return float64(v.Int())
+	case isUint(v):
+		return float64(v.Uint())
+	default:
+		return v.Float()
+	}
+}
+
 func safeConvert(to reflect.Type, fromV reflect.Value) (value reflect.Value) {
 	if !fromV.Type().ConvertibleTo(to) {
 		return fromV
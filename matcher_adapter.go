@@ -0,0 +1,147 @@
+package elemental
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// NewAttributeSpecifiableAdapter returns an AttributeSpecifiable exposing the given map or struct, so it
+// can be matched using MatchesFilter or CompileFilter like the generated models, with the same comparator
+// semantics. This allows to filter raw event payloads or records without decoding them into a model.
+//
+// The given value must be a map with string keys, like a decoded JSON object, or a struct or a pointer to a struct:
+//   - the attributes of a map are its keys. A key exists, as checked by exists and not exists, if it is in
+//     the map, even if its value is nil
+//   - the attributes of a struct are its exported fields, named after their json tag, or their msgpack tag,
+//     or their Go name. Fields tagged with "-" are ignored, and a field tagged with omitempty does not exist
+//     when it holds its zero value, as it would not be encoded
+//
+// Attribute names are matched exactly first, then case insensitively, and dotted paths are resolved as they
+// are by ValueForPath. The specifications of the attributes only hold their name and are all filterable,
+// so the values compared to the attributes are not type checked. Note that the values of a decoded JSON
+// object keep their JSON types: numbers are float64, which compare to any filter number, but dates are strings.
+func NewAttributeSpecifiableAdapter(value any) (AttributeSpecifiable, error) {
+
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, fmt.Errorf("elemental: unable to adapt value: value cannot be nil")
+		}
+		v = v.Elem()
+	}
+
+	a := &attributeSpecifiableAdapter{
+		values: map[string]any{},
+		specs:  map[string]AttributeSpecification{},
+		lower:  map[string]string{},
+	}
+
+	switch v.Kind() {
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("elemental: unable to adapt value: map keys must be strings, got %s", v.Type().Key())
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			a.add(iter.Key().String(), iter.Value().Interface())
+		}
+
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name, omitempty, ok := structFieldName(t.Field(i))
+			if !ok || (omitempty && v.Field(i).IsZero()) {
+				continue
+			}
+			a.add(name, v.Field(i).Interface())
+		}
+
+	default:
+		return nil, fmt.Errorf("elemental: unable to adapt value: expected a map or a struct, got %T", value)
+	}
+
+	return a, nil
+}
+
+type attributeSpecifiableAdapter struct {
+	values map[string]any
+	specs  map[string]AttributeSpecification
+	lower  map[string]string
+}
+
+func (a *attributeSpecifiableAdapter) add(name string, value any) {
+
+	a.values[name] = value
+	a.specs[name] = AttributeSpecification{Name: name, Filterable: true}
+
+	// when several attributes only differ by their case, the one
+	// used by case insensitive lookups is the first one added.
+	if _, ok := a.lower[strings.ToLower(name)]; !ok {
+		a.lower[strings.ToLower(name)] = name
+	}
+}
+
+func (a *attributeSpecifiableAdapter) name(name string) (string, bool) {
+
+	if _, ok := a.specs[name]; ok {
+		return name, true
+	}
+
+	name, ok := a.lower[strings.ToLower(name)]
+	return name, ok
+}
+
+func (a *attributeSpecifiableAdapter) SpecificationForAttribute(name string) AttributeSpecification {
+
+	if name, ok := a.name(name); ok {
+		return a.specs[name]
+	}
+
+	return AttributeSpecification{}
+}
+
+func (a *attributeSpecifiableAdapter) AttributeSpecifications() map[string]AttributeSpecification {
+	return a.specs
+}
+
+func (a *attributeSpecifiableAdapter) ValueForAttribute(name string) any {
+
+	if name, ok := a.name(name); ok {
+		return a.values[name]
+	}
+
+	return nil
+}
+
+// structFieldName returns the name of the given struct field, using its json or msgpack tag if any, and
+// whether it is omitted when empty. The returned boolean is false if the field is not encoded.
+func structFieldName(f reflect.StructField) (string, bool, bool) {
+
+	if !f.IsExported() {
+		return "", false, false
+	}
+
+	for _, key := range []string{"json", "msgpack"} {
+
+		tag, ok := f.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" && opts == "" {
+			return "", false, false
+		}
+
+		omitempty := strings.Contains(","+opts+",", ",omitempty,")
+		if name != "" {
+			return name, omitempty, true
+		}
+
+		return f.Name, omitempty, true
+	}
+
+	return f.Name, false, true
+}
@@ -0,0 +1,167 @@
+package elemental_test
+
+import (
+	"testing"
+	"time"
+
+	"go.aporeto.io/elemental"
+	testmodel "go.aporeto.io/elemental/test/model"
+)
+
+type auditRecord struct {
+	Action    string         `json:"action"`
+	Actor     string         `msgpack:"actor"`
+	Target    *auditTarget   `json:"target,omitempty"`
+	Tags      []string       `json:"tags,omitempty"`
+	Details   map[string]any `json:"details"`
+	Timestamp time.Time
+	Ignored   string `json:"-"`
+	internal  string
+}
+
+type auditTarget struct {
+	Kind      string `json:"kind"`
+	Namespace string
+}
+
+func TestMatchesFilter_Adapter(t *testing.T) {
+
+	record := &auditRecord{
+		Action:    "delete",
+		Actor:     "alice",
+		Target:    &auditTarget{Kind: "list", Namespace: "/a/b"},
+		Details:   map[string]any{"reason": "cleanup", "count": 3},
+		Timestamp: time.Now().Add(-time.Hour),
+		Ignored:   "ignored",
+		internal:  "internal",
+	}
+
+	payload := map[string]any{
+		"name":      "groceries",
+		"namespace": "/a/b",
+		"count":     float64(3),
+		"archived":  nil,
+		"tags":      []any{"food", "weekly"},
+		"owner": map[string]any{
+			"name":   "alice",
+			"groups": []any{map[string]any{"name": "admins"}, map[string]any{"name": "users"}},
+		},
+	}
+
+	tests := map[string]struct {
+		value         any
+		filter        string
+		expectedMatch bool
+	}{
+		"map equal":                         {value: payload, filter: `name == "groceries" and count == 3`, expectedMatch: true},
+		"map case insensitive key":          {value: payload, filter: `Name == "groceries"`, expectedMatch: true},
+		"map not equal":                     {value: payload, filter: `namespace != "/a/b"`, expectedMatch: false},
+		"map ordering":                      {value: payload, filter: `count > 2 and count <= 3`, expectedMatch: true},
+		"map string comparators":            {value: payload, filter: `namespace startswith "/a" and name =~ "GROCERIES"`, expectedMatch: true},
+		"map list":                          {value: payload, filter: `tags contains ["weekly"] and tags not contains ["daily"]`, expectedMatch: true},
+		"map exists with a nil value":       {value: payload, filter: `archived exists`, expectedMatch: true},
+		"map not exists":                    {value: payload, filter: `deleted not exists and name exists`, expectedMatch: true},
+		"map nested path":                   {value: payload, filter: `owner.name == "alice" and owner.groups.name == "admins"`, expectedMatch: true},
+		"map sub filters":                   {value: payload, filter: `name == "other" or (tags matches "^we" and not (count == 4))`, expectedMatch: true},
+		"struct json tag":                   {value: record, filter: `action == "delete"`, expectedMatch: true},
+		"struct msgpack tag":                {value: record, filter: `actor == "alice"`, expectedMatch: true},
+		"struct field name":                 {value: record, filter: `timestamp > now() - 1d`, expectedMatch: true},
+		"struct nested path":                {value: record, filter: `target.kind == "list" and target.namespace == "/a/b"`, expectedMatch: true},
+		"struct nested map":                 {value: record, filter: `details.reason == "cleanup" and details.count >= 3`, expectedMatch: true},
+		"struct omitted empty field":        {value: record, filter: `tags not exists and target exists`, expectedMatch: true},
+		"struct ignored field":              {value: record, filter: `Ignored exists`, expectedMatch: false},
+		"struct unexported field":           {value: record, filter: `internal exists`, expectedMatch: false},
+		"struct without pointer":            {value: *record, filter: `action in ["create", "delete"]`, expectedMatch: true},
+		"generated model struct tags":       {value: testmodel.List{Name: "groceries"}, filter: `name == "groceries"`, expectedMatch: true},
+		"generated model not encoded field": {value: testmodel.List{Unexposed: "x"}, filter: `unexposed exists`, expectedMatch: false},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			filter, err := elemental.NewFilterFromString(tc.filter)
+			if err != nil {
+				t.Fatalf("unable to parse filter: %s", err)
+			}
+
+			as, err := elemental.NewAttributeSpecifiableAdapter(tc.value)
+			if err != nil {
+				t.Fatalf("unable to adapt value: %s", err)
+			}
+
+			matched, err := elemental.MatchesFilter(as, filter)
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %s", err)
+			}
+			if matched != tc.expectedMatch {
+				t.Errorf("match expectation failed: expected a match: %t, matched occurred: %t", tc.expectedMatch, matched)
+			}
+
+			if cf, err := elemental.CompileFilter(filter, as); err == nil {
+				if matched := cf.Match(as); matched != tc.expectedMatch {
+					t.Errorf("compiled match expectation failed: expected a match: %t, matched occurred: %t", tc.expectedMatch, matched)
+				}
+			}
+		})
+	}
+}
+
+func TestNewAttributeSpecifiableAdapter_Errors(t *testing.T) {
+
+	tests := map[string]any{
+		"nil":             nil,
+		"nil pointer":     (*auditRecord)(nil),
+		"scalar":          42,
+		"slice":           []any{map[string]any{}},
+		"non string keys": map[int]any{1: "a"},
+	}
+
+	for description, value := range tests {
+		t.Run(description, func(t *testing.T) {
+			if _, err := elemental.NewAttributeSpecifiableAdapter(value); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestEvent_MatchesFilter(t *testing.T) {
+
+	list := testmodel.NewList()
+	list.Name = "groceries"
+	list.Slice = []string{"milk", "eggs"}
+
+	filter, err := elemental.NewFilterFromString(`name == "groceries" and slice contains "eggs"`)
+	if err != nil {
+		t.Fatalf("unable to parse filter: %s", err)
+	}
+
+	for _, encoding := range []elemental.EncodingType{elemental.EncodingTypeJSON, elemental.EncodingTypeMSGPACK} {
+		t.Run(string(encoding), func(t *testing.T) {
+
+			event := elemental.NewEventWithEncoding(elemental.EventCreate, list, encoding)
+
+			matched, err := event.MatchesFilter(filter)
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %s", err)
+			}
+			if !matched {
+				t.Errorf("expected the event to match")
+			}
+
+			matched, err = event.MatchesFilter(elemental.NewFilterComposer().WithKey("name").Equals("other").Done())
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %s", err)
+			}
+			if matched {
+				t.Errorf("did not expect the event to match")
+			}
+		})
+	}
+
+	event := elemental.NewEvent(elemental.EventCreate, list)
+	event.RawData = []byte("not msgpack")
+	if _, err := event.MatchesFilter(filter); err == nil {
+		t.Errorf("expected an error when the entity cannot be decoded")
+	}
+}
@@ -0,0 +1,431 @@
+package elemental
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// A CompiledFilter is a pre-validated version of a Filter that can be used to match many objects of the same identity
+// without having to walk, convert and compile the filter again for each of them.
+//
+// A CompiledFilter is created using CompileFilter. It is safe for concurrent use.
+type CompiledFilter struct {
+	filter *Filter
+	terms  []compiledTerm
+}
+
+// compiledTerm is the compiled version of one operator of a Filter.
+type compiledTerm struct {
+	operator   FilterOperator
+	comparator FilterComparator
+	key        string
+	path       []string
+	values     FilterValue
+	regexes    []*regexp.Regexp
+	subFilters []*CompiledFilter
+}
+
+// CompileFilter compiles the given filter against the given prototype. The prototype is only used to retrieve the
+// attribute specifications, and the resulting CompiledFilter can be used with any object of the same kind.
+//
+// During compilation:
+//   - keys are resolved against the prototype's AttributeSpecifications (case insensitively) and an error is returned
+//     if an attribute does not exist, except for exists and not exists, which are checked against the matched object
+//     as MatchesFilter does
+//   - values are checked against the type of the attribute and converted to the Go type used by the models
+//   - regular expressions are compiled once and an error is returned if one of them is invalid
+//   - values given to =~, startswith and endswith must be strings
+//
+// Keys that are dotted paths (see ValueForPath) are resolved using their root attribute, and the values
+// compared to nested values are kept as is, as they are not described by the specifications.
+func CompileFilter(filter *Filter, prototype AttributeSpecifiable) (*CompiledFilter, error) {
+
+	if filter == nil {
+		panic(fmt.Errorf("elemental: filter cannot be nil"))
+	}
+
+	if prototype == nil {
+		panic(fmt.Errorf("elemental: prototype cannot be nil"))
+	}
+
+	if err := checkFilterBound(filter); err != nil {
+		return nil, fmt.Errorf("elemental: unable to compile filter: %w", err)
+	}
+
+	cf := &CompiledFilter{
+		filter: filter,
+		terms:  make([]compiledTerm, 0, len(filter.operators)),
+	}
+
+	for i, op := range filter.operators {
+
+		term := compiledTerm{operator: op}
+
+		switch op {
+
+		case AndOperator:
+			if !isPath(filter.keys[i]) && (filter.comparators[i] == ExistsComparator || filter.comparators[i] == NotExistsComparator) {
+				// as in MatchesFilter, the existence is checked against the
+				// specifications of the matched object using the exact key.
+				term.key = filter.keys[i]
+				term.comparator = filter.comparators[i]
+				break
+			}
+
+			spec, ok := specificationForKey(prototype, pathRoot(filter.keys[i]))
+			if !ok {
+				return nil, fmt.Errorf("elemental: unable to compile filter: unknown attribute %q", filter.keys[i])
+			}
+
+			if isPath(filter.keys[i]) {
+				term.path = strings.Split(filter.keys[i], ".")[1:]
+				spec = AttributeSpecification{Name: spec.Name}
+			}
+
+			values, regexes, err := compileValues(spec, filter.comparators[i], filter.values[i])
+			if err != nil {
+				return nil, fmt.Errorf("elemental: unable to compile filter: invalid value for attribute %q: %w", filter.keys[i], err)
+			}
+
+			term.key = spec.Name
+			term.comparator = filter.comparators[i]
+			term.values = values
+			term.regexes = regexes
+
+		case AndFilterOperator, OrFilterOperator, NotFilterOperator:
+			subs := filter.ands[i]
+			switch op {
+			case OrFilterOperator:
+				subs = filter.ors[i]
+			case NotFilterOperator:
+				subs = filter.nots[i]
+			}
+
+			term.subFilters = make([]*CompiledFilter, len(subs))
+			for j, sub := range subs {
+				csub, err := CompileFilter(sub, prototype)
+				if err != nil {
+					return nil, err
+				}
+				term.subFilters[j] = csub
+			}
+		}
+
+		cf.terms = append(cf.terms, term)
+	}
+
+	return cf, nil
+}
+
+// Filter returns the Filter the CompiledFilter was compiled from.
+func (cf *CompiledFilter) Filter() *Filter {
+	return cf.filter
+}
+
+// Match returns true if the given object matches the CompiledFilter. It has the same semantics as MatchesFilter.
+func (cf *CompiledFilter) Match(obj AttributeSpecifiable) bool {
+
+	if obj == nil {
+		panic(fmt.Errorf("elemental: object cannot be nil"))
+	}
+
+	for _, term := range cf.terms {
+		if !term.match(obj) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Explain returns the explanation of why the given object matches the CompiledFilter or not.
+// The filter is evaluated as it is by MatchesFilter with the OptExplain option, so this is slower
+// than Match and should only be used to investigate unexpected results. The error returned by
+// MatchesFilter, if any, is returned.
+func (cf *CompiledFilter) Explain(obj AttributeSpecifiable) (*MatchExplanation, error) {
+
+	e := &MatchExplanation{}
+	if _, err := MatchesFilter(obj, cf.filter, OptExplain(e)); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (t *compiledTerm) match(obj AttributeSpecifiable) bool {
+
+	switch t.operator {
+
+	case AndFilterOperator:
+		for _, sub := range t.subFilters {
+			if !sub.Match(obj) {
+				return false
+			}
+		}
+		return true
+
+	case OrFilterOperator:
+		for _, sub := range t.subFilters {
+			if sub.Match(obj) {
+				return true
+			}
+		}
+		return false
+
+	case NotFilterOperator:
+		for _, sub := range t.subFilters {
+			if !sub.Match(obj) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if t.path != nil {
+		return t.matchPath(obj)
+	}
+
+	switch t.comparator {
+	case ExistsComparator:
+		return exists(t.key, obj.AttributeSpecifications())
+	case NotExistsComparator:
+		return notExists(t.key, obj.AttributeSpecifications())
+	}
+
+	return t.matchValue(obj.ValueForAttribute(t.key))
+}
+
+// matchPath matches the value found at the path of the term. Nested values exist if the path can be resolved.
+func (t *compiledTerm) matchPath(obj AttributeSpecifiable) bool {
+
+	field := walkPath(obj.ValueForAttribute(t.key), t.path)
+
+	switch t.comparator {
+	case ExistsComparator:
+		return field != nil
+	case NotExistsComparator:
+		return field == nil
+	}
+
+	return t.matchValue(field)
+}
+
+func (t *compiledTerm) matchValue(field any) bool {
+
+	switch t.comparator {
+	case EqualComparator:
+		if ok, handled := fastEquals(field, t.values[0]); handled {
+			return ok
+		}
+		return equals(field, t.values[0])
+	case NotEqualComparator:
+		if ok, handled := fastEquals(field, t.values[0]); handled {
+			return !ok
+		}
+		return notEquals(field, t.values[0])
+	case GreaterComparator, GreaterOrEqualComparator, LesserComparator, LesserOrEqualComparator:
+		return compares(field, t.values[0], t.comparator)
+	case InComparator, ContainComparator:
+		return t.in(field)
+	case NotInComparator, NotContainComparator:
+		return !t.in(field)
+	case MatchComparator:
+		return matchesCompiled(field, t.regexes)
+	case NotMatchComparator:
+		return !matchesCompiled(field, t.regexes)
+	case EqualIgnoreCaseComparator, StartWithComparator, EndWithComparator:
+		return comparesStrings(field, t.values[0], t.comparator)
+	default:
+		panic(fmt.Errorf("elemental: unknown comparator %q", translateComparator(t.comparator)))
+	}
+}
+
+func (t *compiledTerm) in(field any) bool {
+
+	for _, v := range t.values {
+		if ok, handled := fastEquals(field, v); handled {
+			if ok {
+				return true
+			}
+			continue
+		}
+		if equals(field, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fastEquals compares the most common attribute types without using reflection. The second returned
+// value is false if the types are not handled, in which case the caller must fallback on equals.
+func fastEquals(field, value any) (bool, bool) {
+
+	switch f := field.(type) {
+	case string:
+		if v, ok := value.(string); ok {
+			return f == v, true
+		}
+	case bool:
+		if v, ok := value.(bool); ok {
+			return f == v, true
+		}
+	case int:
+		if v, ok := value.(int); ok {
+			return f == v, true
+		}
+	case float64:
+		if v, ok := value.(float64); ok {
+			return f == v, true
+		}
+	}
+
+	return false, false
+}
+
+func matchesCompiled(field any, regexes []*regexp.Regexp) bool {
+
+	if field == nil {
+		return false
+	}
+
+	if s, ok := field.(string); ok {
+		for _, r := range regexes {
+			if r.MatchString(s) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, s := range toStringSlice(field) {
+		for _, r := range regexes {
+			if r.MatchString(s) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// compileValues checks the given values against the given attribute specification and converts
+// them to the Go type used by the generated models.
+func compileValues(spec AttributeSpecification, comparator FilterComparator, values FilterValue) (FilterValue, []*regexp.Regexp, error) {
+
+	switch comparator {
+
+	case ExistsComparator, NotExistsComparator:
+		return values, nil, nil
+
+	case MatchComparator, NotMatchComparator:
+		regexes := make([]*regexp.Regexp, len(values))
+		for i, v := range values {
+			s, ok := v.(string)
+			if !ok {
+				return nil, nil, fmt.Errorf("regular expression must be a string, got %T", v)
+			}
+			r, err := regexp.Compile(s)
+			if err != nil {
+				return nil, nil, err
+			}
+			regexes[i] = r
+		}
+		return values, regexes, nil
+
+	case EqualIgnoreCaseComparator, StartWithComparator, EndWithComparator:
+		for _, v := range values {
+			if _, ok := v.(string); !ok {
+				return nil, nil, fmt.Errorf("expected a string, got %T", v)
+			}
+		}
+		return values, nil, nil
+	}
+
+	out := make(FilterValue, len(values))
+	for i, v := range values {
+		cv, err := convertValueForType(spec.Type, v)
+		if err != nil {
+			return nil, nil, err
+		}
+		out[i] = cv
+	}
+
+	return out, nil, nil
+}
+
+// convertValueForType converts the given filter value to the Go type that elegen uses for the given
+// specification type. Types that cannot be checked are returned as is.
+func convertValueForType(typ string, value any) (any, error) {
+
+	if value == nil {
+		return nil, nil
+	}
+
+	v := reflect.ValueOf(value)
+
+	switch typ {
+
+	case "string", "enum":
+		if v.Kind() != reflect.String {
+			return nil, fmt.Errorf("expected a string, got %T", value)
+		}
+		return v.String(), nil
+
+	case "integer":
+		if !isNumber(v) {
+			return nil, fmt.Errorf("expected an integer, got %T", value)
+		}
+		switch {
+		case isInt(v):
+			return int(v.Int()), nil
+		case isUint(v):
+			return int(v.Uint()), nil
+		case v.Float() != float64(int(v.Float())):
+			return nil, fmt.Errorf("expected an integer, got %v", value)
+		default:
+			return int(v.Float()), nil
+		}
+
+	case "float":
+		if !isNumber(v) {
+			return nil, fmt.Errorf("expected a float, got %T", value)
+		}
+		return toFloat(v), nil
+
+	case "boolean":
+		if v.Kind() != reflect.Bool {
+			return nil, fmt.Errorf("expected a boolean, got %T", value)
+		}
+		return v.Bool(), nil
+
+	case "time":
+		switch value.(type) {
+		case time.Time, time.Duration, FilterRelativeTime:
+			return value, nil
+		default:
+			return nil, fmt.Errorf("expected a date or a duration, got %T", value)
+		}
+	}
+
+	return value, nil
+}
+
+// specificationForKey returns the AttributeSpecification for the given filter key. Generated
+// models index their specifications by converted name and by lower case name, so the key is
+// looked up as is first and then lower cased.
+func specificationForKey(as AttributeSpecifiable, key string) (AttributeSpecification, bool) {
+
+	if spec := as.SpecificationForAttribute(key); spec.Name != "" {
+		return spec, true
+	}
+
+	if spec := as.SpecificationForAttribute(strings.ToLower(key)); spec.Name != "" {
+		return spec, true
+	}
+
+	return AttributeSpecification{}, false
+}
@@ -0,0 +1,403 @@
+package elemental_test
+
+import (
+	"testing"
+	"time"
+
+	"go.aporeto.io/elemental"
+	testmodel "go.aporeto.io/elemental/test/model"
+)
+
+func TestCompileFilter(t *testing.T) {
+
+	tests := map[string]struct {
+		filter        string
+		prototype     elemental.AttributeSpecifiable
+		expectedError bool
+	}{
+		"should compile a filter using known attributes": {
+			filter:    `firstName == "Alice" and (archived == true or lastName matches "^B")`,
+			prototype: testmodel.NewUser(),
+		},
+		"should compile a filter using lower case attribute names": {
+			filter:    `firstname == "Alice"`,
+			prototype: testmodel.NewUser(),
+		},
+		"should compile a filter using a date or a duration on a time attribute": {
+			filter:    `date > date("2020-01-01") and date < now("-1h")`,
+			prototype: testmodel.NewList(),
+		},
+		"should return an error if an attribute does not exist": {
+			filter:        `firstName == "Alice" and unknown == 1`,
+			prototype:     testmodel.NewUser(),
+			expectedError: true,
+		},
+		"should return an error if an attribute does not exist in a sub filter": {
+			filter:        `firstName == "Alice" and (archived == true or unknown == 1)`,
+			prototype:     testmodel.NewUser(),
+			expectedError: true,
+		},
+		"should return an error if a value has the wrong type": {
+			filter:        `firstName == 42`,
+			prototype:     testmodel.NewUser(),
+			expectedError: true,
+		},
+		"should return an error if a boolean is compared to a string": {
+			filter:        `archived in [true, "false"]`,
+			prototype:     testmodel.NewUser(),
+			expectedError: true,
+		},
+		"should return an error if a time attribute is compared to a string": {
+			filter:        `date > "yesterday"`,
+			prototype:     testmodel.NewList(),
+			expectedError: true,
+		},
+		"should return an error if a regular expression is invalid": {
+			filter:        `firstName matches "(("`,
+			prototype:     testmodel.NewUser(),
+			expectedError: true,
+		},
+		"should return an error if a prefix is not a string": {
+			filter:        `firstName startswith 42`,
+			prototype:     testmodel.NewUser(),
+			expectedError: true,
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			filter, err := elemental.NewFilterFromString(tc.filter)
+			if err != nil {
+				t.Fatalf("test setup invalid - unable to parse filter: %s", err)
+			}
+
+			cf, err := elemental.CompileFilter(filter, tc.prototype)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("error expectation failed: expected an error: %t, actual error: %v", tc.expectedError, err)
+			}
+
+			if err == nil && cf.Filter() != filter {
+				t.Errorf("expected the compiled filter to reference the original filter")
+			}
+		})
+	}
+}
+
+func TestCompiledFilter_Match(t *testing.T) {
+
+	user := testmodel.NewUser()
+	user.FirstName = "Alice"
+	user.LastName = "Bob"
+	user.UserName = "alice"
+	user.Archived = true
+
+	list := testmodel.NewList()
+	list.Name = "groceries"
+	list.Date = time.Now().Add(-30 * time.Minute)
+	list.Slice = []string{"milk", "eggs"}
+
+	tests := map[string]struct {
+		filter        string
+		obj           elemental.AttributeSpecifiable
+		expectedMatch bool
+	}{
+		"equal": {
+			filter:        `firstName == "Alice"`,
+			obj:           user,
+			expectedMatch: true,
+		},
+		"lower case key": {
+			filter:        `firstname == "Alice"`,
+			obj:           user,
+			expectedMatch: true,
+		},
+		"not equal": {
+			filter:        `firstName != "Alice"`,
+			obj:           user,
+			expectedMatch: false,
+		},
+		"boolean": {
+			filter:        `archived == true`,
+			obj:           user,
+			expectedMatch: true,
+		},
+		"equal ignoring case": {
+			filter:        `firstName =~ "ALICE" and userName =~ "Alice"`,
+			obj:           user,
+			expectedMatch: true,
+		},
+		"starts with and ends with": {
+			filter:        `name startswith "groc" and slice endswith "gs"`,
+			obj:           list,
+			expectedMatch: true,
+		},
+		"starts with in a different case": {
+			filter:        `name startswith "Groc"`,
+			obj:           list,
+			expectedMatch: false,
+		},
+		"in": {
+			filter:        `userName in ["bob", "alice"]`,
+			obj:           user,
+			expectedMatch: true,
+		},
+		"not in": {
+			filter:        `userName not in ["bob", "alice"]`,
+			obj:           user,
+			expectedMatch: false,
+		},
+		"contains on a list": {
+			filter:        `slice contains ["eggs"]`,
+			obj:           list,
+			expectedMatch: true,
+		},
+		"not contains on a list": {
+			filter:        `slice not contains ["bread"]`,
+			obj:           list,
+			expectedMatch: true,
+		},
+		"matches": {
+			filter:        `lastName matches ["^X", "^B"]`,
+			obj:           user,
+			expectedMatch: true,
+		},
+		"matches on a list": {
+			filter:        `slice matches "^mi"`,
+			obj:           list,
+			expectedMatch: true,
+		},
+		"greater than a relative duration": {
+			filter:        `date > now("-1h")`,
+			obj:           list,
+			expectedMatch: true,
+		},
+		"lesser than a relative duration": {
+			filter:        `date < now("-1h")`,
+			obj:           list,
+			expectedMatch: false,
+		},
+		"and": {
+			filter:        `firstName == "Alice" and lastName == "Bob"`,
+			obj:           user,
+			expectedMatch: true,
+		},
+		"and with a failing term": {
+			filter:        `firstName == "Alice" and lastName == "Charlie"`,
+			obj:           user,
+			expectedMatch: false,
+		},
+		"or": {
+			filter:        `firstName == "Charlie" or lastName == "Bob"`,
+			obj:           user,
+			expectedMatch: true,
+		},
+		"nested": {
+			filter:        `archived == true and (firstName == "Charlie" or (lastName == "Bob" and userName == "alice"))`,
+			obj:           user,
+			expectedMatch: true,
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			filter, err := elemental.NewFilterFromString(tc.filter)
+			if err != nil {
+				t.Fatalf("test setup invalid - unable to parse filter: %s", err)
+			}
+
+			cf, err := elemental.CompileFilter(filter, tc.obj)
+			if err != nil {
+				t.Fatalf("test setup invalid - unable to compile filter: %s", err)
+			}
+
+			if matched := cf.Match(tc.obj); matched != tc.expectedMatch {
+				t.Errorf("match expectation failed: expected a match: %t, matched occurred: %t", tc.expectedMatch, matched)
+			}
+		})
+	}
+}
+
+func TestCompiledFilter_MatchExists(t *testing.T) {
+
+	user := testmodel.NewUser()
+	user.FirstName = "Alice"
+
+	// the specifications of the models are keyed by the converted names.
+	tests := map[string]struct {
+		filter        string
+		expectedMatch bool
+	}{
+		"exists": {
+			filter:        `FirstName exists`,
+			expectedMatch: true,
+		},
+		"not exists": {
+			filter:        `FirstName not exists`,
+			expectedMatch: false,
+		},
+		"exists with a key that is not the exact specification key": {
+			filter:        `firstName exists`,
+			expectedMatch: false,
+		},
+		"exists on an unknown attribute": {
+			filter:        `unknown exists`,
+			expectedMatch: false,
+		},
+		"not exists on an unknown attribute": {
+			filter:        `unknown not exists`,
+			expectedMatch: true,
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			filter, err := elemental.NewFilterFromString(tc.filter)
+			if err != nil {
+				t.Fatalf("test setup invalid - unable to parse filter: %s", err)
+			}
+
+			cf, err := elemental.CompileFilter(filter, user)
+			if err != nil {
+				t.Fatalf("test setup invalid - unable to compile filter: %s", err)
+			}
+
+			if matched := cf.Match(user); matched != tc.expectedMatch {
+				t.Errorf("match expectation failed: expected a match: %t, matched occurred: %t", tc.expectedMatch, matched)
+			}
+
+			if matched, err := elemental.MatchesFilter(user, filter); err != nil || matched != tc.expectedMatch {
+				t.Errorf("expected MatchesFilter to agree: matched: %t, error: %v", matched, err)
+			}
+		})
+	}
+}
+
+func TestPushConfig_CompileIdentityFilters(t *testing.T) {
+
+	t.Run("should compile the identity filters using the manager", func(t *testing.T) {
+
+		pc := elemental.NewPushConfig()
+		pc.FilterIdentity("user")
+		pc.IdentityFilters["user"] = `firstName == "Alice"`
+
+		if err := pc.CompileIdentityFilters(testmodel.Manager()); err != nil {
+			t.Fatalf("did not expect to get an error, but received: %s", err)
+		}
+
+		cf, found := pc.CompiledFilterForIdentity("user")
+		if !found {
+			t.Fatalf("expected to find a compiled filter for identity user")
+		}
+
+		user := testmodel.NewUser()
+		user.FirstName = "Alice"
+		if !cf.Match(user) {
+			t.Errorf("expected the compiled filter to match")
+		}
+
+		if _, found := pc.Duplicate().CompiledFilterForIdentity("user"); !found {
+			t.Errorf("expected the duplicated push config to contain the compiled filter")
+		}
+
+		if _, found := pc.CompiledFilterForIdentity("list"); found {
+			t.Errorf("did not expect to find a compiled filter for identity list")
+		}
+	})
+
+	t.Run("should compile the identity filters again after they changed", func(t *testing.T) {
+
+		pc := elemental.NewPushConfig()
+		pc.FilterIdentity("user")
+		pc.IdentityFilters["user"] = `firstName == "Alice"`
+
+		if err := pc.CompileIdentityFilters(testmodel.Manager()); err != nil {
+			t.Fatalf("did not expect to get an error, but received: %s", err)
+		}
+
+		pc.IdentityFilters["user"] = `firstName == "Bob"`
+
+		if err := pc.ParseIdentityFilters(); err != nil {
+			t.Fatalf("did not expect to get an error, but received: %s", err)
+		}
+
+		if _, found := pc.CompiledFilterForIdentity("user"); found {
+			t.Errorf("did not expect to find a stale compiled filter after parsing the filters again")
+		}
+
+		if err := pc.CompileIdentityFilters(testmodel.Manager()); err != nil {
+			t.Fatalf("did not expect to get an error, but received: %s", err)
+		}
+
+		cf, found := pc.CompiledFilterForIdentity("user")
+		if !found {
+			t.Fatalf("expected to find a compiled filter for identity user")
+		}
+
+		user := testmodel.NewUser()
+		user.FirstName = "Bob"
+		if !cf.Match(user) {
+			t.Errorf("expected the compiled filter to use the new identity filter")
+		}
+	})
+
+	t.Run("should return an error if the identity is unknown to the manager", func(t *testing.T) {
+
+		pc := elemental.NewPushConfig()
+		pc.FilterIdentity("unknown")
+		pc.IdentityFilters["unknown"] = `firstName == "Alice"`
+
+		if err := pc.CompileIdentityFilters(testmodel.Manager()); err == nil {
+			t.Fatalf("expected an error")
+		}
+	})
+
+	t.Run("should return an error if a filter cannot be compiled", func(t *testing.T) {
+
+		pc := elemental.NewPushConfig()
+		pc.FilterIdentity("user")
+		pc.IdentityFilters["user"] = `unknown == "Alice"`
+
+		if err := pc.CompileIdentityFilters(testmodel.Manager()); err == nil {
+			t.Fatalf("expected an error")
+		}
+
+		if _, found := pc.CompiledFilterForIdentity("user"); found {
+			t.Errorf("did not expect to find a compiled filter for identity user")
+		}
+	})
+}
+
+// this is to avoid compiler optimization which may ruin the benchmark
+var compiledMatchResult bool
+
+func BenchmarkCompiledFilterMatch(b *testing.B) {
+	b.ReportAllocs()
+
+	user := testmodel.NewUser()
+	user.FirstName = "Alice"
+	user.LastName = "Bob"
+	user.UserName = "alice"
+
+	filter, err := elemental.NewFilterFromString(`(firstName == "Charlie" or lastName matches "^B") and userName in ["bob", "alice"]`)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	cf, err := elemental.CompileFilter(filter, user)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var matched bool
+	for n := 0; n < b.N; n++ {
+		matched = cf.Match(user)
+		if !matched {
+			b.Error("benchmark test case should have resulted in a match")
+		}
+	}
+
+	compiledMatchResult = matched
+}
@@ -0,0 +1,120 @@
+package elemental
+
+import (
+	"strings"
+)
+
+// A MatchExplanation explains why an identity matched a filter or not. It is recorded by
+// MatchesFilter when given the OptExplain option.
+//
+// An explanation is a tree. Its root explains the whole filter, as an AndFilterOperator node
+// whose children explain each term and sub filters operator of the filter:
+//   - a term is explained by an AndOperator node holding the Term, and the Value of the
+//     attribute found in the identity, which is nil if the attribute does not exist
+//   - the and, or and not operators are explained by an AndFilterOperator, OrFilterOperator
+//     or NotFilterOperator node, whose children explain each sub filter, in the same way
+//     the root explains the whole filter
+type MatchExplanation struct {
+	Operator FilterOperator
+	Term     *FilterTerm
+	Value    any
+	Matched  bool
+	Children []*MatchExplanation
+}
+
+// String returns a readable representation of the explanation, with one line per node.
+func (e *MatchExplanation) String() string {
+
+	var b strings.Builder
+	e.write(&b, 0)
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (e *MatchExplanation) write(b *strings.Builder, depth int) {
+
+	b.WriteString(strings.Repeat("  ", depth))
+
+	switch e.Operator {
+	case AndOperator:
+		f := NewFilter()
+		f.addTerm(e.Term.Key, e.Term.Comparator, e.Term.Values)
+		b.WriteString(f.String())
+	case AndFilterOperator:
+		b.WriteString("and")
+	case OrFilterOperator:
+		b.WriteString("or")
+	case NotFilterOperator:
+		b.WriteString("not")
+	}
+
+	if e.Matched {
+		b.WriteString(": matched")
+	} else {
+		b.WriteString(": not matched")
+	}
+
+	if e.Operator == AndOperator {
+		b.WriteString(" (value: ")
+		if e.Value == nil {
+			b.WriteString("none")
+		} else {
+			// the contain comparator keeps lists as they are.
+			b.WriteString(translateValue(ContainComparator, e.Value))
+		}
+		b.WriteString(")")
+	}
+
+	b.WriteString("\n")
+
+	for _, c := range e.Children {
+		c.write(b, depth+1)
+	}
+}
+
+// explainFilter evaluates the given filter against the given identifiable and returns
+// the explanation of the result. It follows the semantics of MatchesFilter.
+func explainFilter(identifiable AttributeSpecifiable, filter *Filter) *MatchExplanation {
+
+	root := &MatchExplanation{Operator: AndFilterOperator, Matched: true}
+
+	for i, op := range filter.operators {
+
+		node := &MatchExplanation{Operator: op}
+
+		switch op {
+
+		case AndOperator:
+			node.Term = &FilterTerm{
+				Key:        filter.keys[i],
+				Comparator: filter.comparators[i],
+				Values:     append(FilterValue{}, filter.values[i]...),
+			}
+			node.Matched, node.Value = matchesTerm(identifiable, filter.keys[i], filter.comparators[i], filter.values[i])
+
+		default:
+			var matchedCount int
+			for _, sub := range filter.subFilters(i) {
+				child := explainFilter(identifiable, sub)
+				node.Children = append(node.Children, child)
+				if child.Matched {
+					matchedCount++
+				}
+			}
+
+			switch op {
+			case AndFilterOperator:
+				node.Matched = matchedCount == len(node.Children)
+			case OrFilterOperator:
+				node.Matched = len(node.Children) == 0 || matchedCount > 0
+			case NotFilterOperator:
+				node.Matched = matchedCount != len(node.Children)
+			}
+		}
+
+		root.Children = append(root.Children, node)
+		root.Matched = root.Matched && node.Matched
+	}
+
+	return root
+}
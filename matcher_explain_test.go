@@ -0,0 +1,176 @@
+package elemental_test
+
+import (
+	"testing"
+
+	"go.aporeto.io/elemental"
+	testmodel "go.aporeto.io/elemental/test/model"
+)
+
+func TestMatchesFilter_Explain(t *testing.T) {
+
+	user := testmodel.NewUser()
+	user.FirstName = "Alice"
+	user.LastName = "Bob"
+	user.UserName = "alice"
+
+	filter, err := elemental.NewFilterFromString(`firstName == "Alice" and (lastName == "Charlie" or userName matches "^al") and not (archived == true)`)
+	if err != nil {
+		t.Fatalf("unable to parse filter: %s", err)
+	}
+
+	var explanation elemental.MatchExplanation
+	matched, err := elemental.MatchesFilter(user, filter, elemental.OptExplain(&explanation))
+	if err != nil {
+		t.Fatalf("did not expect to get an error, but received: %s", err)
+	}
+
+	if !matched || !explanation.Matched {
+		t.Errorf("expected the filter to match: %t, explanation: %t", matched, explanation.Matched)
+	}
+
+	expected := `and: matched
+  and: matched
+    and: matched
+      firstName == "Alice": matched (value: "Alice")
+    and: matched
+      or: matched
+        and: not matched
+          lastName == "Charlie": not matched (value: "Bob")
+        and: matched
+          userName matches ["^al"]: matched (value: "alice")
+    and: matched
+      not: matched
+        and: not matched
+          archived == true: not matched (value: false)`
+
+	if explanation.String() != expected {
+		t.Errorf("unexpected explanation\nexpected:\n%s\nactual:\n%s", expected, explanation.String())
+	}
+
+	term := explanation.Children[0].Children[0].Children[0]
+	if term.Operator != elemental.AndOperator || term.Term.Key != "firstName" || term.Value != "Alice" {
+		t.Errorf("unexpected term explanation: %#v", term)
+	}
+
+	cf, err := elemental.CompileFilter(filter, user)
+	if err != nil {
+		t.Fatalf("unable to compile filter: %s", err)
+	}
+
+	e, err := cf.Explain(user)
+	if err != nil {
+		t.Fatalf("unable to explain compiled filter: %s", err)
+	}
+
+	if e.String() != expected {
+		t.Errorf("unexpected compiled filter explanation\nexpected:\n%s\nactual:\n%s", expected, e.String())
+	}
+}
+
+func TestCompiledFilter_ExplainError(t *testing.T) {
+
+	user := testmodel.NewUser()
+
+	filter := elemental.NewFilterComposer().WithKey("firstName").Equals("Alice").Done()
+
+	cf, err := elemental.CompileFilter(filter, user)
+	if err != nil {
+		t.Fatalf("unable to compile filter: %s", err)
+	}
+
+	// the explanation evaluates the filter itself, which can be changed after the compilation.
+	filter.Values()[0][0] = elemental.FilterPlaceholder{Name: "name"}
+
+	e, err := cf.Explain(user)
+	if err == nil {
+		t.Fatalf("expected an error, got the explanation: %s", e)
+	}
+
+	if e != nil {
+		t.Errorf("did not expect an explanation, got: %s", e)
+	}
+}
+
+func TestMatchesFilter_ExplainConsistency(t *testing.T) {
+
+	user := testmodel.NewUser()
+	user.FirstName = "Alice"
+	user.LastName = "Bob"
+
+	tests := map[string]struct {
+		filter        *elemental.Filter
+		expectedMatch bool
+	}{
+		"failing term": {
+			filter:        elemental.NewFilterComposer().WithKey("firstName").Equals("Alice").WithKey("lastName").Equals("Charlie").Done(),
+			expectedMatch: false,
+		},
+		"failing or followed by a matching or": {
+			filter: elemental.NewFilterComposer().
+				Or(
+					elemental.NewFilterComposer().WithKey("firstName").Equals("Charlie").Done(),
+					elemental.NewFilterComposer().WithKey("lastName").Equals("Charlie").Done(),
+				).
+				Or(
+					elemental.NewFilterComposer().WithKey("firstName").Equals("Alice").Done(),
+				).
+				Done(),
+			expectedMatch: false,
+		},
+		"failing and followed by a matching or": {
+			filter: elemental.NewFilterComposer().
+				And(
+					elemental.NewFilterComposer().WithKey("firstName").Equals("Charlie").Done(),
+				).
+				Or(
+					elemental.NewFilterComposer().WithKey("firstName").Equals("Alice").Done(),
+				).
+				Done(),
+			expectedMatch: false,
+		},
+		"empty or": {
+			filter:        elemental.NewFilterComposer().WithKey("firstName").Equals("Alice").Or().Done(),
+			expectedMatch: true,
+		},
+		"empty not": {
+			filter:        elemental.NewFilterComposer().WithKey("firstName").Equals("Alice").Not().Done(),
+			expectedMatch: false,
+		},
+		"not with a failing sub filter": {
+			filter: elemental.NewFilterComposer().
+				Not(
+					elemental.NewFilterComposer().WithKey("firstName").Equals("Alice").Done(),
+					elemental.NewFilterComposer().WithKey("lastName").Equals("Charlie").Done(),
+				).
+				Done(),
+			expectedMatch: true,
+		},
+		"missing attribute": {
+			filter:        elemental.NewFilterComposer().WithKey("unknown").NotExists().Done(),
+			expectedMatch: true,
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			matched, err := elemental.MatchesFilter(user, tc.filter)
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %s", err)
+			}
+			if matched != tc.expectedMatch {
+				t.Errorf("match expectation failed: expected a match: %t, matched occurred: %t", tc.expectedMatch, matched)
+			}
+
+			var explanation elemental.MatchExplanation
+			explained, err := elemental.MatchesFilter(user, tc.filter, elemental.OptExplain(&explanation))
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %s", err)
+			}
+			if explained != tc.expectedMatch || explanation.Matched != tc.expectedMatch {
+				t.Errorf("explained match expectation failed: expected a match: %t, matched occurred: %t\n%s", tc.expectedMatch, explained, &explanation)
+			}
+		})
+	}
+}
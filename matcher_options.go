@@ -1,7 +1,17 @@
 package elemental
 
-type matchConfig struct{}
+type matchConfig struct {
+	explanation *MatchExplanation
+}
 
 // MatcherOption represents the type for the options that can be passed to the helper `MatchesFilter` which can be used
 // to alter the matching behaviour
 type MatcherOption func(*matchConfig)
+
+// OptExplain makes MatchesFilter record in the given MatchExplanation why the identity matched the filter or not.
+// When explaining, all the terms and sub filters are evaluated, even if the result is known before.
+func OptExplain(explanation *MatchExplanation) MatcherOption {
+	return func(config *matchConfig) {
+		config.explanation = explanation
+	}
+}
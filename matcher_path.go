@@ -0,0 +1,135 @@
+package elemental
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ValueForPath returns the value of the given attribute path of the given object.
+//
+// The path is either the name of an attribute, or a dotted path like "metadata.owner" or "spec.ports.port"
+// targeting a value nested in an attribute. The first element of the path is resolved using ValueForAttribute,
+// and the others are resolved by walking the attribute value:
+//   - nested AttributeSpecifiables (ref attributes) are walked using ValueForAttribute
+//   - maps with string keys are walked using the path element as key
+//   - structs are walked using the field that has a json or msgpack tag, or a name (case insensitively), equal to
+//     the path element
+//   - slices and arrays (refList attributes) are walked element by element, and the result is the list of all the
+//     values found in the elements
+//
+// It returns nil if the path cannot be resolved.
+//
+// When the path goes through a list, the comparators of a filter have the same semantics as they have with
+// list attributes: a positive comparator (==, in, matches, etc.) matches if any of the values found matches,
+// and a negative comparator (!=, not in, not matches, etc.) matches if none of the values found matches.
+func ValueForPath(obj AttributeSpecifiable, path string) any {
+
+	root, rest, nested := strings.Cut(path, ".")
+	if !nested {
+		return obj.ValueForAttribute(path)
+	}
+
+	if spec, ok := specificationForKey(obj, root); ok {
+		root = spec.Name
+	}
+
+	return walkPath(obj.ValueForAttribute(root), strings.Split(rest, "."))
+}
+
+// isPath returns true if the given filter key is a dotted path.
+func isPath(key string) bool {
+	return strings.Contains(key, ".")
+}
+
+// pathRoot returns the name of the root attribute of the given filter key.
+func pathRoot(key string) string {
+	root, _, _ := strings.Cut(key, ".")
+	return root
+}
+
+func walkPath(value any, path []string) any {
+
+	if len(path) == 0 || value == nil {
+		return value
+	}
+
+	if as, ok := value.(AttributeSpecifiable); ok {
+		name := path[0]
+		if spec, ok := specificationForKey(as, name); ok {
+			name = spec.Name
+		}
+		return walkPath(as.ValueForAttribute(name), path[1:])
+	}
+
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		item := v.MapIndex(reflect.ValueOf(path[0]).Convert(v.Type().Key()))
+		if !item.IsValid() {
+			return nil
+		}
+		return walkPath(item.Interface(), path[1:])
+
+	case reflect.Struct:
+		field, ok := structFieldForPath(v, path[0])
+		if !ok {
+			return nil
+		}
+		return walkPath(field.Interface(), path[1:])
+
+	case reflect.Slice, reflect.Array:
+		var out []any
+		for i := 0; i < v.Len(); i++ {
+			item := walkPath(v.Index(i).Interface(), path)
+			if item == nil {
+				continue
+			}
+			// values found in nested lists are flattened.
+			if iv := reflect.ValueOf(item); isArrayLike(iv) && iv.Type().Elem().Kind() != reflect.Uint8 {
+				for j := 0; j < iv.Len(); j++ {
+					out = append(out, iv.Index(j).Interface())
+				}
+				continue
+			}
+			out = append(out, item)
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return out
+	}
+
+	return nil
+}
+
+func structFieldForPath(v reflect.Value, name string) (reflect.Value, bool) {
+
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+
+		f := t.Field(i)
+		fieldName, _, ok := structFieldName(f)
+		if !ok {
+			continue
+		}
+
+		// fields named by a tag are matched exactly.
+		if fieldName == name || (fieldName == f.Name && strings.EqualFold(f.Name, name)) {
+			return v.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
@@ -0,0 +1,172 @@
+package elemental_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"go.aporeto.io/elemental"
+	"go.aporeto.io/elemental/internal"
+	testmodel "go.aporeto.io/elemental/test/model"
+)
+
+type testPort struct {
+	Name     string `json:"name"`
+	Port     int    `json:"port"`
+	Protocol string
+}
+
+type testSpec struct {
+	Ports  []testPort        `json:"ports"`
+	Labels map[string]string `json:"labels"`
+	Owner  *testmodel.User   `json:"owner"`
+	hidden string
+}
+
+func makePathMock(t *testing.T) elemental.AttributeSpecifiable {
+
+	owner := testmodel.NewUser()
+	owner.FirstName = "Alice"
+
+	values := map[string]any{
+		"metadata": map[string]string{"owner": "alice", "team": "core"},
+		"spec": &testSpec{
+			Ports: []testPort{
+				{Name: "http", Port: 80, Protocol: "TCP"},
+				{Name: "dns", Port: 53, Protocol: "UDP"},
+			},
+			Labels: map[string]string{"env": "prod"},
+			Owner:  owner,
+			hidden: "secret",
+		},
+		"owners": []*testmodel.User{owner, testmodel.NewUser()},
+	}
+
+	mockAS := internal.NewMockAttributeSpecifiable(gomock.NewController(t))
+	mockAS.
+		EXPECT().
+		ValueForAttribute(gomock.Any()).
+		DoAndReturn(func(name string) any { return values[name] }).
+		AnyTimes()
+	mockAS.
+		EXPECT().
+		SpecificationForAttribute(gomock.Any()).
+		DoAndReturn(func(name string) elemental.AttributeSpecification {
+			if _, ok := values[name]; ok {
+				return elemental.AttributeSpecification{Name: name, Type: "external", Filterable: true}
+			}
+			return elemental.AttributeSpecification{}
+		}).
+		AnyTimes()
+
+	return mockAS
+}
+
+func TestValueForPath(t *testing.T) {
+
+	tests := map[string]struct {
+		path     string
+		expected any
+	}{
+		"attribute":                      {path: "metadata", expected: map[string]string{"owner": "alice", "team": "core"}},
+		"map key":                        {path: "metadata.owner", expected: "alice"},
+		"missing map key":                {path: "metadata.nope", expected: nil},
+		"struct field using its json":    {path: "spec.labels.env", expected: "prod"},
+		"struct field using its name":    {path: "spec.ports.Protocol", expected: []any{"TCP", "UDP"}},
+		"unexported struct field":        {path: "spec.hidden", expected: nil},
+		"slice of structs":               {path: "spec.ports.port", expected: []any{80, 53}},
+		"nested attribute specifiable":   {path: "spec.owner.firstName", expected: "Alice"},
+		"slice of attribute specifiable": {path: "owners.firstName", expected: []any{"Alice", ""}},
+		"path through a scalar":          {path: "metadata.owner.name", expected: nil},
+		"unknown attribute":              {path: "nope.owner", expected: nil},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			if v := elemental.ValueForPath(makePathMock(t), tc.path); !reflect.DeepEqual(v, tc.expected) {
+				t.Errorf("unexpected value for %s\nexpected: %#v\nactual:   %#v", tc.path, tc.expected, v)
+			}
+		})
+	}
+}
+
+func TestMatchesFilter_Paths(t *testing.T) {
+
+	tests := map[string]struct {
+		filter        string
+		expectedMatch bool
+	}{
+		"equal on a map key":                      {filter: `metadata.owner == "alice"`, expectedMatch: true},
+		"not equal on a map key":                  {filter: `metadata.owner != "alice"`, expectedMatch: false},
+		"exists on a map key":                     {filter: `metadata.team exists`, expectedMatch: true},
+		"exists on a missing map key":             {filter: `metadata.nope exists`, expectedMatch: false},
+		"not exists on a missing map key":         {filter: `metadata.nope not exists`, expectedMatch: true},
+		"any element of a list equals":            {filter: `spec.ports.port == 53`, expectedMatch: true},
+		"no element of a list equals":             {filter: `spec.ports.port == 22`, expectedMatch: false},
+		"all elements of a list are not equal":    {filter: `spec.ports.port != 22`, expectedMatch: true},
+		"any element of a list is in":             {filter: `spec.ports.name in ["ssh", "dns"]`, expectedMatch: true},
+		"no element of a list is in":              {filter: `spec.ports.name not in ["http"]`, expectedMatch: false},
+		"any element of a list is greater":        {filter: `spec.ports.port > 79`, expectedMatch: true},
+		"any element of a list matches":           {filter: `spec.ports.name matches "^h"`, expectedMatch: true},
+		"nested attribute specifiable":            {filter: `spec.owner.firstName == "Alice" and metadata.owner == "alice"`, expectedMatch: true},
+		"nested attribute specifiable in a list":  {filter: `owners.firstName contains "Alice"`, expectedMatch: true},
+		"negated sub filter using paths":          {filter: `not (spec.labels.env == "dev")`, expectedMatch: true},
+		"missing nested value is not equal to it": {filter: `metadata.nope == "x"`, expectedMatch: false},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			filter, err := elemental.NewFilterFromString(tc.filter)
+			if err != nil {
+				t.Fatalf("test setup invalid - unable to parse filter: %s", err)
+			}
+
+			obj := makePathMock(t)
+
+			matched, err := elemental.MatchesFilter(obj, filter)
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %s", err)
+			}
+
+			if matched != tc.expectedMatch {
+				t.Errorf("match expectation failed for %s: expected a match: %t", tc.filter, tc.expectedMatch)
+			}
+
+			cf, err := elemental.CompileFilter(filter, obj)
+			if err != nil {
+				t.Fatalf("unable to compile filter: %s", err)
+			}
+
+			if cf.Match(obj) != tc.expectedMatch {
+				t.Errorf("compiled match expectation failed for %s: expected a match: %t", tc.filter, tc.expectedMatch)
+			}
+
+			if err := elemental.ValidateFilter(filter, obj); err != nil {
+				t.Errorf("did not expect the filter to be invalid, but received: %s", err)
+			}
+		})
+	}
+}
+
+func TestGeneratedModel_Paths(t *testing.T) {
+
+	user := testmodel.NewUser()
+
+	if spec := user.SpecificationForAttribute("FirstName"); spec.Name != "firstName" {
+		t.Errorf("expected the specification of the attribute, got: %#v", spec)
+	}
+
+	if spec := user.SpecificationForAttribute("FirstName.something"); spec.Name != "" {
+		t.Errorf("expected an empty specification for a dotted path, got: %#v", spec)
+	}
+
+	if spec := user.SpecificationForAttribute("nope.something"); spec.Name != "" {
+		t.Errorf("expected an empty specification, got: %#v", spec)
+	}
+
+	if v := user.ValueForAttribute("firstName.something"); v != nil {
+		t.Errorf("expected no value, got: %#v", v)
+	}
+}
@@ -2,7 +2,6 @@ package elemental_test
 
 import (
 	"errors"
-	"fmt"
 	"reflect"
 	"testing"
 	"time"
@@ -1290,86 +1289,490 @@ func TestMatchesFilter(t *testing.T) {
 	}
 }
 
-func TestUnsupportedComparators(t *testing.T) {
+// this unit test suite tests the functionality of the GreaterComparator, GreaterOrEqualComparator, LesserComparator
+// and LesserOrEqualComparator when used in conjunction with the helper MatchesFilter
+func TestOrderingComparators(t *testing.T) {
 
-	identifiable := testmodel.NewUser()
-	testAttribute := "firstName"
+	type aliasToInt int
+
+	testAttributeName := "someAttribute"
+	now := time.Now()
 
 	tests := map[string]struct {
-		filter     *elemental.Filter
-		comparator string
+		filter         *elemental.Filter
+		attributeValue any
+		expectedMatch  bool
 	}{
-		"greater than": {
-			filter:     elemental.NewFilterComposer().WithKey(testAttribute).GreaterThan("").Done(),
-			comparator: ">",
+		"should match if an int attribute is greater than the comparator value": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).GreaterThan(int64(1)).Done(),
+			attributeValue: 2,
+			expectedMatch:  true,
+		},
+		"should not match if an int attribute is equal to the comparator value when using '>'": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).GreaterThan(2).Done(),
+			attributeValue: 2,
+			expectedMatch:  false,
+		},
+		"should match if an int attribute is equal to the comparator value when using '>='": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).GreaterOrEqualThan(2).Done(),
+			attributeValue: aliasToInt(2),
+			expectedMatch:  true,
+		},
+		"should match if a float attribute is lesser than an int comparator value": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).LesserThan(int64(2)).Done(),
+			attributeValue: 1.5,
+			expectedMatch:  true,
+		},
+		"should match if an uint attribute is lesser or equal to the comparator value": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).LesserOrEqualThan(uint(3)).Done(),
+			attributeValue: uint8(3),
+			expectedMatch:  true,
+		},
+		"should compare strings lexicographically": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).GreaterThan("abc").Done(),
+			attributeValue: "abd",
+			expectedMatch:  true,
+		},
+		"should compare booleans with false lesser than true": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).LesserThan(true).Done(),
+			attributeValue: false,
+			expectedMatch:  true,
+		},
+		"should compare times": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).GreaterThan(now.Add(-time.Hour)).Done(),
+			attributeValue: now,
+			expectedMatch:  true,
+		},
+		"should compare a time attribute to a duration relative to now": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).GreaterThan(-time.Hour).Done(),
+			attributeValue: now.Add(-30 * time.Minute),
+			expectedMatch:  true,
+		},
+		"should not match a time attribute older than the duration relative to now": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).GreaterThan(-time.Hour).Done(),
+			attributeValue: now.Add(-2 * time.Hour),
+			expectedMatch:  false,
+		},
+		"should compare durations": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).LesserThan(time.Minute).Done(),
+			attributeValue: time.Second,
+			expectedMatch:  true,
+		},
+		"should match if any element of a slice attribute satisfies the comparison": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).GreaterThan(10).Done(),
+			attributeValue: []int{1, 5, 11},
+			expectedMatch:  true,
+		},
+		"should not match if no element of a slice attribute satisfies the comparison": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).GreaterThan(10).Done(),
+			attributeValue: []int{1, 5, 10},
+			expectedMatch:  false,
+		},
+		"should not match values of different types": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).LesserThan("10").Done(),
+			attributeValue: 1,
+			expectedMatch:  false,
+		},
+		"should not match if the attribute does not exist": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).LesserThan(10).Done(),
+			attributeValue: nil,
+			expectedMatch:  false,
 		},
-		"greater than equal": {
-			filter:     elemental.NewFilterComposer().WithKey(testAttribute).GreaterOrEqualThan("").Done(),
-			comparator: ">=",
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			mockAS := internal.NewMockAttributeSpecifiable(gomock.NewController(t))
+			mockAS.
+				EXPECT().
+				ValueForAttribute(testAttributeName).
+				Return(tc.attributeValue)
+
+			matched, err := elemental.MatchesFilter(mockAS, tc.filter)
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %+v\n", err)
+			}
+
+			if matched != tc.expectedMatch {
+				t.Errorf("\n"+
+					"match expectation failed:\n"+
+					"expected a match: %t\n"+
+					"matched occurred: %+v\n",
+					tc.expectedMatch,
+					matched)
+			}
+		})
+	}
+}
+
+// this unit test suite tests the functionality of the InComparator, NotInComparator, ContainComparator and
+// NotContainComparator when used in conjunction with the helper MatchesFilter
+func TestInComparators(t *testing.T) {
+
+	testAttributeName := "someAttribute"
+
+	tests := map[string]struct {
+		filter         *elemental.Filter
+		attributeValue any
+		expectedMatch  bool
+	}{
+		"should match if the attribute is one of the comparator values using 'in'": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).In("a", "b").Done(),
+			attributeValue: "b",
+			expectedMatch:  true,
+		},
+		"should not match if the attribute is none of the comparator values using 'in'": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).In("a", "b").Done(),
+			attributeValue: "c",
+			expectedMatch:  false,
+		},
+		"should convert the comparator values to the attribute type using 'in'": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).In(int64(1), int64(2)).Done(),
+			attributeValue: 2,
+			expectedMatch:  true,
+		},
+		"should match if a slice attribute contains one of the comparator values using 'in'": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).In("a", "b").Done(),
+			attributeValue: []string{"c", "a"},
+			expectedMatch:  true,
+		},
+		"should match if a slice attribute contains one of the comparator values using 'contains'": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).Contains("x", "b").Done(),
+			attributeValue: []string{"a", "b"},
+			expectedMatch:  true,
+		},
+		"should not match if a slice attribute contains none of the comparator values using 'contains'": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).Contains("x", "y").Done(),
+			attributeValue: []string{"a", "b"},
+			expectedMatch:  false,
+		},
+		"should not match if the attribute does not exist using 'in'": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).In("a").Done(),
+			attributeValue: nil,
+			expectedMatch:  false,
+		},
+		"should match if the attribute is none of the comparator values using 'not in'": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).NotIn("a", "b").Done(),
+			attributeValue: "c",
+			expectedMatch:  true,
+		},
+		"should not match if the attribute is one of the comparator values using 'not in'": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).NotIn("a", "b").Done(),
+			attributeValue: "a",
+			expectedMatch:  false,
+		},
+		"should match if the attribute does not exist using 'not in'": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).NotIn("a").Done(),
+			attributeValue: nil,
+			expectedMatch:  true,
+		},
+		"should not match if a slice attribute contains one of the comparator values using 'not contains'": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).NotContains("b").Done(),
+			attributeValue: []string{"a", "b"},
+			expectedMatch:  false,
+		},
+		"should match if a slice attribute contains none of the comparator values using 'not contains'": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).NotContains("c").Done(),
+			attributeValue: []string{"a", "b"},
+			expectedMatch:  true,
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			mockAS := internal.NewMockAttributeSpecifiable(gomock.NewController(t))
+			mockAS.
+				EXPECT().
+				ValueForAttribute(testAttributeName).
+				Return(tc.attributeValue)
+
+			matched, err := elemental.MatchesFilter(mockAS, tc.filter)
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %+v\n", err)
+			}
+
+			if matched != tc.expectedMatch {
+				t.Errorf("\n"+
+					"match expectation failed:\n"+
+					"expected a match: %t\n"+
+					"matched occurred: %+v\n",
+					tc.expectedMatch,
+					matched)
+			}
+		})
+	}
+}
+
+func TestNotMatchComparator(t *testing.T) {
+
+	testAttributeName := "someAttribute"
+
+	tests := map[string]struct {
+		filter         *elemental.Filter
+		attributeValue any
+		expectedMatch  bool
+	}{
+		"should match if the attribute does not match any of the expressions": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).NotMatches("^a", "^b").Done(),
+			attributeValue: "cat",
+			expectedMatch:  true,
+		},
+		"should not match if the attribute matches one of the expressions": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).NotMatches("^a", "^b").Done(),
+			attributeValue: "bat",
+			expectedMatch:  false,
+		},
+		"should not match if one of the slice attribute values matches one of the expressions": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).NotMatches("^b").Done(),
+			attributeValue: []string{"cat", "bat"},
+			expectedMatch:  false,
+		},
+		"should match if the attribute does not exist": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).NotMatches("^a").Done(),
+			attributeValue: nil,
+			expectedMatch:  true,
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			mockAS := internal.NewMockAttributeSpecifiable(gomock.NewController(t))
+			mockAS.
+				EXPECT().
+				ValueForAttribute(testAttributeName).
+				Return(tc.attributeValue)
+
+			matched, err := elemental.MatchesFilter(mockAS, tc.filter)
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %+v\n", err)
+			}
+
+			if matched != tc.expectedMatch {
+				t.Errorf("\n"+
+					"match expectation failed:\n"+
+					"expected a match: %t\n"+
+					"matched occurred: %+v\n",
+					tc.expectedMatch,
+					matched)
+			}
+		})
+	}
+}
+
+func TestStringComparators(t *testing.T) {
+
+	testAttributeName := "someAttribute"
+
+	tests := map[string]struct {
+		filter         *elemental.Filter
+		attributeValue any
+		expectedMatch  bool
+	}{
+		"should match if the attribute is equal ignoring the case": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).EqualsIgnoreCase("HeLLo").Done(),
+			attributeValue: "hello",
+			expectedMatch:  true,
+		},
+		"should not match if the attribute is not equal ignoring the case": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).EqualsIgnoreCase("hello").Done(),
+			attributeValue: "hello world",
+			expectedMatch:  false,
+		},
+		"should match if the attribute starts with the value": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).StartsWith("/a/").Done(),
+			attributeValue: "/a/b",
+			expectedMatch:  true,
+		},
+		"should match the value literally": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).StartsWith(".*").Done(),
+			attributeValue: "abc",
+			expectedMatch:  false,
+		},
+		"should not match if the attribute starts with the value in a different case": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).StartsWith("/A").Done(),
+			attributeValue: "/a/b",
+			expectedMatch:  false,
+		},
+		"should match if the attribute ends with the value": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).EndsWith(".com").Done(),
+			attributeValue: "example.com",
+			expectedMatch:  true,
+		},
+		"should match if one of the slice attribute values ends with the value": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).EndsWith(".com").Done(),
+			attributeValue: []string{"a.org", "b.com"},
+			expectedMatch:  true,
+		},
+		"should not match if the value is not a string": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).StartsWith(1).Done(),
+			attributeValue: "1",
+			expectedMatch:  false,
+		},
+		"should not match if the attribute does not exist": {
+			filter:         elemental.NewFilterComposer().WithKey(testAttributeName).EqualsIgnoreCase("").Done(),
+			attributeValue: nil,
+			expectedMatch:  false,
 		},
-		"lesser than": {
-			filter:     elemental.NewFilterComposer().WithKey(testAttribute).LesserThan("").Done(),
-			comparator: "<",
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			mockAS := internal.NewMockAttributeSpecifiable(gomock.NewController(t))
+			mockAS.
+				EXPECT().
+				ValueForAttribute(testAttributeName).
+				Return(tc.attributeValue)
+
+			matched, err := elemental.MatchesFilter(mockAS, tc.filter)
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %+v\n", err)
+			}
+
+			if matched != tc.expectedMatch {
+				t.Errorf("\n"+
+					"match expectation failed:\n"+
+					"expected a match: %t\n"+
+					"matched occurred: %+v\n",
+					tc.expectedMatch,
+					matched)
+			}
+		})
+	}
+}
+
+func TestNotFilterOperator(t *testing.T) {
+
+	tests := map[string]struct {
+		filter        string
+		expectedMatch bool
+	}{
+		"should match if the negated sub filter does not match": {
+			filter:        `not (firstName == "Bob")`,
+			expectedMatch: true,
 		},
-		"lesser than or equal": {
-			filter:     elemental.NewFilterComposer().WithKey(testAttribute).LesserOrEqualThan("").Done(),
-			comparator: "<=",
+		"should not match if the negated sub filter matches": {
+			filter:        `not (firstName == "Alice")`,
+			expectedMatch: false,
 		},
-		"in": {
-			filter:     elemental.NewFilterComposer().WithKey(testAttribute).In("a", "b").Done(),
-			comparator: "in",
+		"should negate a whole or sub filter": {
+			filter:        `not (firstName == "Bob" or lastName == "Smith")`,
+			expectedMatch: false,
 		},
-		"not in": {
-			filter:     elemental.NewFilterComposer().WithKey(testAttribute).NotIn("a", "b").Done(),
-			comparator: "not in",
+		"should negate a whole and sub filter": {
+			filter:        `not (firstName == "Alice" and lastName == "Doe")`,
+			expectedMatch: true,
 		},
-		"contains": {
-			filter:     elemental.NewFilterComposer().WithKey(testAttribute).Contains("a", "b", "c").Done(),
-			comparator: "contains",
+		"should combine a negated sub filter with other terms": {
+			filter:        `lastName == "Smith" and not (firstName matches "^B")`,
+			expectedMatch: true,
 		},
-		"not contains": {
-			filter:     elemental.NewFilterComposer().WithKey(testAttribute).NotContains("a", "b", "c").Done(),
-			comparator: "not contains",
+		"should handle a double negation": {
+			filter:        `not (not (firstName == "Alice"))`,
+			expectedMatch: true,
 		},
 	}
 
-	for description, test := range tests {
-		t.Run(fmt.Sprintf("elemental.MatchesFilter should return an error when using unsupported comparator %s", description), func(t *testing.T) {
-			matched, err := elemental.MatchesFilter(identifiable, test.filter)
+	user := testmodel.NewUser()
+	user.FirstName = "Alice"
+	user.LastName = "Smith"
 
-			if err == nil {
-				t.Fatalf("expected an error to occur when using unsupported comparator\n" +
-					"Hint: if you just added support for a new comparator, you can now remove the failing test case for that comparator")
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			filter, err := elemental.NewFilterFromString(tc.filter)
+			if err != nil {
+				t.Fatalf("test setup invalid - unable to parse filter: %s", err)
+			}
+
+			matched, err := elemental.MatchesFilter(user, filter)
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %+v\n", err)
 			}
 
-			if matched {
-				t.Errorf("a match should never occur when using an unsupported operator")
+			if matched != tc.expectedMatch {
+				t.Errorf("match expectation failed for %s: expected a match: %t", tc.filter, tc.expectedMatch)
 			}
 
-			var me *elemental.MatcherError
-			if ok := errors.As(err, &me); !ok {
-				t.Fatalf("expected underlying error type to be: *elemental.MatcherError\n"+
-					"actual error type was: %s\n"+
-					"WARNING: this is a major breaking change as you could break client error handling logic",
-					reflect.TypeOf(err))
+			cf, err := elemental.CompileFilter(filter, user)
+			if err != nil {
+				t.Fatalf("unable to compile filter: %s", err)
 			}
 
-			// this verifies that the matcher error chain contains the expected error
+			if cf.Match(user) != tc.expectedMatch {
+				t.Errorf("compiled match expectation failed for %s: expected a match: %t", tc.filter, tc.expectedMatch)
+			}
+		})
+	}
+}
+
+func TestOrFilterOperator(t *testing.T) {
+
+	term := func(key string, value string) *elemental.Filter {
+		return elemental.NewFilterComposer().WithKey(key).Equals(value).Done()
+	}
+
+	tests := map[string]struct {
+		filter        *elemental.Filter
+		expectedMatch bool
+	}{
+		"should match if one of the sub filters matches": {
+			filter:        elemental.NewFilterComposer().Or(term("firstName", "Bob"), term("lastName", "Smith")).Done(),
+			expectedMatch: true,
+		},
+		"should not match if none of the sub filters matches": {
+			filter:        elemental.NewFilterComposer().Or(term("firstName", "Bob"), term("lastName", "Doe")).Done(),
+			expectedMatch: false,
+		},
+		"should not match if a first or does not match and a second one matches": {
+			filter: elemental.NewFilterComposer().
+				Or(term("firstName", "Bob"), term("lastName", "Doe")).
+				Or(term("firstName", "Alice"), term("lastName", "Doe")).
+				Done(),
+			expectedMatch: false,
+		},
+		"should not match if a first or matches and a second one does not match": {
+			filter: elemental.NewFilterComposer().
+				Or(term("firstName", "Alice"), term("lastName", "Doe")).
+				Or(term("firstName", "Bob"), term("lastName", "Doe")).
+				Done(),
+			expectedMatch: false,
+		},
+		"should match if all the ors match": {
+			filter: elemental.NewFilterComposer().
+				Or(term("firstName", "Alice"), term("lastName", "Doe")).
+				Or(term("firstName", "Bob"), term("lastName", "Smith")).
+				Done(),
+			expectedMatch: true,
+		},
+	}
+
+	user := testmodel.NewUser()
+	user.FirstName = "Alice"
+	user.LastName = "Smith"
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			matched, err := elemental.MatchesFilter(user, tc.filter)
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %+v\n", err)
+			}
+
+			if matched != tc.expectedMatch {
+				t.Errorf("match expectation failed for %s: expected a match: %t", tc.filter, tc.expectedMatch)
+			}
 
-			if !errors.Is(err, elemental.ErrUnsupportedComparator{}) {
-				t.Errorf("expected the matcher error to contain an 'elemental.ErrUnsupportedComparator'\n"+
-					"actual error type: %s\n"+
-					"WARNING: this is a major breaking change as you could break client error handling logic",
-					reflect.TypeOf(err),
-				)
+			cf, err := elemental.CompileFilter(tc.filter, user)
+			if err != nil {
+				t.Fatalf("unable to compile filter: %s", err)
 			}
 
-			expectedErrCopy := fmt.Sprintf("elemental: unable to match: unsupported comparator: %q", test.comparator)
-			if me.Error() != expectedErrCopy {
-				t.Errorf("expected the error copy to equal: %s\n"+
-					"actual error copy: %s",
-					expectedErrCopy,
-					me.Error())
+			if cf.Match(user) != tc.expectedMatch {
+				t.Errorf("compiled match expectation failed for %s: expected a match: %t", tc.filter, tc.expectedMatch)
 			}
 		})
 	}
@@ -0,0 +1,376 @@
+package elemental
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/gofrs/uuid"
+)
+
+// A MemoryStore is an in-memory store of Identifiables, keyed by Identity, that executes Requests
+// the way a backend would. It is meant to replace a database in tests.
+//
+// Objects that are Namespaceable are only visible from their namespace, from its ancestors if the
+// Request is Recursive, and from its descendants if the Request is Propagated and the object is a
+// Propagatable that propagates. The parent of a Request is ignored.
+type MemoryStore struct {
+	manager ModelManager
+	objects map[string]IdentifiablesList
+	lock    sync.RWMutex
+}
+
+// NewMemoryStore returns a new empty MemoryStore that uses the given ModelManager
+// to create the objects decoded from the Requests.
+func NewMemoryStore(manager ModelManager) *MemoryStore {
+
+	return &MemoryStore{
+		manager: manager,
+		objects: map[string]IdentifiablesList{},
+	}
+}
+
+// Insert adds the given objects to the store as they are. Objects without
+// identifier are given a new one. Existing objects with the same identifier
+// are replaced.
+func (s *MemoryStore) Insert(objects ...Identifiable) {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, obj := range objects {
+
+		if obj.Identifier() == "" {
+			obj.SetIdentifier(uuid.Must(uuid.NewV4()).String())
+		}
+
+		if i := s.index(obj.Identity(), obj.Identifier()); i != -1 {
+			s.objects[obj.Identity().Name][i] = obj
+			continue
+		}
+
+		s.objects[obj.Identity().Name] = append(s.objects[obj.Identity().Name], obj)
+	}
+}
+
+// Objects returns all the objects of the given Identity, in insertion order.
+func (s *MemoryStore) Objects(identity Identity) IdentifiablesList {
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return slices.Clone(s.objects[identity.Name])
+}
+
+// Execute executes the given Request and returns the Response, holding the encoded objects
+// as a real backend would:
+//   - retrieve-many returns the visible objects matching the filters given by the q parameters,
+//     sorted using the Order of the Request or the DefaultOrder of the objects, and paginated
+//     using either the Page and PageSize, or the After and Limit of the Request. The Count of
+//     the Response is the number of returned objects, the Total is the number of objects matching
+//     the filters, and the Next is the cursor of the next page, if any, to be used as After.
+//   - info returns the same Count and Total as retrieve-many, without any data
+//   - create decodes and validates the object, gives it a new identifier and the namespace of the Request
+//   - retrieve, update, patch and delete work on the visible object with the ObjectID of the Request.
+//     Updated and patched objects are validated before they replace the stored object, which is left
+//     as is if they are not valid. Only PlainIdentifiables that are Patchable can be patched.
+//
+// Returned errors are Errors with the status code a backend would return.
+func (s *MemoryStore) Execute(req *Request) (*Response, error) {
+
+	switch req.Operation {
+
+	case OperationRetrieveMany, OperationInfo:
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+		return s.retrieveMany(req)
+
+	case OperationRetrieve:
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+		return s.retrieve(req)
+
+	case OperationCreate:
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		return s.create(req)
+
+	case OperationUpdate, OperationPatch:
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		return s.update(req)
+
+	case OperationDelete:
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		return s.delete(req)
+
+	default:
+		return nil, NewError("Bad Request", fmt.Sprintf("Unsupported operation '%s'", req.Operation), "elemental", http.StatusBadRequest)
+	}
+}
+
+func (s *MemoryStore) retrieveMany(req *Request) (*Response, error) {
+
+	objects := IdentifiablesList{}
+	for _, obj := range s.objects[req.Identity.Name] {
+		if isVisibleFromRequest(obj, req) {
+			objects = append(objects, obj)
+		}
+	}
+
+	filter, err := filterFromParameters(req.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter != nil {
+		if objects, err = objects.Filter(filter); err != nil {
+			return nil, NewError("Bad Request", err.Error(), "elemental", http.StatusBadRequest)
+		}
+	}
+
+	if err := objects.Sort(req.Order...); err != nil {
+		return nil, NewError("Bad Request", err.Error(), "elemental", http.StatusBadRequest)
+	}
+
+	resp := NewResponse(req)
+	resp.Total = len(objects)
+
+	switch {
+
+	case req.After != "":
+		start := slices.IndexFunc(objects, func(obj Identifiable) bool { return obj.Identifier() == req.After })
+		if start == -1 {
+			return nil, NewError("Bad Request", fmt.Sprintf("Invalid cursor '%s'", req.After), "elemental", http.StatusBadRequest)
+		}
+		objects = objects[start+1:]
+		if req.Limit > 0 && len(objects) > req.Limit {
+			objects = objects[:req.Limit]
+			resp.Next = objects[len(objects)-1].Identifier()
+		}
+
+	case req.Limit > 0:
+		if len(objects) > req.Limit {
+			objects = objects[:req.Limit]
+			resp.Next = objects[len(objects)-1].Identifier()
+		}
+
+	case req.PageSize > 0:
+		page := max(req.Page, 1)
+		start := min((page-1)*req.PageSize, len(objects))
+		objects = objects[start:min(start+req.PageSize, len(objects))]
+	}
+
+	resp.StatusCode = http.StatusOK
+	resp.Count = len(objects)
+
+	if req.Operation == OperationInfo {
+		return resp, nil
+	}
+
+	if err := resp.Encode(objects); err != nil {
+		return nil, NewError("Internal Server Error", err.Error(), "elemental", http.StatusInternalServerError)
+	}
+
+	return resp, nil
+}
+
+func (s *MemoryStore) retrieve(req *Request) (*Response, error) {
+
+	i, err := s.visibleIndex(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return makeMemoryStoreResponse(req, http.StatusOK, s.objects[req.Identity.Name][i])
+}
+
+func (s *MemoryStore) create(req *Request) (*Response, error) {
+
+	obj := s.manager.Identifiable(req.Identity)
+	if obj == nil {
+		return nil, NewError("Bad Request", fmt.Sprintf("Unknown identity '%s'", req.Identity.Name), "elemental", http.StatusBadRequest)
+	}
+
+	if err := req.Decode(obj); err != nil {
+		return nil, NewError("Bad Request", err.Error(), "elemental", http.StatusBadRequest)
+	}
+
+	if v, ok := obj.(Validatable); ok {
+		if err := v.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	obj.SetIdentifier(uuid.Must(uuid.NewV4()).String())
+
+	if n, ok := obj.(Namespaceable); ok {
+		n.SetNamespace(req.Namespace)
+	}
+
+	s.objects[req.Identity.Name] = append(s.objects[req.Identity.Name], obj)
+
+	return makeMemoryStoreResponse(req, http.StatusCreated, obj)
+}
+
+func (s *MemoryStore) update(req *Request) (*Response, error) {
+
+	i, err := s.visibleIndex(req)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := s.objects[req.Identity.Name][i]
+
+	var obj Identifiable
+
+	if req.Operation == OperationPatch {
+
+		// a copy is patched, so the stored object is left as is if the patched one is not valid.
+		if plain, ok := existing.(PlainIdentifiable); ok {
+			obj = plain.ToSparse().ToPlain()
+		}
+
+		patchable, ok := obj.(Patchable)
+		if !ok {
+			return nil, NewError("Method Not Allowed", fmt.Sprintf("Identity '%s' cannot be patched", req.Identity.Name), "elemental", http.StatusMethodNotAllowed)
+		}
+
+		sparse := s.manager.SparseIdentifiable(req.Identity)
+		if sparse == nil {
+			return nil, NewError("Bad Request", fmt.Sprintf("Unknown identity '%s'", req.Identity.Name), "elemental", http.StatusBadRequest)
+		}
+
+		if err := req.Decode(sparse); err != nil {
+			return nil, NewError("Bad Request", err.Error(), "elemental", http.StatusBadRequest)
+		}
+
+		patchable.Patch(sparse)
+
+	} else {
+
+		obj = s.manager.Identifiable(req.Identity)
+		if obj == nil {
+			return nil, NewError("Bad Request", fmt.Sprintf("Unknown identity '%s'", req.Identity.Name), "elemental", http.StatusBadRequest)
+		}
+
+		if err := req.Decode(obj); err != nil {
+			return nil, NewError("Bad Request", err.Error(), "elemental", http.StatusBadRequest)
+		}
+	}
+
+	// the identifier and the namespace of an object cannot be changed.
+	obj.SetIdentifier(existing.Identifier())
+	if n, ok := obj.(Namespaceable); ok {
+		if en, ok := existing.(Namespaceable); ok {
+			n.SetNamespace(en.GetNamespace())
+		}
+	}
+
+	if v, ok := obj.(Validatable); ok {
+		if err := v.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	s.objects[req.Identity.Name][i] = obj
+
+	return makeMemoryStoreResponse(req, http.StatusOK, obj)
+}
+
+func (s *MemoryStore) delete(req *Request) (*Response, error) {
+
+	i, err := s.visibleIndex(req)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := s.objects[req.Identity.Name][i]
+	s.objects[req.Identity.Name] = slices.Delete(s.objects[req.Identity.Name], i, i+1)
+
+	return makeMemoryStoreResponse(req, http.StatusOK, obj)
+}
+
+// index returns the index of the object of the given identity with the given identifier, or -1.
+func (s *MemoryStore) index(identity Identity, id string) int {
+
+	return slices.IndexFunc(s.objects[identity.Name], func(obj Identifiable) bool { return obj.Identifier() == id })
+}
+
+// visibleIndex returns the index of the object targeted by the given Request, or
+// a not found error if there is no such object, or if it is not visible.
+func (s *MemoryStore) visibleIndex(req *Request) (int, error) {
+
+	i := s.index(req.Identity, req.ObjectID)
+	if i == -1 || !isVisibleFromRequest(s.objects[req.Identity.Name][i], req) {
+		return -1, NewError("Not Found", fmt.Sprintf("Cannot find %s with id '%s'", req.Identity.Name, req.ObjectID), "elemental", http.StatusNotFound)
+	}
+
+	return i, nil
+}
+
+func makeMemoryStoreResponse(req *Request, code int, obj Identifiable) (*Response, error) {
+
+	resp := NewResponse(req)
+	resp.StatusCode = code
+	resp.Count = 1
+	resp.Total = 1
+
+	if err := resp.Encode(obj); err != nil {
+		return nil, NewError("Internal Server Error", err.Error(), "elemental", http.StatusInternalServerError)
+	}
+
+	return resp, nil
+}
+
+// isVisibleFromRequest returns true if the given object can be seen from the namespace of the
+// given Request. Objects that are not Namespaceable, and all objects from a Request without
+// namespace, are always visible.
+func isVisibleFromRequest(obj Identifiable, req *Request) bool {
+
+	n, ok := obj.(Namespaceable)
+	if !ok || req.Namespace == "" {
+		return true
+	}
+
+	ns := n.GetNamespace()
+
+	switch {
+	case ns == req.Namespace:
+		return true
+	case req.Recursive && IsNamespaceChildrenOfNamespace(ns, req.Namespace):
+		return true
+	case req.Propagated && IsNamespaceChildrenOfNamespace(req.Namespace, ns):
+		p, ok := obj.(Propagatable)
+		return ok && p.GetProgagate()
+	default:
+		return false
+	}
+}
+
+// filterFromParameters returns the filter given by the q parameters. Several
+// filters are combined with an or. It returns nil if there is no filter.
+func filterFromParameters(params Parameters) (*Filter, error) {
+
+	qs := params.Get("q").StringValues()
+	if len(qs) == 0 {
+		return nil, nil
+	}
+
+	filters := make([]*Filter, len(qs))
+	for i, q := range qs {
+		f, err := NewFilterFromString(q)
+		if err != nil {
+			return nil, err
+		}
+		filters[i] = f
+	}
+
+	if len(filters) == 1 {
+		return filters[0], nil
+	}
+
+	return NewFilterComposer().Or(filters...).Done(), nil
+}
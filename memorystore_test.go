@@ -0,0 +1,303 @@
+package elemental_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"go.aporeto.io/elemental"
+	testmodel "go.aporeto.io/elemental/test/model"
+)
+
+type namespacedList struct {
+	*testmodel.List
+	Namespace string `json:"namespace"`
+	Propagate bool   `json:"propagate"`
+}
+
+func (o *namespacedList) GetNamespace() string          { return o.Namespace }
+func (o *namespacedList) SetNamespace(namespace string) { o.Namespace = namespace }
+func (o *namespacedList) GetProgagate() bool            { return o.Propagate }
+
+func makeMemoryStore() *elemental.MemoryStore {
+
+	newList := func(id string, name string, namespace string, propagate bool) *namespacedList {
+		l := testmodel.NewList()
+		l.ID = id
+		l.Name = name
+		return &namespacedList{List: l, Namespace: namespace, Propagate: propagate}
+	}
+
+	store := elemental.NewMemoryStore(testmodel.Manager())
+	store.Insert(
+		newList("1", "e", "/a", false),
+		newList("2", "d", "/a", true),
+		newList("3", "c", "/a/b", false),
+		newList("4", "b", "/a/b/c", false),
+		newList("5", "a", "/z", false),
+	)
+
+	return store
+}
+
+func memoryStoreResponseNames(t *testing.T, resp *elemental.Response) []string {
+
+	lists := testmodel.ListsList{}
+	if err := elemental.Decode(elemental.EncodingTypeJSON, resp.Data, &lists); err != nil {
+		t.Fatalf("unable to decode response: %s", err)
+	}
+
+	names := []string{}
+	for _, l := range lists {
+		names = append(names, l.Name)
+	}
+
+	return names
+}
+
+func TestMemoryStore_RetrieveMany(t *testing.T) {
+
+	tests := map[string]struct {
+		configure     func(*elemental.Request)
+		expectedNames []string
+		expectedTotal int
+		expectedNext  string
+	}{
+		"namespace": {
+			configure:     func(r *elemental.Request) {},
+			expectedNames: []string{"d", "e"},
+			expectedTotal: 2,
+		},
+		"recursive": {
+			configure:     func(r *elemental.Request) { r.Recursive = true },
+			expectedNames: []string{"b", "c", "d", "e"},
+			expectedTotal: 4,
+		},
+		"propagated": {
+			configure:     func(r *elemental.Request) { r.Namespace = "/a/b"; r.Propagated = true },
+			expectedNames: []string{"c", "d"},
+			expectedTotal: 2,
+		},
+		"filter": {
+			configure: func(r *elemental.Request) {
+				r.Recursive = true
+				r.Parameters["q"] = elemental.NewParameter(elemental.ParameterTypeString, `name in ["b", "e"]`)
+			},
+			expectedNames: []string{"b", "e"},
+			expectedTotal: 2,
+		},
+		"several filters": {
+			configure: func(r *elemental.Request) {
+				r.Recursive = true
+				r.Parameters["q"] = elemental.NewParameter(elemental.ParameterTypeString, `name == "b"`, `name == "c"`)
+			},
+			expectedNames: []string{"b", "c"},
+			expectedTotal: 2,
+		},
+		"order": {
+			configure:     func(r *elemental.Request) { r.Recursive = true; r.Order = []string{"-name"} },
+			expectedNames: []string{"e", "d", "c", "b"},
+			expectedTotal: 4,
+		},
+		"page": {
+			configure:     func(r *elemental.Request) { r.Recursive = true; r.Page = 2; r.PageSize = 3 },
+			expectedNames: []string{"e"},
+			expectedTotal: 4,
+		},
+		"page out of range": {
+			configure:     func(r *elemental.Request) { r.Recursive = true; r.Page = 3; r.PageSize = 3 },
+			expectedNames: []string{},
+			expectedTotal: 4,
+		},
+		"limit": {
+			configure:     func(r *elemental.Request) { r.Recursive = true; r.Limit = 2 },
+			expectedNames: []string{"b", "c"},
+			expectedTotal: 4,
+			expectedNext:  "3",
+		},
+		"after": {
+			configure:     func(r *elemental.Request) { r.Recursive = true; r.After = "3"; r.Limit = 1 },
+			expectedNames: []string{"d"},
+			expectedTotal: 4,
+			expectedNext:  "2",
+		},
+		"after last page": {
+			configure:     func(r *elemental.Request) { r.Recursive = true; r.After = "2"; r.Limit = 2 },
+			expectedNames: []string{"e"},
+			expectedTotal: 4,
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			req := elemental.NewRequest()
+			req.Operation = elemental.OperationRetrieveMany
+			req.Identity = testmodel.ListIdentity
+			req.Namespace = "/a"
+			req.Order = []string{"name"}
+			tc.configure(req)
+
+			resp, err := makeMemoryStore().Execute(req)
+			if err != nil {
+				t.Fatalf("did not expect to get an error, but received: %s", err)
+			}
+
+			if names := memoryStoreResponseNames(t, resp); !reflect.DeepEqual(names, tc.expectedNames) {
+				t.Errorf("unexpected objects\nexpected: %v\nactual:   %v", tc.expectedNames, names)
+			}
+			if resp.Count != len(tc.expectedNames) {
+				t.Errorf("unexpected count: expected %d, got %d", len(tc.expectedNames), resp.Count)
+			}
+			if resp.Total != tc.expectedTotal {
+				t.Errorf("unexpected total: expected %d, got %d", tc.expectedTotal, resp.Total)
+			}
+			if resp.Next != tc.expectedNext {
+				t.Errorf("unexpected next: expected %q, got %q", tc.expectedNext, resp.Next)
+			}
+		})
+	}
+}
+
+func TestMemoryStore_Info(t *testing.T) {
+
+	req := elemental.NewRequest()
+	req.Operation = elemental.OperationInfo
+	req.Identity = testmodel.ListIdentity
+	req.Namespace = "/a"
+	req.PageSize = 1
+
+	resp, err := makeMemoryStore().Execute(req)
+	if err != nil {
+		t.Fatalf("did not expect to get an error, but received: %s", err)
+	}
+	if resp.Count != 1 || resp.Total != 2 || resp.Data != nil {
+		t.Errorf("unexpected response: count %d, total %d, data %s", resp.Count, resp.Total, resp.Data)
+	}
+}
+
+func TestMemoryStore_CRUD(t *testing.T) {
+
+	store := elemental.NewMemoryStore(testmodel.Manager())
+
+	execute := func(operation elemental.Operation, id string, data string) (*elemental.Response, error) {
+		req := elemental.NewRequest()
+		req.Operation = operation
+		req.Identity = testmodel.ListIdentity
+		req.ObjectID = id
+		req.Data = []byte(data)
+		return store.Execute(req)
+	}
+
+	decode := func(resp *elemental.Response) *testmodel.List {
+		l := testmodel.NewList()
+		if err := elemental.Decode(elemental.EncodingTypeJSON, resp.Data, l); err != nil {
+			t.Fatalf("unable to decode response: %s", err)
+		}
+		return l
+	}
+
+	resp, err := execute(elemental.OperationCreate, "", `{"name": "groceries", "description": "weekly"}`)
+	if err != nil {
+		t.Fatalf("unable to create: %s", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	id := decode(resp).ID
+	if id == "" {
+		t.Fatalf("expected the object to have an identifier")
+	}
+
+	if _, err := execute(elemental.OperationCreate, "", `{"description": "no name"}`); err == nil {
+		t.Errorf("expected a validation error")
+	}
+
+	resp, err = execute(elemental.OperationRetrieve, id, "")
+	if err != nil {
+		t.Fatalf("unable to retrieve: %s", err)
+	}
+	if l := decode(resp); l.Name != "groceries" || l.Description != "weekly" {
+		t.Errorf("unexpected object: %s", resp.Data)
+	}
+
+	resp, err = execute(elemental.OperationPatch, id, `{"description": "daily"}`)
+	if err != nil {
+		t.Fatalf("unable to patch: %s", err)
+	}
+	if l := decode(resp); l.ID != id || l.Name != "groceries" || l.Description != "daily" {
+		t.Errorf("unexpected patched object: %s", resp.Data)
+	}
+
+	_, err = execute(elemental.OperationPatch, id, `{"name": ""}`)
+	if !elemental.IsErrorWithCode(err, http.StatusUnprocessableEntity) {
+		t.Errorf("expected a validation error, got %v", err)
+	}
+	if l := store.Objects(testmodel.ListIdentity)[0].(*testmodel.List); l.Name != "groceries" || l.Description != "daily" {
+		t.Errorf("the stored object has been modified by an invalid patch: %s %s", l.Name, l.Description)
+	}
+
+	resp, err = execute(elemental.OperationUpdate, id, `{"ID": "other", "name": "chores"}`)
+	if err != nil {
+		t.Fatalf("unable to update: %s", err)
+	}
+	if l := decode(resp); l.ID != id || l.Name != "chores" || l.Description != "" {
+		t.Errorf("unexpected updated object: %s", resp.Data)
+	}
+
+	if _, err = execute(elemental.OperationDelete, id, ""); err != nil {
+		t.Fatalf("unable to delete: %s", err)
+	}
+
+	_, err = execute(elemental.OperationRetrieve, id, "")
+	if eerr, ok := err.(elemental.Error); !ok || eerr.Code != http.StatusNotFound {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+
+	if len(store.Objects(testmodel.ListIdentity)) != 0 {
+		t.Errorf("expected the store to be empty")
+	}
+}
+
+func TestMemoryStore_Errors(t *testing.T) {
+
+	tests := map[string]struct {
+		configure    func(*elemental.Request)
+		expectedCode int
+	}{
+		"invisible object": {
+			configure:    func(r *elemental.Request) { r.Operation = elemental.OperationRetrieve; r.ObjectID = "3" },
+			expectedCode: http.StatusNotFound,
+		},
+		"invalid filter": {
+			configure: func(r *elemental.Request) {
+				r.Parameters["q"] = elemental.NewParameter(elemental.ParameterTypeString, `name ==`)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		"invalid cursor": {
+			configure:    func(r *elemental.Request) { r.After = "42" },
+			expectedCode: http.StatusBadRequest,
+		},
+		"invalid data": {
+			configure:    func(r *elemental.Request) { r.Operation = elemental.OperationCreate; r.Data = []byte("{") },
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for description, tc := range tests {
+		t.Run(description, func(t *testing.T) {
+
+			req := elemental.NewRequest()
+			req.Operation = elemental.OperationRetrieveMany
+			req.Identity = testmodel.ListIdentity
+			req.Namespace = "/a"
+			tc.configure(req)
+
+			_, err := makeMemoryStore().Execute(req)
+			if eerr, ok := err.(elemental.Error); !ok || eerr.Code != tc.expectedCode {
+				t.Errorf("expected an error with code %d, got %v", tc.expectedCode, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,465 @@
+package elemental
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// A ProtobufMarshaler is the interface of objects that can be encoded using the
+// EncodingTypeProtobuf encoding. elegen generates it for the models and their lists.
+type ProtobufMarshaler interface {
+	MarshalProtobuf() ([]byte, error)
+}
+
+// A ProtobufUnmarshaler is the interface of objects that can be decoded using the
+// EncodingTypeProtobuf encoding. elegen generates it for the models and their lists.
+type ProtobufUnmarshaler interface {
+	UnmarshalProtobuf(data []byte) error
+}
+
+// Protobuf wire types.
+const (
+	protobufWireVarint  = 0
+	protobufWireFixed64 = 1
+	protobufWireBytes   = 2
+	protobufWireFixed32 = 5
+)
+
+// A ProtobufEncoder writes the fields of a Protocol Buffers message. It is used by the
+// code generated by elegen to implement ProtobufMarshaler. Fields are always written,
+// even if they hold their zero value. The first error is returned by Bytes.
+type ProtobufEncoder struct {
+	buf []byte
+	err error
+}
+
+// NewProtobufEncoder returns a new ProtobufEncoder.
+func NewProtobufEncoder() *ProtobufEncoder {
+	return &ProtobufEncoder{}
+}
+
+// Bytes returns the encoded message, or the first error that occurred.
+func (e *ProtobufEncoder) Bytes() ([]byte, error) {
+
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	return e.buf, nil
+}
+
+// EncodeString writes a string field.
+func (e *ProtobufEncoder) EncodeString(field int, v string) {
+	e.tag(field, protobufWireBytes)
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+// EncodeBytes writes a bytes field.
+func (e *ProtobufEncoder) EncodeBytes(field int, v []byte) {
+	e.tag(field, protobufWireBytes)
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+// EncodeInt writes an int64 field.
+func (e *ProtobufEncoder) EncodeInt(field int, v int64) {
+	e.tag(field, protobufWireVarint)
+	e.buf = binary.AppendUvarint(e.buf, uint64(v))
+}
+
+// EncodeBool writes a bool field.
+func (e *ProtobufEncoder) EncodeBool(field int, v bool) {
+	e.tag(field, protobufWireVarint)
+	if v {
+		e.buf = append(e.buf, 1)
+	} else {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+// EncodeFloat writes a double field.
+func (e *ProtobufEncoder) EncodeFloat(field int, v float64) {
+	e.tag(field, protobufWireFixed64)
+	e.buf = binary.LittleEndian.AppendUint64(e.buf, math.Float64bits(v))
+}
+
+// EncodeTime writes a google.protobuf.Timestamp field.
+func (e *ProtobufEncoder) EncodeTime(field int, v time.Time) {
+
+	ts := NewProtobufEncoder()
+	if s := v.Unix(); s != 0 {
+		ts.EncodeInt(1, s)
+	}
+	if n := v.Nanosecond(); n != 0 {
+		ts.EncodeInt(2, int64(n))
+	}
+
+	e.EncodeBytes(field, ts.buf)
+}
+
+// EncodeMessage writes an embedded message field. Nothing is written if the message is nil.
+func (e *ProtobufEncoder) EncodeMessage(field int, v ProtobufMarshaler) {
+
+	if v == nil || e.err != nil {
+		return
+	}
+
+	data, err := v.MarshalProtobuf()
+	if err != nil {
+		e.err = err
+		return
+	}
+
+	e.EncodeBytes(field, data)
+}
+
+// EncodeJSON writes a bytes field holding the JSON encoding of the given value.
+// It is used for the values that have no Protocol Buffers equivalent.
+// Nothing is written if the value is nil.
+func (e *ProtobufEncoder) EncodeJSON(field int, v any) {
+
+	if v == nil || e.err != nil {
+		return
+	}
+
+	data, err := Encode(EncodingTypeJSON, v)
+	if err != nil {
+		e.err = err
+		return
+	}
+
+	e.EncodeBytes(field, data)
+}
+
+func (e *ProtobufEncoder) tag(field int, wireType int) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(field)<<3|uint64(wireType))
+}
+
+// A ProtobufDecoder reads the fields of a Protocol Buffers message. It is used by the
+// code generated by elegen to implement ProtobufUnmarshaler, as follows:
+//
+//	d := elemental.NewProtobufDecoder(data)
+//	for d.Next() {
+//	    switch d.Field() {
+//	    case 1:
+//	        o.Name = d.DecodeString()
+//	    default:
+//	        d.Skip()
+//	    }
+//	}
+//	return d.Err()
+//
+// Repeated fields are read one value at a time, so packed repeated fields are not supported.
+type ProtobufDecoder struct {
+	data     []byte
+	field    int
+	wireType int
+	err      error
+}
+
+// NewProtobufDecoder returns a new ProtobufDecoder reading the given message.
+func NewProtobufDecoder(data []byte) *ProtobufDecoder {
+	return &ProtobufDecoder{data: data}
+}
+
+// Next reads the next field. It returns false at the end of
+// the message, or if an error occurred.
+func (d *ProtobufDecoder) Next() bool {
+
+	if d.err != nil || len(d.data) == 0 {
+		return false
+	}
+
+	tag := d.varint()
+	if d.err != nil {
+		return false
+	}
+
+	d.field, d.wireType = int(tag>>3), int(tag&7)
+	if d.field == 0 {
+		d.fail("invalid field number 0")
+		return false
+	}
+
+	return true
+}
+
+// Field returns the number of the current field.
+func (d *ProtobufDecoder) Field() int {
+	return d.field
+}
+
+// Err returns the first error that occurred.
+func (d *ProtobufDecoder) Err() error {
+	return d.err
+}
+
+// DecodeString reads the current string field.
+func (d *ProtobufDecoder) DecodeString() string {
+	return string(d.DecodeBytes())
+}
+
+// DecodeBytes reads the current bytes field.
+func (d *ProtobufDecoder) DecodeBytes() []byte {
+
+	if !d.expect(protobufWireBytes) {
+		return nil
+	}
+
+	n := d.varint()
+	if d.err != nil {
+		return nil
+	}
+
+	if n > uint64(len(d.data)) {
+		d.fail("truncated field %d", d.field)
+		return nil
+	}
+
+	v := d.data[:n:n]
+	d.data = d.data[n:]
+
+	return v
+}
+
+// DecodeInt reads the current int64 field.
+func (d *ProtobufDecoder) DecodeInt() int64 {
+
+	if !d.expect(protobufWireVarint) {
+		return 0
+	}
+
+	return int64(d.varint())
+}
+
+// DecodeBool reads the current bool field.
+func (d *ProtobufDecoder) DecodeBool() bool {
+
+	if !d.expect(protobufWireVarint) {
+		return false
+	}
+
+	return d.varint() != 0
+}
+
+// DecodeFloat reads the current double field.
+func (d *ProtobufDecoder) DecodeFloat() float64 {
+
+	if !d.expect(protobufWireFixed64) {
+		return 0
+	}
+
+	if len(d.data) < 8 {
+		d.fail("truncated field %d", d.field)
+		return 0
+	}
+
+	v := math.Float64frombits(binary.LittleEndian.Uint64(d.data))
+	d.data = d.data[8:]
+
+	return v
+}
+
+// DecodeTime reads the current google.protobuf.Timestamp field.
+func (d *ProtobufDecoder) DecodeTime() time.Time {
+
+	data := d.DecodeBytes()
+	if d.err != nil {
+		return time.Time{}
+	}
+
+	var s, n int64
+
+	ts := NewProtobufDecoder(data)
+	for ts.Next() {
+		switch ts.Field() {
+		case 1:
+			s = ts.DecodeInt()
+		case 2:
+			n = ts.DecodeInt()
+		default:
+			ts.Skip()
+		}
+	}
+
+	if ts.err != nil {
+		d.err = ts.err
+		return time.Time{}
+	}
+
+	return time.Unix(s, n).UTC()
+}
+
+// DecodeMessage reads the current embedded message field into the given destination.
+func (d *ProtobufDecoder) DecodeMessage(dest ProtobufUnmarshaler) {
+
+	data := d.DecodeBytes()
+	if d.err != nil {
+		return
+	}
+
+	if err := dest.UnmarshalProtobuf(data); err != nil {
+		d.err = err
+	}
+}
+
+// DecodeJSON reads the current bytes field holding a JSON encoded value into the given destination.
+func (d *ProtobufDecoder) DecodeJSON(dest any) {
+
+	data := d.DecodeBytes()
+	if d.err != nil {
+		return
+	}
+
+	if err := Decode(EncodingTypeJSON, data, dest); err != nil {
+		d.err = err
+	}
+}
+
+// Skip skips the current field.
+func (d *ProtobufDecoder) Skip() {
+
+	switch d.wireType {
+	case protobufWireVarint:
+		d.varint()
+	case protobufWireBytes:
+		d.DecodeBytes()
+	case protobufWireFixed64, protobufWireFixed32:
+		size := 8
+		if d.wireType == protobufWireFixed32 {
+			size = 4
+		}
+		if len(d.data) < size {
+			d.fail("truncated field %d", d.field)
+			return
+		}
+		d.data = d.data[size:]
+	default:
+		d.fail("unsupported wire type %d for field %d", d.wireType, d.field)
+	}
+}
+
+func (d *ProtobufDecoder) expect(wireType int) bool {
+
+	if d.err != nil {
+		return false
+	}
+
+	if d.wireType != wireType {
+		d.fail("unexpected wire type %d for field %d", d.wireType, d.field)
+		return false
+	}
+
+	return true
+}
+
+func (d *ProtobufDecoder) varint() uint64 {
+
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail("invalid varint")
+		return 0
+	}
+
+	d.data = d.data[n:]
+
+	return v
+}
+
+func (d *ProtobufDecoder) fail(format string, args ...any) {
+	if d.err == nil {
+		d.err = fmt.Errorf("invalid protobuf message: "+format, args...)
+	}
+}
+
+// maxProtobufMessageSize is the maximum size of a message read by the stream decoder.
+const maxProtobufMessageSize = 64 << 20
+
+// NewProtobufCodec returns the Codec of the EncodingTypeProtobuf encoding. As Protocol Buffers messages
+// are not self describing, it can only encode ProtobufMarshalers and decode into ProtobufUnmarshalers,
+// so data cannot be converted from or to this encoding with Convert. Streams are sequences of messages
+// prefixed by their length, which cannot be greater than 64MiB.
+//
+// The codec is not registered by default, as Error and Errors, for instance, are not ProtobufMarshalers.
+// Once it is registered using RegisterCodec(EncodingTypeProtobuf, NewProtobufCodec()), the encoding can
+// be negotiated by EncodingFromHeaders, so all the objects sent using the negotiated encoding, including
+// errors and events, must support it.
+func NewProtobufCodec() Codec {
+	return protobufCodec{}
+}
+
+type protobufCodec struct{}
+
+func (protobufCodec) Encode(obj any) ([]byte, error) {
+
+	m, ok := obj.(ProtobufMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("%T is not a ProtobufMarshaler", obj)
+	}
+
+	return m.MarshalProtobuf()
+}
+
+func (protobufCodec) Decode(data []byte, dest any) error {
+
+	u, ok := dest.(ProtobufUnmarshaler)
+	if !ok {
+		return fmt.Errorf("%T is not a ProtobufUnmarshaler", dest)
+	}
+
+	return u.UnmarshalProtobuf(data)
+}
+
+func (c protobufCodec) NewStreamEncoder(writer io.Writer) (func(obj any) error, func()) {
+
+	return func(obj any) error {
+
+		data, err := c.Encode(obj)
+		if err != nil {
+			return err
+		}
+
+		if _, err := writer.Write(binary.AppendUvarint(nil, uint64(len(data)))); err != nil {
+			return err
+		}
+
+		_, err = writer.Write(data)
+		return err
+	}, func() {}
+}
+
+func (c protobufCodec) NewStreamDecoder(reader io.Reader) (func(dest any) error, func()) {
+
+	r := bufio.NewReader(reader)
+
+	return func(dest any) error {
+
+		n, err := binary.ReadUvarint(r)
+		if err != nil {
+			if errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+				return io.EOF
+			}
+			return err
+		}
+
+		if n > maxProtobufMessageSize {
+			return fmt.Errorf("invalid protobuf stream: message size %d is greater than the maximum of %d", n, maxProtobufMessageSize)
+		}
+
+		data := make([]byte, n)
+		if _, err := io.ReadFull(r, data); err != nil {
+			if errors.Is(err, io.EOF) {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+
+		return c.Decode(data, dest)
+	}, func() {}
+}
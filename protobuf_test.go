@@ -0,0 +1,430 @@
+package elemental
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// protobufTestObject implements the protobuf interfaces
+// the same way the code generated by elegen does.
+type protobufTestObject struct {
+	Name     string
+	Count    int
+	Ratio    float64
+	Enabled  bool
+	Date     time.Time
+	Tags     []string
+	Metadata map[string]any
+	Child    *protobufTestObject
+	Owner    List
+	Lists    ListsList
+	Counts   []int
+}
+
+func (o *protobufTestObject) MarshalProtobuf() ([]byte, error) {
+
+	e := NewProtobufEncoder()
+	e.EncodeString(1, o.Name)
+	e.EncodeInt(2, int64(o.Count))
+	e.EncodeFloat(3, o.Ratio)
+	e.EncodeBool(4, o.Enabled)
+	e.EncodeTime(5, o.Date)
+	for _, v := range o.Tags {
+		e.EncodeString(6, v)
+	}
+	e.EncodeJSON(7, o.Metadata)
+	if o.Child != nil {
+		e.EncodeMessage(8, o.Child)
+	}
+	e.EncodeMessage(9, &o.Owner)
+	for _, v := range o.Lists {
+		if v != nil {
+			e.EncodeMessage(10, v)
+		}
+	}
+	for _, v := range o.Counts {
+		e.EncodeInt(11, int64(v))
+	}
+
+	return e.Bytes()
+}
+
+func (o *protobufTestObject) UnmarshalProtobuf(data []byte) error {
+
+	o.Tags = nil
+	o.Child = nil
+	o.Lists = nil
+	o.Counts = nil
+
+	d := NewProtobufDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		case 1:
+			o.Name = d.DecodeString()
+		case 2:
+			o.Count = int(d.DecodeInt())
+		case 3:
+			o.Ratio = d.DecodeFloat()
+		case 4:
+			o.Enabled = d.DecodeBool()
+		case 5:
+			o.Date = d.DecodeTime()
+		case 6:
+			o.Tags = append(o.Tags, d.DecodeString())
+		case 7:
+			d.DecodeJSON(&o.Metadata)
+		case 8:
+			o.Child = &protobufTestObject{}
+			d.DecodeMessage(o.Child)
+		case 9:
+			d.DecodeMessage(&o.Owner)
+		case 10:
+			v := NewList()
+			d.DecodeMessage(v)
+			o.Lists = append(o.Lists, v)
+		case 11:
+			o.Counts = append(o.Counts, int(d.DecodeInt()))
+		default:
+			d.Skip()
+		}
+	}
+
+	return d.Err()
+}
+
+// registerProtobufCodec registers the protobuf codec
+// for the duration of the given test.
+func registerProtobufCodec(t *testing.T) {
+
+	RegisterCodec(EncodingTypeProtobuf, NewProtobufCodec())
+	t.Cleanup(func() { delete(codecs, EncodingTypeProtobuf) })
+}
+
+func TestProtobuf_Registration(t *testing.T) {
+
+	header := http.Header{}
+	header.Set("Content-Type", string(EncodingTypeProtobuf))
+	header.Set("Accept", string(EncodingTypeProtobuf))
+
+	if _, _, err := EncodingFromHeaders(header); err == nil {
+		t.Errorf("expected an error as the protobuf codec is not registered by default")
+	}
+
+	registerProtobufCodec(t)
+
+	read, write, err := EncodingFromHeaders(header)
+	if err != nil {
+		t.Fatalf("did not expect to get an error, but received: %s", err)
+	}
+
+	if read != EncodingTypeProtobuf || write != EncodingTypeProtobuf {
+		t.Errorf("unexpected encodings: %s %s", read, write)
+	}
+}
+
+func TestProtobuf_EncodeDecode(t *testing.T) {
+
+	registerProtobufCodec(t)
+
+	now := time.Date(2024, 3, 4, 5, 6, 7, 123456789, time.UTC)
+
+	tests := map[string]struct {
+		obj *protobufTestObject
+	}{
+		"empty": {
+			obj: &protobufTestObject{Date: time.Unix(0, 0).UTC()},
+		},
+		"full": {
+			obj: &protobufTestObject{
+				Name:     "hello",
+				Count:    -42,
+				Ratio:    3.14,
+				Enabled:  true,
+				Date:     now,
+				Tags:     []string{"a", "b"},
+				Metadata: map[string]any{"key": "value"},
+				Child: &protobufTestObject{
+					Name: "child",
+					Date: now.Add(-time.Hour),
+				},
+				Owner:  List{ID: "1", Name: "owner", Date: now, Slice: []string{"a"}},
+				Lists:  ListsList{{ID: "2", Name: "first", Date: now, ModelVersion: 1}, {ID: "3", Name: "second", Date: now, ModelVersion: 1}},
+				Counts: []int{-1, 0, 42},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			data, err := Encode(EncodingTypeProtobuf, tt.obj)
+			if err != nil {
+				t.Fatalf("unable to encode: %s", err)
+			}
+
+			obj := &protobufTestObject{}
+			if err := Decode(EncodingTypeProtobuf, data, obj); err != nil {
+				t.Fatalf("unable to decode: %s", err)
+			}
+
+			if !reflect.DeepEqual(obj, tt.obj) {
+				t.Errorf("unexpected decoded object:\n got: %#v\nwant: %#v", obj, tt.obj)
+			}
+		})
+	}
+}
+
+func TestProtobuf_GeneratedModels(t *testing.T) {
+
+	registerProtobufCodec(t)
+
+	now := time.Date(2024, 3, 4, 5, 6, 7, 123456789, time.UTC)
+
+	tests := map[string]struct {
+		obj  any
+		dest any
+	}{
+		"model with an enum": {
+			obj:  &Task{ID: "1", Name: "task", Description: "a task", Status: TaskStatusPROGRESS},
+			dest: &Task{},
+		},
+		"model with an unknown enum value": {
+			obj:  &Task{ID: "1", Name: "task", Status: TaskStatusValue("")},
+			dest: &Task{Status: TaskStatusDONE},
+		},
+		"model with a time and a list": {
+			obj:  &List{ID: "1", Name: "list", Date: now, Slice: []string{"a", "b"}, ParentID: "2", ParentType: "user"},
+			dest: &List{Slice: []string{"stale"}},
+		},
+		"list of models": {
+			obj: &TasksList{
+				{ID: "1", Name: "first", Status: TaskStatusDONE, ModelVersion: 1},
+				{ID: "2", Name: "second", Status: TaskStatusTODO, ModelVersion: 1},
+			},
+			dest: &TasksList{},
+		},
+		"empty root": {
+			obj:  &Root{},
+			dest: &Root{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			data, err := Encode(EncodingTypeProtobuf, tt.obj)
+			if err != nil {
+				t.Fatalf("unable to encode: %s", err)
+			}
+
+			if err := Decode(EncodingTypeProtobuf, data, tt.dest); err != nil {
+				t.Fatalf("unable to decode: %s", err)
+			}
+
+			if !reflect.DeepEqual(tt.dest, tt.obj) {
+				t.Errorf("unexpected decoded object:\n got: %#v\nwant: %#v", tt.dest, tt.obj)
+			}
+		})
+	}
+}
+
+func TestProtobuf_GeneratedModelsZeroValues(t *testing.T) {
+
+	registerProtobufCodec(t)
+
+	// proto3 encoders leave out the fields holding a zero value.
+	e := NewProtobufEncoder()
+	e.EncodeString(1, "1")
+	e.EncodeString(7, "jdoe")
+
+	user, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("unable to encode: %s", err)
+	}
+
+	e = NewProtobufEncoder()
+	e.EncodeString(3, "task")
+
+	task, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("unable to encode: %s", err)
+	}
+
+	e = NewProtobufEncoder()
+	e.EncodeBytes(1, task)
+
+	tasks, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("unable to encode: %s", err)
+	}
+
+	tests := map[string]struct {
+		data []byte
+		dest any
+		want any
+	}{
+		"missing string and bool": {
+			data: user,
+			dest: &User{ID: "2", FirstName: "John", LastName: "Doe", Archived: true},
+			want: &User{ID: "1", UserName: "jdoe"},
+		},
+		"missing enum": {
+			data: task,
+			dest: NewTask(),
+			want: &Task{Name: "task", ModelVersion: 1},
+		},
+		"missing enum in a list": {
+			data: tasks,
+			dest: &TasksList{},
+			want: &TasksList{{Name: "task", ModelVersion: 1}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			if err := Decode(EncodingTypeProtobuf, tt.data, tt.dest); err != nil {
+				t.Fatalf("unable to decode: %s", err)
+			}
+
+			if !reflect.DeepEqual(tt.dest, tt.want) {
+				t.Errorf("unexpected decoded object:\n got: %#v\nwant: %#v", tt.dest, tt.want)
+			}
+		})
+	}
+}
+
+func TestProtobuf_UnknownFields(t *testing.T) {
+
+	e := NewProtobufEncoder()
+	e.EncodeString(1, "hello")
+	e.EncodeInt(100, 1)
+	e.EncodeFloat(101, 1)
+	e.EncodeBytes(102, []byte("x"))
+	e.EncodeString(1, "world")
+
+	data, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("unable to encode: %s", err)
+	}
+
+	obj := &protobufTestObject{}
+	if err := obj.UnmarshalProtobuf(data); err != nil {
+		t.Fatalf("unable to decode: %s", err)
+	}
+
+	if obj.Name != "world" {
+		t.Errorf("unexpected name: %s", obj.Name)
+	}
+}
+
+func TestProtobuf_Errors(t *testing.T) {
+
+	registerProtobufCodec(t)
+
+	tests := map[string]struct {
+		data []byte
+		err  string
+	}{
+		"truncated string": {
+			data: []byte{0x0a, 0x05, 'a'},
+			err:  "unable to decode application/x-protobuf: invalid protobuf message: truncated field 1",
+		},
+		"wrong wire type": {
+			data: []byte{0x08, 0x01},
+			err:  "unable to decode application/x-protobuf: invalid protobuf message: unexpected wire type 0 for field 1",
+		},
+		"invalid varint": {
+			data: []byte{0x10, 0xff},
+			err:  "unable to decode application/x-protobuf: invalid protobuf message: invalid varint",
+		},
+		"field 0": {
+			data: []byte{0x00, 0x01},
+			err:  "unable to decode application/x-protobuf: invalid protobuf message: invalid field number 0",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := Decode(EncodingTypeProtobuf, tt.data, &protobufTestObject{})
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	t.Run("not a marshaler", func(t *testing.T) {
+		if _, err := Encode(EncodingTypeProtobuf, NewError("title", "description", "subject", 400)); err == nil {
+			t.Errorf("expected an error")
+		}
+		if err := Decode(EncodingTypeProtobuf, nil, &Error{}); err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+
+	t.Run("convert", func(t *testing.T) {
+		data, _ := Encode(EncodingTypeJSON, &List{Name: "hello"})
+		if _, err := Convert(EncodingTypeJSON, EncodingTypeProtobuf, data); err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+}
+
+func TestProtobuf_Stream(t *testing.T) {
+
+	registerProtobufCodec(t)
+
+	buf := bytes.NewBuffer(nil)
+
+	encode, dispose := MakeStreamEncoder(EncodingTypeProtobuf, buf)
+	defer dispose()
+
+	for _, name := range []string{"a", "", "c"} {
+		if err := encode(&protobufTestObject{Name: name}); err != nil {
+			t.Fatalf("unable to encode: %s", err)
+		}
+	}
+
+	decode, dispose := MakeStreamDecoder(EncodingTypeProtobuf, bytes.NewReader(buf.Bytes()))
+	defer dispose()
+
+	var names []string
+	for {
+		obj := &protobufTestObject{}
+		err := decode(obj)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unable to decode: %s", err)
+		}
+		names = append(names, obj.Name)
+	}
+
+	if !reflect.DeepEqual(names, []string{"a", "", "c"}) {
+		t.Errorf("unexpected decoded objects: %v", names)
+	}
+
+	decode, dispose = MakeStreamDecoder(EncodingTypeProtobuf, bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	defer dispose()
+
+	var err error
+	for err == nil {
+		err = decode(&protobufTestObject{})
+	}
+	if err == io.EOF {
+		t.Errorf("expected a truncated stream error")
+	}
+
+	decode, dispose = MakeStreamDecoder(EncodingTypeProtobuf, bytes.NewReader(binary.AppendUvarint(nil, 1<<40)))
+	defer dispose()
+
+	if err := decode(&protobufTestObject{}); err == nil || err.Error() != "unable to decode application/x-protobuf: invalid protobuf stream: message size 1099511627776 is greater than the maximum of 67108864" {
+		t.Errorf("unexpected error for a message that is too large: %v", err)
+	}
+}
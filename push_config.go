@@ -37,19 +37,9 @@ type PushConfig struct {
 	// parsedIdentityFilters holds the parsed `IdentityFilters` to avoid re-parsing the configured filters on each push
 	// event that is using the same config.
 	parsedIdentityFilters map[string]*Filter
-}
 
-// unsupportedComparators is the list of comparators that are currently not handled by the elemental API 'MatchesFilter'
-// which is utilized for providing fine-grained identity filtering for websocket clients
-var unsupportedComparators = []FilterComparator{
-	GreaterComparator,
-	GreaterOrEqualComparator,
-	LesserComparator,
-	LesserOrEqualComparator,
-	InComparator,
-	NotInComparator,
-	ContainComparator,
-	NotContainComparator,
+	// compiledIdentityFilters holds the compiled `parsedIdentityFilters` to allow fast matching on each push event.
+	compiledIdentityFilters map[string]*CompiledFilter
 }
 
 // NewPushFilter returns a new PushFilter. NewPushFilter is now aliased to NewPushConfig. This was done for backwards
@@ -111,15 +101,16 @@ func (pc *PushConfig) FilterIdentity(identityName string, eventTypes ...EventTyp
 // ParseIdentityFilters parses the configured PushConfig's 'IdentityFilters' attribute to elemental filters.
 // The parsed filters will then be stored in the non-exposed 'parsedIdentityFilters' attribute of PushConfig. This is useful
 // for clients that wish the utilize the same filter multiple times without having to incur the overhead of parsing each time.
+// The filters compiled by CompileIdentityFilters are discarded, as they may not match the parsed filters anymore.
 //
 // An error is returned in following situations:
 //   - when a filter is declared on an identity that is not defined in the PushConfig's 'Identities' attribute
 //   - when a filter cannot be parsed into an elemental.Filter
 func (pc *PushConfig) ParseIdentityFilters() error {
 
-	if pc.parsedIdentityFilters == nil {
-		pc.parsedIdentityFilters = map[string]*Filter{}
-	}
+	// the compiled filters were compiled from the previous parsed filters.
+	pc.compiledIdentityFilters = nil
+	pc.parsedIdentityFilters = make(map[string]*Filter, len(pc.IdentityFilters))
 
 	for identity, unparsedFilter := range pc.IdentityFilters {
 		if _, found := pc.Identities[identity]; !found {
@@ -128,11 +119,7 @@ func (pc *PushConfig) ParseIdentityFilters() error {
 			return fmt.Errorf("elemental: cannot declare an identity filter on %q as that was not declared in 'Identities'", identity)
 		}
 
-		filter, err := NewFilterParser(unparsedFilter,
-			// blacklist unsupported comparators so socket can either be closed or if the client supports error events, an
-			// error can be emitted.
-			OptUnsupportedComparators(unsupportedComparators),
-		).Parse()
+		filter, err := NewFilterParser(unparsedFilter).Parse()
 		if err != nil {
 			// in the event an error occurs we zero out the parsed identities to avoid having a partially set of parsed identities
 			pc.parsedIdentityFilters = map[string]*Filter{}
@@ -145,6 +132,42 @@ func (pc *PushConfig) ParseIdentityFilters() error {
 	return nil
 }
 
+// CompileIdentityFilters compiles the configured PushConfig's 'IdentityFilters' against the models of the given manager.
+// The filters are always parsed again first, so changes made to 'IdentityFilters' are taken into account. The compiled filters can then be
+// retrieved using CompiledFilterForIdentity and are much faster to evaluate than using MatchesFilter on each push event.
+//
+// An error is returned in following situations:
+//   - when the filters cannot be parsed
+//   - when the identity is unknown to the manager or its model is not an elemental.AttributeSpecifiable
+//   - when a filter cannot be compiled against the identity's model
+func (pc *PushConfig) CompileIdentityFilters(manager ModelManager) error {
+
+	if err := pc.ParseIdentityFilters(); err != nil {
+		return err
+	}
+
+	compiled := make(map[string]*CompiledFilter, len(pc.parsedIdentityFilters))
+
+	for identity, filter := range pc.parsedIdentityFilters {
+
+		prototype, ok := manager.Identifiable(manager.IdentityFromName(identity)).(AttributeSpecifiable)
+		if !ok {
+			return fmt.Errorf("elemental: unable to compile filter for identity %q: unknown identity or not an attribute specifiable", identity)
+		}
+
+		cf, err := CompileFilter(filter, prototype)
+		if err != nil {
+			return fmt.Errorf("elemental: unable to compile filter for identity %q: %w", identity, err)
+		}
+
+		compiled[identity] = cf
+	}
+
+	pc.compiledIdentityFilters = compiled
+
+	return nil
+}
+
 // IsFilteredOut returns true if the given Identity is not part of the PushConfig's Identity mapping
 func (pc *PushConfig) IsFilteredOut(identityName string, eventType EventType) bool {
 
@@ -187,6 +210,19 @@ func (pc *PushConfig) FilterForIdentity(identityName string) (*Filter, bool) {
 	return filter, found
 }
 
+// CompiledFilterForIdentity returns the associated compiled fine-grained filter for the given identity. In the event that
+// CompileIdentityFilters has not been called, or no fine-grained filter has been configured for the identity, the second
+// return value (a boolean), will be set to false.
+func (pc *PushConfig) CompiledFilterForIdentity(identityName string) (*CompiledFilter, bool) {
+
+	if pc.compiledIdentityFilters == nil {
+		return nil, false
+	}
+
+	filter, found := pc.compiledIdentityFilters[identityName]
+	return filter, found
+}
+
 // Duplicate duplicates the PushConfig.
 func (pc *PushConfig) Duplicate() *PushConfig {
 
@@ -204,6 +240,13 @@ func (pc *PushConfig) Duplicate() *PushConfig {
 		config.parsedIdentityFilters[id] = f
 	}
 
+	if pc.compiledIdentityFilters != nil {
+		config.compiledIdentityFilters = make(map[string]*CompiledFilter, len(pc.compiledIdentityFilters))
+		for id, f := range pc.compiledIdentityFilters {
+			config.compiledIdentityFilters[id] = f
+		}
+	}
+
 	for k, v := range pc.Params {
 		config.SetParameter(k, v...)
 	}
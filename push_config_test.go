@@ -241,7 +241,7 @@ func TestPushConfig_ParseIdentityFilters(t *testing.T) {
 			expectedFilters: map[string]*Filter{},
 			expectedError:   true,
 		},
-		"should result in error if an identity filter uses the unsupported comparator '>'": {
+		"should successfully parse an identity filter using the comparator '>'": {
 			pushConfig: &PushConfig{
 				Identities: map[string][]EventType{
 					"identity_one": {
@@ -258,11 +258,12 @@ func TestPushConfig_ParseIdentityFilters(t *testing.T) {
 						String(),
 				},
 			},
-			// the push config's parsed identities attribute should be zero'd out
-			expectedFilters: map[string]*Filter{},
-			expectedError:   true,
+			expectedFilters: map[string]*Filter{
+				"identity_one": NewFilterComposer().WithKey("someAttr").GreaterThan(int64(1)).Done(),
+			},
+			expectedError: false,
 		},
-		"should result in error if an identity filter uses the unsupported comparator '>='": {
+		"should successfully parse an identity filter using the comparator '>='": {
 			pushConfig: &PushConfig{
 				Identities: map[string][]EventType{
 					"identity_one": {
@@ -279,11 +280,12 @@ func TestPushConfig_ParseIdentityFilters(t *testing.T) {
 						String(),
 				},
 			},
-			// the push config's parsed identities attribute should be zero'd out
-			expectedFilters: map[string]*Filter{},
-			expectedError:   true,
+			expectedFilters: map[string]*Filter{
+				"identity_one": NewFilterComposer().WithKey("someAttr").GreaterOrEqualThan(int64(1)).Done(),
+			},
+			expectedError: false,
 		},
-		"should result in error if an identity filter uses the unsupported comparator '<'": {
+		"should successfully parse an identity filter using the comparator '<'": {
 			pushConfig: &PushConfig{
 				Identities: map[string][]EventType{
 					"identity_one": {
@@ -300,11 +302,12 @@ func TestPushConfig_ParseIdentityFilters(t *testing.T) {
 						String(),
 				},
 			},
-			// the push config's parsed identities attribute should be zero'd out
-			expectedFilters: map[string]*Filter{},
-			expectedError:   true,
+			expectedFilters: map[string]*Filter{
+				"identity_one": NewFilterComposer().WithKey("someAttr").LesserThan(int64(1)).Done(),
+			},
+			expectedError: false,
 		},
-		"should result in error if an identity filter uses the unsupported comparator '<='": {
+		"should successfully parse an identity filter using the comparator '<='": {
 			pushConfig: &PushConfig{
 				Identities: map[string][]EventType{
 					"identity_one": {
@@ -321,11 +324,12 @@ func TestPushConfig_ParseIdentityFilters(t *testing.T) {
 						String(),
 				},
 			},
-			// the push config's parsed identities attribute should be zero'd out
-			expectedFilters: map[string]*Filter{},
-			expectedError:   true,
+			expectedFilters: map[string]*Filter{
+				"identity_one": NewFilterComposer().WithKey("someAttr").LesserOrEqualThan(int64(1)).Done(),
+			},
+			expectedError: false,
 		},
-		"should result in error if an identity filter uses the unsupported comparator 'in": {
+		"should successfully parse an identity filter using the comparator 'in'": {
 			pushConfig: &PushConfig{
 				Identities: map[string][]EventType{
 					"identity_one": {
@@ -342,11 +346,12 @@ func TestPushConfig_ParseIdentityFilters(t *testing.T) {
 						String(),
 				},
 			},
-			// the push config's parsed identities attribute should be zero'd out
-			expectedFilters: map[string]*Filter{},
-			expectedError:   true,
+			expectedFilters: map[string]*Filter{
+				"identity_one": NewFilterComposer().WithKey("someAttr").In(int64(1), int64(2), int64(3)).Done(),
+			},
+			expectedError: false,
 		},
-		"should result in error if an identity filter uses the unsupported comparator 'not in": {
+		"should successfully parse an identity filter using the comparator 'not in'": {
 			pushConfig: &PushConfig{
 				Identities: map[string][]EventType{
 					"identity_one": {
@@ -363,11 +368,12 @@ func TestPushConfig_ParseIdentityFilters(t *testing.T) {
 						String(),
 				},
 			},
-			// the push config's parsed identities attribute should be zero'd out
-			expectedFilters: map[string]*Filter{},
-			expectedError:   true,
+			expectedFilters: map[string]*Filter{
+				"identity_one": NewFilterComposer().WithKey("someAttr").NotIn(int64(1), int64(2), int64(3)).Done(),
+			},
+			expectedError: false,
 		},
-		"should result in error if an identity filter uses the unsupported comparator 'contains": {
+		"should successfully parse an identity filter using the comparator 'contains'": {
 			pushConfig: &PushConfig{
 				Identities: map[string][]EventType{
 					"identity_one": {
@@ -384,11 +390,12 @@ func TestPushConfig_ParseIdentityFilters(t *testing.T) {
 						String(),
 				},
 			},
-			// the push config's parsed identities attribute should be zero'd out
-			expectedFilters: map[string]*Filter{},
-			expectedError:   true,
+			expectedFilters: map[string]*Filter{
+				"identity_one": NewFilterComposer().WithKey("someAttr").Contains(int64(1), int64(2), int64(3)).Done(),
+			},
+			expectedError: false,
 		},
-		"should result in error if an identity filter uses the unsupported comparator 'not contains": {
+		"should successfully parse an identity filter using the comparator 'not contains'": {
 			pushConfig: &PushConfig{
 				Identities: map[string][]EventType{
 					"identity_one": {
@@ -405,9 +412,10 @@ func TestPushConfig_ParseIdentityFilters(t *testing.T) {
 						String(),
 				},
 			},
-			// the push config's parsed identities attribute should be zero'd out
-			expectedFilters: map[string]*Filter{},
-			expectedError:   true,
+			expectedFilters: map[string]*Filter{
+				"identity_one": NewFilterComposer().WithKey("someAttr").NotContains(int64(1), int64(2), int64(3)).Done(),
+			},
+			expectedError: false,
 		},
 	}
 
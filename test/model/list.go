@@ -5,6 +5,7 @@ package testmodel
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mitchellh/copystructure"
@@ -83,42 +84,75 @@ func (o ListsList) Version() int {
 	return 1
 }
 
+// MarshalProtobuf implements the elemental.ProtobufMarshaler interface.
+func (o ListsList) MarshalProtobuf() ([]byte, error) {
+
+	e := elemental.NewProtobufEncoder()
+	for _, obj := range o {
+		if obj != nil {
+			e.EncodeMessage(1, obj)
+		}
+	}
+
+	return e.Bytes()
+}
+
+// UnmarshalProtobuf implements the elemental.ProtobufUnmarshaler interface.
+func (o *ListsList) UnmarshalProtobuf(data []byte) error {
+
+	*o = ListsList{}
+
+	d := elemental.NewProtobufDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		case 1:
+			obj := NewList()
+			d.DecodeMessage(obj)
+			*o = append(*o, obj)
+		default:
+			d.Skip()
+		}
+	}
+
+	return d.Err()
+}
+
 // List represents the model of a list
 type List struct {
 	// The identifier.
-	ID string `json:"ID" msgpack:"ID" bson:"-" mapstructure:"ID,omitempty"`
+	ID string `json:"ID" msgpack:"ID" cbor:"ID" bson:"-" mapstructure:"ID,omitempty"`
 
 	// This attribute is creation only.
-	CreationOnly string `json:"creationOnly" msgpack:"creationOnly" bson:"creationonly" mapstructure:"creationOnly,omitempty"`
+	CreationOnly string `json:"creationOnly" msgpack:"creationOnly" cbor:"creationOnly" bson:"creationonly" mapstructure:"creationOnly,omitempty"`
 
 	// The date.
-	Date time.Time `json:"date" msgpack:"date" bson:"date" mapstructure:"date,omitempty"`
+	Date time.Time `json:"date" msgpack:"date" cbor:"date" bson:"date" mapstructure:"date,omitempty"`
 
 	// The description.
-	Description string `json:"description" msgpack:"description" bson:"description" mapstructure:"description,omitempty"`
+	Description string `json:"description" msgpack:"description" cbor:"description" bson:"description" mapstructure:"description,omitempty"`
 
 	// The name.
-	Name string `json:"name" msgpack:"name" bson:"name" mapstructure:"name,omitempty"`
+	Name string `json:"name" msgpack:"name" cbor:"name" bson:"name" mapstructure:"name,omitempty"`
 
 	// The identifier of the parent of the object.
-	ParentID string `json:"parentID" msgpack:"parentID" bson:"parentid" mapstructure:"parentID,omitempty"`
+	ParentID string `json:"parentID" msgpack:"parentID" cbor:"parentID" bson:"parentid" mapstructure:"parentID,omitempty"`
 
 	// The type of the parent of the object.
-	ParentType string `json:"parentType" msgpack:"parentType" bson:"parenttype" mapstructure:"parentType,omitempty"`
+	ParentType string `json:"parentType" msgpack:"parentType" cbor:"parentType" bson:"parenttype" mapstructure:"parentType,omitempty"`
 
 	// This attribute is readonly.
-	ReadOnly string `json:"readOnly" msgpack:"readOnly" bson:"readonly" mapstructure:"readOnly,omitempty"`
+	ReadOnly string `json:"readOnly" msgpack:"readOnly" cbor:"readOnly" bson:"readonly" mapstructure:"readOnly,omitempty"`
 
 	// This attribute is secret.
-	Secret string `json:"secret" msgpack:"secret" bson:"secret" mapstructure:"secret,omitempty"`
+	Secret string `json:"secret" msgpack:"secret" cbor:"secret" bson:"secret" mapstructure:"secret,omitempty"`
 
 	// this is a slice.
-	Slice []string `json:"slice" msgpack:"slice" bson:"slice" mapstructure:"slice,omitempty"`
+	Slice []string `json:"slice" msgpack:"slice" cbor:"slice" bson:"slice" mapstructure:"slice,omitempty"`
 
 	// This attribute is not exposed.
-	Unexposed string `json:"-" msgpack:"-" bson:"unexposed" mapstructure:"-,omitempty"`
+	Unexposed string `json:"-" msgpack:"-" cbor:"-" bson:"unexposed" mapstructure:"-,omitempty"`
 
-	ModelVersion int `json:"-" msgpack:"-" bson:"_modelversion"`
+	ModelVersion int `json:"-" msgpack:"-" cbor:"-" bson:"_modelversion"`
 }
 
 // NewList returns a new *List
@@ -207,6 +241,71 @@ func (o *List) UnmarshalBSON(raw []byte) error {
 	return nil
 }
 
+// MarshalProtobuf implements the elemental.ProtobufMarshaler interface.
+func (o *List) MarshalProtobuf() ([]byte, error) {
+
+	e := elemental.NewProtobufEncoder()
+	e.EncodeString(1, o.ID)
+	e.EncodeString(2, o.CreationOnly)
+	e.EncodeTime(3, o.Date)
+	e.EncodeString(4, o.Description)
+	e.EncodeString(5, o.Name)
+	e.EncodeString(6, o.ParentID)
+	e.EncodeString(7, o.ParentType)
+	e.EncodeString(8, o.ReadOnly)
+	e.EncodeString(9, o.Secret)
+	for _, v := range o.Slice {
+		e.EncodeString(10, v)
+	}
+
+	return e.Bytes()
+}
+
+// UnmarshalProtobuf implements the elemental.ProtobufUnmarshaler interface.
+func (o *List) UnmarshalProtobuf(data []byte) error {
+
+	o.ID = ""
+	o.CreationOnly = ""
+	o.Date = time.Time{}
+	o.Description = ""
+	o.Name = ""
+	o.ParentID = ""
+	o.ParentType = ""
+	o.ReadOnly = ""
+	o.Secret = ""
+	o.Slice = nil
+
+	d := elemental.NewProtobufDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		case 1:
+			o.ID = d.DecodeString()
+		case 2:
+			o.CreationOnly = d.DecodeString()
+		case 3:
+			o.Date = d.DecodeTime()
+		case 4:
+			o.Description = d.DecodeString()
+		case 5:
+			o.Name = d.DecodeString()
+		case 6:
+			o.ParentID = d.DecodeString()
+		case 7:
+			o.ParentType = d.DecodeString()
+		case 8:
+			o.ReadOnly = d.DecodeString()
+		case 9:
+			o.Secret = d.DecodeString()
+		case 10:
+			o.Slice = append(o.Slice, d.DecodeString())
+		default:
+			d.Skip()
+		}
+	}
+
+	return d.Err()
+}
+
 // Version returns the hardcoded version of the model.
 func (o *List) Version() int {
 
@@ -434,6 +533,11 @@ func (o *List) ValueForAttribute(name string) any {
 		return o.Unexposed
 	}
 
+	// The name may be a dotted path to a nested value
+	if strings.Contains(name, ".") {
+		return elemental.ValueForPath(o, name)
+	}
+
 	return nil
 }
 
@@ -803,39 +907,39 @@ func (o SparseListsList) Version() int {
 // SparseList represents the sparse version of a list.
 type SparseList struct {
 	// The identifier.
-	ID *string `json:"ID,omitempty" msgpack:"ID,omitempty" bson:"-" mapstructure:"ID,omitempty"`
+	ID *string `json:"ID,omitempty" msgpack:"ID,omitempty" cbor:"ID,omitempty" bson:"-" mapstructure:"ID,omitempty"`
 
 	// This attribute is creation only.
-	CreationOnly *string `json:"creationOnly,omitempty" msgpack:"creationOnly,omitempty" bson:"creationonly,omitempty" mapstructure:"creationOnly,omitempty"`
+	CreationOnly *string `json:"creationOnly,omitempty" msgpack:"creationOnly,omitempty" cbor:"creationOnly,omitempty" bson:"creationonly,omitempty" mapstructure:"creationOnly,omitempty"`
 
 	// The date.
-	Date *time.Time `json:"date,omitempty" msgpack:"date,omitempty" bson:"date,omitempty" mapstructure:"date,omitempty"`
+	Date *time.Time `json:"date,omitempty" msgpack:"date,omitempty" cbor:"date,omitempty" bson:"date,omitempty" mapstructure:"date,omitempty"`
 
 	// The description.
-	Description *string `json:"description,omitempty" msgpack:"description,omitempty" bson:"description,omitempty" mapstructure:"description,omitempty"`
+	Description *string `json:"description,omitempty" msgpack:"description,omitempty" cbor:"description,omitempty" bson:"description,omitempty" mapstructure:"description,omitempty"`
 
 	// The name.
-	Name *string `json:"name,omitempty" msgpack:"name,omitempty" bson:"name,omitempty" mapstructure:"name,omitempty"`
+	Name *string `json:"name,omitempty" msgpack:"name,omitempty" cbor:"name,omitempty" bson:"name,omitempty" mapstructure:"name,omitempty"`
 
 	// The identifier of the parent of the object.
-	ParentID *string `json:"parentID,omitempty" msgpack:"parentID,omitempty" bson:"parentid,omitempty" mapstructure:"parentID,omitempty"`
+	ParentID *string `json:"parentID,omitempty" msgpack:"parentID,omitempty" cbor:"parentID,omitempty" bson:"parentid,omitempty" mapstructure:"parentID,omitempty"`
 
 	// The type of the parent of the object.
-	ParentType *string `json:"parentType,omitempty" msgpack:"parentType,omitempty" bson:"parenttype,omitempty" mapstructure:"parentType,omitempty"`
+	ParentType *string `json:"parentType,omitempty" msgpack:"parentType,omitempty" cbor:"parentType,omitempty" bson:"parenttype,omitempty" mapstructure:"parentType,omitempty"`
 
 	// This attribute is readonly.
-	ReadOnly *string `json:"readOnly,omitempty" msgpack:"readOnly,omitempty" bson:"readonly,omitempty" mapstructure:"readOnly,omitempty"`
+	ReadOnly *string `json:"readOnly,omitempty" msgpack:"readOnly,omitempty" cbor:"readOnly,omitempty" bson:"readonly,omitempty" mapstructure:"readOnly,omitempty"`
 
 	// This attribute is secret.
-	Secret *string `json:"secret,omitempty" msgpack:"secret,omitempty" bson:"secret,omitempty" mapstructure:"secret,omitempty"`
+	Secret *string `json:"secret,omitempty" msgpack:"secret,omitempty" cbor:"secret,omitempty" bson:"secret,omitempty" mapstructure:"secret,omitempty"`
 
 	// this is a slice.
-	Slice *[]string `json:"slice,omitempty" msgpack:"slice,omitempty" bson:"slice,omitempty" mapstructure:"slice,omitempty"`
+	Slice *[]string `json:"slice,omitempty" msgpack:"slice,omitempty" cbor:"slice,omitempty" bson:"slice,omitempty" mapstructure:"slice,omitempty"`
 
 	// This attribute is not exposed.
-	Unexposed *string `json:"-" msgpack:"-" bson:"unexposed,omitempty" mapstructure:"-,omitempty"`
+	Unexposed *string `json:"-" msgpack:"-" cbor:"-" bson:"unexposed,omitempty" mapstructure:"-,omitempty"`
 
-	ModelVersion int `json:"-" msgpack:"-" bson:"_modelversion"`
+	ModelVersion int `json:"-" msgpack:"-" cbor:"-" bson:"_modelversion"`
 }
 
 // NewSparseList returns a new  SparseList.
@@ -5,6 +5,7 @@ package testmodel
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/mitchellh/copystructure"
 	"go.aporeto.io/elemental"
@@ -21,7 +22,7 @@ var RootIdentity = elemental.Identity{
 
 // Root represents the model of a root
 type Root struct {
-	ModelVersion int `json:"-" msgpack:"-" bson:"_modelversion"`
+	ModelVersion int `json:"-" msgpack:"-" cbor:"-" bson:"_modelversion"`
 }
 
 // NewRoot returns a new *Root
@@ -78,6 +79,28 @@ func (o *Root) UnmarshalBSON(raw []byte) error {
 	return nil
 }
 
+// MarshalProtobuf implements the elemental.ProtobufMarshaler interface.
+func (o *Root) MarshalProtobuf() ([]byte, error) {
+
+	e := elemental.NewProtobufEncoder()
+
+	return e.Bytes()
+}
+
+// UnmarshalProtobuf implements the elemental.ProtobufUnmarshaler interface.
+func (o *Root) UnmarshalProtobuf(data []byte) error {
+
+	d := elemental.NewProtobufDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		default:
+			d.Skip()
+		}
+	}
+
+	return d.Err()
+}
+
 // Version returns the hardcoded version of the model.
 func (o *Root) Version() int {
 
@@ -173,6 +196,11 @@ func (o *Root) ValueForAttribute(name string) any {
 	switch name {
 	}
 
+	// The name may be a dotted path to a nested value
+	if strings.Contains(name, ".") {
+		return elemental.ValueForPath(o, name)
+	}
+
 	return nil
 }
 
@@ -5,6 +5,7 @@ package testmodel
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/mitchellh/copystructure"
 	"go.aporeto.io/elemental"
@@ -96,27 +97,60 @@ func (o TasksList) Version() int {
 	return 1
 }
 
+// MarshalProtobuf implements the elemental.ProtobufMarshaler interface.
+func (o TasksList) MarshalProtobuf() ([]byte, error) {
+
+	e := elemental.NewProtobufEncoder()
+	for _, obj := range o {
+		if obj != nil {
+			e.EncodeMessage(1, obj)
+		}
+	}
+
+	return e.Bytes()
+}
+
+// UnmarshalProtobuf implements the elemental.ProtobufUnmarshaler interface.
+func (o *TasksList) UnmarshalProtobuf(data []byte) error {
+
+	*o = TasksList{}
+
+	d := elemental.NewProtobufDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		case 1:
+			obj := NewTask()
+			d.DecodeMessage(obj)
+			*o = append(*o, obj)
+		default:
+			d.Skip()
+		}
+	}
+
+	return d.Err()
+}
+
 // Task represents the model of a task
 type Task struct {
 	// The identifier.
-	ID string `json:"ID" msgpack:"ID" bson:"-" mapstructure:"ID,omitempty"`
+	ID string `json:"ID" msgpack:"ID" cbor:"ID" bson:"-" mapstructure:"ID,omitempty"`
 
 	// The description.
-	Description string `json:"description" msgpack:"description" bson:"description" mapstructure:"description,omitempty"`
+	Description string `json:"description" msgpack:"description" cbor:"description" bson:"description" mapstructure:"description,omitempty"`
 
 	// The name.
-	Name string `json:"name" msgpack:"name" bson:"name" mapstructure:"name,omitempty"`
+	Name string `json:"name" msgpack:"name" cbor:"name" bson:"name" mapstructure:"name,omitempty"`
 
 	// The identifier of the parent of the object.
-	ParentID string `json:"parentID" msgpack:"parentID" bson:"parentid" mapstructure:"parentID,omitempty"`
+	ParentID string `json:"parentID" msgpack:"parentID" cbor:"parentID" bson:"parentid" mapstructure:"parentID,omitempty"`
 
 	// The type of the parent of the object.
-	ParentType string `json:"parentType" msgpack:"parentType" bson:"parenttype" mapstructure:"parentType,omitempty"`
+	ParentType string `json:"parentType" msgpack:"parentType" cbor:"parentType" bson:"parenttype" mapstructure:"parentType,omitempty"`
 
 	// The status of the task.
-	Status TaskStatusValue `json:"status" msgpack:"status" bson:"status" mapstructure:"status,omitempty"`
+	Status TaskStatusValue `json:"status" msgpack:"status" cbor:"status" bson:"status" mapstructure:"status,omitempty"`
 
-	ModelVersion int `json:"-" msgpack:"-" bson:"_modelversion"`
+	ModelVersion int `json:"-" msgpack:"-" cbor:"-" bson:"_modelversion"`
 }
 
 // NewTask returns a new *Task
@@ -195,6 +229,71 @@ func (o *Task) UnmarshalBSON(raw []byte) error {
 	return nil
 }
 
+// MarshalProtobuf implements the elemental.ProtobufMarshaler interface.
+func (o *Task) MarshalProtobuf() ([]byte, error) {
+
+	e := elemental.NewProtobufEncoder()
+	e.EncodeString(1, o.ID)
+	e.EncodeString(2, o.Description)
+	e.EncodeString(3, o.Name)
+	e.EncodeString(4, o.ParentID)
+	e.EncodeString(5, o.ParentType)
+	switch o.Status {
+	case TaskStatusDONE:
+		e.EncodeInt(6, 1)
+	case TaskStatusPROGRESS:
+		e.EncodeInt(6, 2)
+	case TaskStatusTODO:
+		e.EncodeInt(6, 3)
+	default:
+		e.EncodeInt(6, 0)
+	}
+
+	return e.Bytes()
+}
+
+// UnmarshalProtobuf implements the elemental.ProtobufUnmarshaler interface.
+func (o *Task) UnmarshalProtobuf(data []byte) error {
+
+	o.ID = ""
+	o.Description = ""
+	o.Name = ""
+	o.ParentID = ""
+	o.ParentType = ""
+	o.Status = ""
+
+	d := elemental.NewProtobufDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		case 1:
+			o.ID = d.DecodeString()
+		case 2:
+			o.Description = d.DecodeString()
+		case 3:
+			o.Name = d.DecodeString()
+		case 4:
+			o.ParentID = d.DecodeString()
+		case 5:
+			o.ParentType = d.DecodeString()
+		case 6:
+			switch d.DecodeInt() {
+			case 1:
+				o.Status = TaskStatusDONE
+			case 2:
+				o.Status = TaskStatusPROGRESS
+			case 3:
+				o.Status = TaskStatusTODO
+			default:
+				o.Status = ""
+			}
+		default:
+			d.Skip()
+		}
+	}
+
+	return d.Err()
+}
+
 // Version returns the hardcoded version of the model.
 func (o *Task) Version() int {
 
@@ -386,6 +485,11 @@ func (o *Task) ValueForAttribute(name string) any {
 		return o.Status
 	}
 
+	// The name may be a dotted path to a nested value
+	if strings.Contains(name, ".") {
+		return elemental.ValueForPath(o, name)
+	}
+
 	return nil
 }
 
@@ -631,24 +735,24 @@ func (o SparseTasksList) Version() int {
 // SparseTask represents the sparse version of a task.
 type SparseTask struct {
 	// The identifier.
-	ID *string `json:"ID,omitempty" msgpack:"ID,omitempty" bson:"-" mapstructure:"ID,omitempty"`
+	ID *string `json:"ID,omitempty" msgpack:"ID,omitempty" cbor:"ID,omitempty" bson:"-" mapstructure:"ID,omitempty"`
 
 	// The description.
-	Description *string `json:"description,omitempty" msgpack:"description,omitempty" bson:"description,omitempty" mapstructure:"description,omitempty"`
+	Description *string `json:"description,omitempty" msgpack:"description,omitempty" cbor:"description,omitempty" bson:"description,omitempty" mapstructure:"description,omitempty"`
 
 	// The name.
-	Name *string `json:"name,omitempty" msgpack:"name,omitempty" bson:"name,omitempty" mapstructure:"name,omitempty"`
+	Name *string `json:"name,omitempty" msgpack:"name,omitempty" cbor:"name,omitempty" bson:"name,omitempty" mapstructure:"name,omitempty"`
 
 	// The identifier of the parent of the object.
-	ParentID *string `json:"parentID,omitempty" msgpack:"parentID,omitempty" bson:"parentid,omitempty" mapstructure:"parentID,omitempty"`
+	ParentID *string `json:"parentID,omitempty" msgpack:"parentID,omitempty" cbor:"parentID,omitempty" bson:"parentid,omitempty" mapstructure:"parentID,omitempty"`
 
 	// The type of the parent of the object.
-	ParentType *string `json:"parentType,omitempty" msgpack:"parentType,omitempty" bson:"parenttype,omitempty" mapstructure:"parentType,omitempty"`
+	ParentType *string `json:"parentType,omitempty" msgpack:"parentType,omitempty" cbor:"parentType,omitempty" bson:"parenttype,omitempty" mapstructure:"parentType,omitempty"`
 
 	// The status of the task.
-	Status *TaskStatusValue `json:"status,omitempty" msgpack:"status,omitempty" bson:"status,omitempty" mapstructure:"status,omitempty"`
+	Status *TaskStatusValue `json:"status,omitempty" msgpack:"status,omitempty" cbor:"status,omitempty" bson:"status,omitempty" mapstructure:"status,omitempty"`
 
-	ModelVersion int `json:"-" msgpack:"-" bson:"_modelversion"`
+	ModelVersion int `json:"-" msgpack:"-" cbor:"-" bson:"_modelversion"`
 }
 
 // NewSparseTask returns a new  SparseTask.
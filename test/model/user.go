@@ -5,6 +5,7 @@ package testmodel
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/mitchellh/copystructure"
 	"go.aporeto.io/elemental"
@@ -82,30 +83,63 @@ func (o UsersList) Version() int {
 	return 1
 }
 
+// MarshalProtobuf implements the elemental.ProtobufMarshaler interface.
+func (o UsersList) MarshalProtobuf() ([]byte, error) {
+
+	e := elemental.NewProtobufEncoder()
+	for _, obj := range o {
+		if obj != nil {
+			e.EncodeMessage(1, obj)
+		}
+	}
+
+	return e.Bytes()
+}
+
+// UnmarshalProtobuf implements the elemental.ProtobufUnmarshaler interface.
+func (o *UsersList) UnmarshalProtobuf(data []byte) error {
+
+	*o = UsersList{}
+
+	d := elemental.NewProtobufDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		case 1:
+			obj := NewUser()
+			d.DecodeMessage(obj)
+			*o = append(*o, obj)
+		default:
+			d.Skip()
+		}
+	}
+
+	return d.Err()
+}
+
 // User represents the model of a user
 type User struct {
 	// The identifier.
-	ID string `json:"ID" msgpack:"ID" bson:"-" mapstructure:"ID,omitempty"`
+	ID string `json:"ID" msgpack:"ID" cbor:"ID" bson:"-" mapstructure:"ID,omitempty"`
 
 	// the object is archived and not deleted.
-	Archived bool `json:"archived" msgpack:"archived" bson:"archived" mapstructure:"archived,omitempty"`
+	Archived bool `json:"archived" msgpack:"archived" cbor:"archived" bson:"archived" mapstructure:"archived,omitempty"`
 
 	// The first name.
-	FirstName string `json:"firstName" msgpack:"firstName" bson:"firstname" mapstructure:"firstName,omitempty"`
+	FirstName string `json:"firstName" msgpack:"firstName" cbor:"firstName" bson:"firstname" mapstructure:"firstName,omitempty"`
 
 	// The last name.
-	LastName string `json:"lastName" msgpack:"lastName" bson:"lastname" mapstructure:"lastName,omitempty"`
+	LastName string `json:"lastName" msgpack:"lastName" cbor:"lastName" bson:"lastname" mapstructure:"lastName,omitempty"`
 
 	// The identifier of the parent of the object.
-	ParentID string `json:"parentID" msgpack:"parentID" bson:"parentid" mapstructure:"parentID,omitempty"`
+	ParentID string `json:"parentID" msgpack:"parentID" cbor:"parentID" bson:"parentid" mapstructure:"parentID,omitempty"`
 
 	// The type of the parent of the object.
-	ParentType string `json:"parentType" msgpack:"parentType" bson:"parenttype" mapstructure:"parentType,omitempty"`
+	ParentType string `json:"parentType" msgpack:"parentType" cbor:"parentType" bson:"parenttype" mapstructure:"parentType,omitempty"`
 
 	// the login.
-	UserName string `json:"userName" msgpack:"userName" bson:"username" mapstructure:"userName,omitempty"`
+	UserName string `json:"userName" msgpack:"userName" cbor:"userName" bson:"username" mapstructure:"userName,omitempty"`
 
-	ModelVersion int `json:"-" msgpack:"-" bson:"_modelversion"`
+	ModelVersion int `json:"-" msgpack:"-" cbor:"-" bson:"_modelversion"`
 }
 
 // NewUser returns a new *User
@@ -185,6 +219,57 @@ func (o *User) UnmarshalBSON(raw []byte) error {
 	return nil
 }
 
+// MarshalProtobuf implements the elemental.ProtobufMarshaler interface.
+func (o *User) MarshalProtobuf() ([]byte, error) {
+
+	e := elemental.NewProtobufEncoder()
+	e.EncodeString(1, o.ID)
+	e.EncodeBool(2, o.Archived)
+	e.EncodeString(3, o.FirstName)
+	e.EncodeString(4, o.LastName)
+	e.EncodeString(5, o.ParentID)
+	e.EncodeString(6, o.ParentType)
+	e.EncodeString(7, o.UserName)
+
+	return e.Bytes()
+}
+
+// UnmarshalProtobuf implements the elemental.ProtobufUnmarshaler interface.
+func (o *User) UnmarshalProtobuf(data []byte) error {
+
+	o.ID = ""
+	o.Archived = false
+	o.FirstName = ""
+	o.LastName = ""
+	o.ParentID = ""
+	o.ParentType = ""
+	o.UserName = ""
+
+	d := elemental.NewProtobufDecoder(data)
+	for d.Next() {
+		switch d.Field() {
+		case 1:
+			o.ID = d.DecodeString()
+		case 2:
+			o.Archived = d.DecodeBool()
+		case 3:
+			o.FirstName = d.DecodeString()
+		case 4:
+			o.LastName = d.DecodeString()
+		case 5:
+			o.ParentID = d.DecodeString()
+		case 6:
+			o.ParentType = d.DecodeString()
+		case 7:
+			o.UserName = d.DecodeString()
+		default:
+			d.Skip()
+		}
+	}
+
+	return d.Err()
+}
+
 // Version returns the hardcoded version of the model.
 func (o *User) Version() int {
 
@@ -390,6 +475,11 @@ func (o *User) ValueForAttribute(name string) any {
 		return o.UserName
 	}
 
+	// The name may be a dotted path to a nested value
+	if strings.Contains(name, ".") {
+		return elemental.ValueForPath(o, name)
+	}
+
 	return nil
 }
 
@@ -657,27 +747,27 @@ func (o SparseUsersList) Version() int {
 // SparseUser represents the sparse version of a user.
 type SparseUser struct {
 	// The identifier.
-	ID *string `json:"ID,omitempty" msgpack:"ID,omitempty" bson:"-" mapstructure:"ID,omitempty"`
+	ID *string `json:"ID,omitempty" msgpack:"ID,omitempty" cbor:"ID,omitempty" bson:"-" mapstructure:"ID,omitempty"`
 
 	// the object is archived and not deleted.
-	Archived *bool `json:"archived,omitempty" msgpack:"archived,omitempty" bson:"archived,omitempty" mapstructure:"archived,omitempty"`
+	Archived *bool `json:"archived,omitempty" msgpack:"archived,omitempty" cbor:"archived,omitempty" bson:"archived,omitempty" mapstructure:"archived,omitempty"`
 
 	// The first name.
-	FirstName *string `json:"firstName,omitempty" msgpack:"firstName,omitempty" bson:"firstname,omitempty" mapstructure:"firstName,omitempty"`
+	FirstName *string `json:"firstName,omitempty" msgpack:"firstName,omitempty" cbor:"firstName,omitempty" bson:"firstname,omitempty" mapstructure:"firstName,omitempty"`
 
 	// The last name.
-	LastName *string `json:"lastName,omitempty" msgpack:"lastName,omitempty" bson:"lastname,omitempty" mapstructure:"lastName,omitempty"`
+	LastName *string `json:"lastName,omitempty" msgpack:"lastName,omitempty" cbor:"lastName,omitempty" bson:"lastname,omitempty" mapstructure:"lastName,omitempty"`
 
 	// The identifier of the parent of the object.
-	ParentID *string `json:"parentID,omitempty" msgpack:"parentID,omitempty" bson:"parentid,omitempty" mapstructure:"parentID,omitempty"`
+	ParentID *string `json:"parentID,omitempty" msgpack:"parentID,omitempty" cbor:"parentID,omitempty" bson:"parentid,omitempty" mapstructure:"parentID,omitempty"`
 
 	// The type of the parent of the object.
-	ParentType *string `json:"parentType,omitempty" msgpack:"parentType,omitempty" bson:"parenttype,omitempty" mapstructure:"parentType,omitempty"`
+	ParentType *string `json:"parentType,omitempty" msgpack:"parentType,omitempty" cbor:"parentType,omitempty" bson:"parenttype,omitempty" mapstructure:"parentType,omitempty"`
 
 	// the login.
-	UserName *string `json:"userName,omitempty" msgpack:"userName,omitempty" bson:"username,omitempty" mapstructure:"userName,omitempty"`
+	UserName *string `json:"userName,omitempty" msgpack:"userName,omitempty" cbor:"userName,omitempty" bson:"username,omitempty" mapstructure:"userName,omitempty"`
 
-	ModelVersion int `json:"-" msgpack:"-" bson:"_modelversion"`
+	ModelVersion int `json:"-" msgpack:"-" cbor:"-" bson:"_modelversion"`
 }
 
 // NewSparseUser returns a new  SparseUser.